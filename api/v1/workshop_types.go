@@ -17,6 +17,7 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -41,22 +42,37 @@ type UserSpec struct {
 
 // SourceSpec ...
 type SourceSpec struct {
+	// URL of the Git repository, or of a tarball, of the workshop
 	GitURL    string `json:"gitURL"`
 	GitBranch string `json:"gitBranch"`
+	// Server of the source: github, gitlab, gitea, tarball or configmap, detected from the URL by default
+	Provider string `json:"provider,omitempty"`
+	// Path of the devfile in the source, defaults to devfile.yaml
+	DevfilePath string `json:"devfilePath,omitempty"`
+	// Secret of the workshop namespace whose token key authenticates the requests to a private source
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+	// ConfigMap of the workshop namespace holding the source files, keyed by their file name
+	ConfigMapName string `json:"configMapName,omitempty"`
 }
 
 // InfrastructureSpec ...
 type InfrastructureSpec struct {
 	CertManager        CertManagerSpec        `json:"certManager,omitempty"`
 	CodeReadyWorkspace CodeReadyWorkspaceSpec `json:"codeReadyWorkspace,omitempty"`
+	DevSpaces          DevSpacesSpec          `json:"devSpaces,omitempty"`
+	Git                GitSpec                `json:"git,omitempty"`
 	Gitea              GiteaSpec              `json:"gitea,omitempty"`
 	GitOps             GitOpsSpec             `json:"gitops,omitempty"`
 	Guide              GuideSpec              `json:"guide,omitempty"`
+	ImagePuller        ImagePullerSpec        `json:"imagePuller,omitempty"`
+	Kafka              KafkaSpec              `json:"kafka,omitempty"`
 	Nexus              NexusSpec              `json:"nexus,omitempty"`
 	Pipeline           PipelineSpec           `json:"pipeline,omitempty"`
+	Portal             PortalSpec             `json:"portal,omitempty"`
 	Project            ProjectSpec            `json:"project,omitempty"`
 	ServiceMesh        ServiceMeshSpec        `json:"serviceMesh,omitempty"`
 	Serverless         ServerlessSpec         `json:"serverless,omitempty"`
+	SSO                SSOSpec                `json:"sso,omitempty"`
 	Vault              VaultSpec              `json:"vault,omitempty"`
 }
 
@@ -72,16 +88,157 @@ type CertManagerSpec struct {
 	OperatorHub OperatorHubSpec `json:"operatorHub"`
 }
 
+// GitSpec ...
+type GitSpec struct {
+	// Provider of the attendee repositories: gitea (default) uses the gitea component, gitlab and github use an external server
+	Provider string `json:"provider,omitempty"`
+	// URL of an external server, defaults to https://github.com for github
+	URL string `json:"url,omitempty"`
+	// Secret of the workshop namespace holding the admin token of an external server in its token key
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+	// GitHub organization receiving the repositories of the users, as GitHub can not import into user accounts
+	Organization string `json:"organization,omitempty"`
+	// GitHub logins of the workshop users by username, required on github.com where the users are existing
+	// accounts invited into the organization, GitHub Enterprise Server accounts being named after the users
+	Logins map[string]string `json:"logins,omitempty"`
+	// Delete the accounts created by the operator on an external server when the number of users is lowered,
+	// they are kept by default
+	DeleteUsers  bool                `json:"deleteUsers,omitempty"`
+	Repositories GitRepositoriesSpec `json:"repositories,omitempty"`
+	Webhooks     []GitWebhookSpec    `json:"webhooks,omitempty"`
+}
+
+// GitRepositoriesSpec ...
+type GitRepositoriesSpec struct {
+	// Migrate the workshop source repository into the account of every user
+	MirrorSource bool `json:"mirrorSource,omitempty"`
+	// Default branch of the migrated repositories, defaults to the source branch
+	Branch string `json:"branch,omitempty"`
+	// Make the migrated repositories private
+	Private bool                `json:"private,omitempty"`
+	Extra   []GitRepositorySpec `json:"extra,omitempty"`
+}
+
+// GitRepositorySpec ...
+type GitRepositorySpec struct {
+	URL string `json:"url"`
+	// Name of the repository, defaults to the last path element of the URL
+	Name   string `json:"name,omitempty"`
+	Branch string `json:"branch,omitempty"`
+}
+
+// GitWebhookSpec ...
+type GitWebhookSpec struct {
+	// URL receiving the events, %USERNAME% is replaced by the name of the user owning the repository
+	URL string `json:"url"`
+	// Content type of the payload, json or form, defaults to json
+	ContentType string   `json:"contentType,omitempty"`
+	Secret      string   `json:"secret,omitempty"`
+	Events      []string `json:"events,omitempty"`
+}
+
 // GiteaSpec ...
 type GiteaSpec struct {
-	Enabled bool      `json:"enabled"`
-	Image   ImageSpec `json:"image"`
+	Enabled bool `json:"enabled"`
+	// Deployment mode of Gitea, operator (default) installs the Gitea Ansible operator, native deploys Gitea directly
+	Mode string `json:"mode,omitempty"`
+	// Image of the Gitea Ansible operator
+	Image        ImageSpec             `json:"image,omitempty"`
+	Server       GiteaServerSpec       `json:"server,omitempty"`
+	Organization GiteaOrganizationSpec `json:"organization,omitempty"`
+}
+
+// GiteaOrganizationSpec ...
+type GiteaOrganizationSpec struct {
+	Enabled bool `json:"enabled"`
+	// Name of the organization, defaults to the name of the workshop
+	Name string `json:"name,omitempty"`
+	// Gitea users of the instructors team, created with the workshop password if missing
+	Instructors []string `json:"instructors,omitempty"`
+}
+
+// GiteaServerSpec ...
+type GiteaServerSpec struct {
+	// Image of the Gitea server, changing its tag upgrades Gitea in place
+	Image            ImageSpec                   `json:"image,omitempty"`
+	StorageSize      string                      `json:"storageSize,omitempty"`
+	StorageClassName string                      `json:"storageClassName,omitempty"`
+	Resources        corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Secret with the username and password of the admin used by the operator, generated when not set
+	AdminSecretName string            `json:"adminSecretName,omitempty"`
+	SSH             GiteaSSHSpec      `json:"ssh,omitempty"`
+	Database        GiteaDatabaseSpec `json:"database,omitempty"`
+	// app.ini settings by section, applied over the generated ones
+	Config map[string]GiteaConfigSection `json:"config,omitempty"`
+}
+
+// GiteaConfigSection holds the settings of an app.ini section
+type GiteaConfigSection map[string]string
+
+// GiteaSSHSpec ...
+type GiteaSSHSpec struct {
+	Enabled     bool               `json:"enabled"`
+	Port        int32              `json:"port,omitempty"`
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
+}
+
+// GiteaDatabaseSpec ...
+type GiteaDatabaseSpec struct {
+	Image            ImageSpec                   `json:"image,omitempty"`
+	StorageSize      string                      `json:"storageSize,omitempty"`
+	StorageClassName string                      `json:"storageClassName,omitempty"`
+	Resources        corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 // GitOpsSpec ...
 type GitOpsSpec struct {
 	Enabled     bool            `json:"enabled"`
 	OperatorHub OperatorHubSpec `json:"operatorHub"`
+	// Mode of the Argo CD installation: shared (default) installs one Argo CD in the argocd namespace, isolating
+	// the users with policies, perUser one Argo CD in the staging project of every user
+	Mode string `json:"mode,omitempty"`
+	// Argo CD Applications created for every user in its AppProject
+	Applications []GitOpsApplicationSpec `json:"applications,omitempty"`
+	// Argo CD ApplicationSets deploying a shared application for every user, the users being the elements of
+	// their list generator. Only supported by the shared Argo CD.
+	ApplicationSets []GitOpsApplicationSetSpec `json:"applicationSets,omitempty"`
+}
+
+// GitOpsApplicationSetSpec is an application deployed for every user, %USERNAME% being replaced by its username
+type GitOpsApplicationSetSpec struct {
+	// Name of the ApplicationSet, its Applications being prefixed by the username
+	Name string `json:"name"`
+	// URL of the repository, defaults to the workshop source
+	RepoURL string `json:"repoURL,omitempty"`
+	Path    string `json:"path,omitempty"`
+	// Revision of the repository, defaults to the branch of the workshop source
+	TargetRevision string `json:"targetRevision,omitempty"`
+	// Namespace the Applications are deployed to, defaults to the staging project of the user
+	Namespace  string                      `json:"namespace,omitempty"`
+	SyncPolicy GitOpsApplicationSyncPolicy `json:"syncPolicy,omitempty"`
+}
+
+// GitOpsApplicationSpec is the template of an Argo CD Application of a user, %USERNAME% being replaced by its username
+type GitOpsApplicationSpec struct {
+	// Name of the Application, prefixed by the username
+	Name string `json:"name"`
+	// Git repository of the user the Application is synced from, defaults to the first repository imported for
+	// the users
+	Repository string `json:"repository,omitempty"`
+	// URL of the repository, overriding the repository of the user
+	RepoURL        string `json:"repoURL,omitempty"`
+	Path           string `json:"path,omitempty"`
+	TargetRevision string `json:"targetRevision,omitempty"`
+	// Namespace the Application is deployed to, defaults to the staging project of the user
+	Namespace  string                      `json:"namespace,omitempty"`
+	SyncPolicy GitOpsApplicationSyncPolicy `json:"syncPolicy,omitempty"`
+}
+
+// GitOpsApplicationSyncPolicy ...
+type GitOpsApplicationSyncPolicy struct {
+	Automated bool `json:"automated,omitempty"`
+	Prune     bool `json:"prune,omitempty"`
+	SelfHeal  bool `json:"selfHeal,omitempty"`
 }
 
 // GuideSpec ...
@@ -90,10 +247,121 @@ type GuideSpec struct {
 	Scholars ScholarsSpec `json:"scholars,omitempty"`
 }
 
+// ImagePullerSpec ...
+type ImagePullerSpec struct {
+	Enabled bool `json:"enabled"`
+	// Images pulled on every node besides the ones of the devfile, the Bookbag and the plugin registry
+	Images []string `json:"images,omitempty"`
+	// Image holding the static sleep binary run by the pulled images, defaults to busybox
+	Image ImageSpec `json:"image,omitempty"`
+	// Node selector of the nodes pulling the images, defaults to all the schedulable nodes
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Pull secrets of the private registries of the images, copied from the namespace of the Workshop
+	ImagePullSecrets []string `json:"imagePullSecrets,omitempty"`
+}
+
+// KafkaSpec configures the Kafka clusters of the event-driven labs
+type KafkaSpec struct {
+	Enabled bool `json:"enabled"`
+	// Operator installing Kafka: amq-streams (default) or strimzi
+	Operator    string          `json:"operator,omitempty"`
+	OperatorHub OperatorHubSpec `json:"operatorHub"`
+	// Mode of the clusters: shared (default), one cluster in the kafka namespace, or perUser, one cluster in the
+	// staging project of every user
+	Mode string `json:"mode,omitempty"`
+	// Version of Kafka, defaults to the one of the operator. The clusters run in KRaft mode, supported from AMQ Streams
+	// 2.7 and Strimzi 0.40
+	Version string `json:"version,omitempty"`
+	// Number of nodes of every cluster, each being both a KRaft controller and a broker, defaults to 1
+	Replicas int32 `json:"replicas,omitempty"`
+	// Size of the persistent volumes of the nodes, ephemeral storage being used when unset
+	StorageSize string `json:"storageSize,omitempty"`
+	// Topics created for every user
+	Topics []KafkaTopicSpec `json:"topics,omitempty"`
+}
+
+// KafkaTopicSpec ...
+type KafkaTopicSpec struct {
+	// Name of the topic, prefixed by the username in the shared cluster
+	Name string `json:"name"`
+	// Number of partitions, defaults to 1
+	Partitions int32 `json:"partitions,omitempty"`
+	// Replication factor, defaults to the number of brokers
+	Replicas int32 `json:"replicas,omitempty"`
+}
+
 // NexusSpec ...
 type NexusSpec struct {
-	Enabled bool      `json:"enabled"`
-	Image   ImageSpec `json:"image"`
+	Enabled bool `json:"enabled"`
+	// Image of the Nexus Ansible operator
+	Image  ImageSpec       `json:"image"`
+	Server NexusServerSpec `json:"server,omitempty"`
+	// Repositories of the server, the maven-central, redhat-ga, jboss, releases, docker and npm repositories by default
+	Repositories []NexusRepositorySpec `json:"repositories,omitempty"`
+	WarmUp       NexusWarmUpSpec       `json:"warmUp,omitempty"`
+}
+
+// NexusWarmUpSpec ...
+type NexusWarmUpSpec struct {
+	Enabled bool `json:"enabled"`
+	// Image of the warm-up Job, with Maven, npm, git and curl, defaults to quay.io/devfile/universal-developer-image:ubi8-38da5c2
+	Image ImageSpec `json:"image,omitempty"`
+	// Maven artifacts to cache with their dependencies, as groupId:artifactId:version[:packaging[:classifier]]
+	Maven []string `json:"maven,omitempty"`
+	// npm packages to cache with their dependencies, as name@version
+	Npm []string `json:"npm,omitempty"`
+	// Directory of the workshop source repository whose pom.xml and package.json dependencies are cached
+	SourcePath string         `json:"sourcePath,omitempty"`
+	Cache      NexusCacheSpec `json:"cache,omitempty"`
+}
+
+// NexusCacheSpec ...
+type NexusCacheSpec struct {
+	// Name of the PersistentVolumeClaim of the nexus namespace holding the cache, created unless it exists, its
+	// volume being retained once the namespace is deleted
+	ClaimName string `json:"claimName,omitempty"`
+	// Size of the created claim, defaults to 5Gi
+	VolumeSize string `json:"volumeSize,omitempty"`
+	// Copy the cached Maven artifacts and npm packages to the claim
+	Export bool `json:"export,omitempty"`
+	// Upload the Maven artifacts and npm packages of the claim to the hosted repositories before warming up,
+	// for disconnected setups
+	Import bool `json:"import,omitempty"`
+}
+
+// NexusServerSpec ...
+type NexusServerSpec struct {
+	// Image of the Nexus server, defaults to docker.io/sonatype/nexus3:3.68.1. The REST API of the repositories and
+	// of the users requires Nexus 3.30 or later.
+	Image         ImageSpec `json:"image,omitempty"`
+	VolumeSize    string    `json:"volumeSize,omitempty"`
+	CPURequest    int       `json:"cpuRequest,omitempty"`
+	CPULimit      int       `json:"cpuLimit,omitempty"`
+	MemoryRequest string    `json:"memoryRequest,omitempty"`
+	MemoryLimit   string    `json:"memoryLimit,omitempty"`
+}
+
+// NexusRepositorySpec ...
+type NexusRepositorySpec struct {
+	Name string `json:"name"`
+	// Format of the repository: maven2, npm, docker, pypi or go
+	Format string `json:"format"`
+	// Type of the repository: proxy, hosted or group
+	Type string `json:"type"`
+	// Remote URL of a proxy repository
+	RemoteURL string `json:"remoteURL,omitempty"`
+	// Members of a group repository
+	MemberRepos []string `json:"memberRepos,omitempty"`
+	// Layout policy of a maven2 repository, strict or permissive
+	LayoutPolicy string `json:"layoutPolicy,omitempty"`
+	// Version policy of a maven2 repository, release, snapshot or mixed
+	VersionPolicy string `json:"versionPolicy,omitempty"`
+	// Write policy of a hosted repository, allow, allow_once or deny
+	WritePolicy string `json:"writePolicy,omitempty"`
+	// HTTP connector port of a docker repository
+	HTTPPort int `json:"httpPort,omitempty"`
+	// Docker registry API v1 support of a docker repository
+	V1Enabled bool `json:"v1Enabled,omitempty"`
 }
 
 // PipelineSpec ...
@@ -102,6 +370,12 @@ type PipelineSpec struct {
 	OperatorHub OperatorHubSpec `json:"operatorHub"`
 }
 
+// PortalSpec ...
+type PortalSpec struct {
+	Image ImageSpec `json:"image,omitempty"`
+	Title string    `json:"title,omitempty"`
+}
+
 // ProjectSpec ...
 type ProjectSpec struct {
 	Enabled     bool   `json:"enabled"`
@@ -121,20 +395,137 @@ type ServiceMeshSpec struct {
 	ElasticSearchOperatorHub OperatorHubSpec `json:"elasticSearchOperatorHub"`
 	JaegerOperatorHub        OperatorHubSpec `json:"jaegerOperatorHub"`
 	KialiOperatorHub         OperatorHubSpec `json:"kialiOperatorHub"`
+	// Provider of the mesh: maistra (default), OpenShift Service Mesh installed with OLM, or istio, upstream Istio
+	// installed by istioctl with Kiali, Jaeger and Prometheus
+	Provider string    `json:"provider,omitempty"`
+	Istio    IstioSpec `json:"istio,omitempty"`
+	// Tenancy of the control plane: shared (default), in istio-system, or perUser, in the userN-istio namespace of every user
+	Tenancy string `json:"tenancy,omitempty"`
+	// ControlPlane configures the ServiceMeshControlPlane of istio-system, or of every user
+	ControlPlane ServiceMeshControlPlaneSpec `json:"controlPlane,omitempty"`
+}
+
+// IstioSpec configures the upstream Istio of the istio provider
+type IstioSpec struct {
+	// Hub of the images of istioctl and of the components, defaults to docker.io/istio
+	Hub string `json:"hub,omitempty"`
+	// Version of Istio, defaults to 1.28.1
+	Version string `json:"version,omitempty"`
+}
+
+// ServiceMeshControlPlaneSpec ...
+type ServiceMeshControlPlaneSpec struct {
+	// Version of the control plane, defaults to v2.0. Not used by the istio provider
+	Version string `json:"version,omitempty"`
+	// Percentage of the requests traced, in hundredths of a percent from 0 to 10000, defaults to 10000
+	TracingSampling *int32                `json:"tracingSampling,omitempty"`
+	Addons          ServiceMeshAddonsSpec `json:"addons,omitempty"`
+	// Replicas of the ingress gateway, defaults to the one of the control plane profile
+	IngressGatewayReplicas *int32 `json:"ingressGatewayReplicas,omitempty"`
+	// Mutual TLS between the services of the mesh: permissive (default) or strict
+	// +kubebuilder:validation:Enum=permissive;strict
+	MTLSMode string `json:"mtlsMode,omitempty"`
+	// Namespaces joining the mesh in addition to the staging projects of the users, %USERNAME% being replaced by
+	// the user with the perUser tenancy
+	Members []string `json:"members,omitempty"`
+}
+
+// ServiceMeshAddonsSpec enables the addons of the control plane, all of them being enabled by default
+type ServiceMeshAddonsSpec struct {
+	Kiali   *bool `json:"kiali,omitempty"`
+	Grafana *bool `json:"grafana,omitempty"`
+	Jaeger  *bool `json:"jaeger,omitempty"`
 }
 
 // ServerlessSpec ...
 type ServerlessSpec struct {
-	Enabled     bool            `json:"enabled"`
-	OperatorHub OperatorHubSpec `json:"operatorHub"`
+	Enabled     bool                `json:"enabled"`
+	OperatorHub OperatorHubSpec     `json:"operatorHub"`
+	Serving     KnativeServingSpec  `json:"serving,omitempty"`
+	Eventing    KnativeEventingSpec `json:"eventing,omitempty"`
+}
+
+// KnativeServingSpec configures the KnativeServing of knative-serving
+type KnativeServingSpec struct {
+	// Domain of the routes of the Knative Services, defaults to the one of the cluster
+	Domain      string                 `json:"domain,omitempty"`
+	Autoscaling KnativeAutoscalingSpec `json:"autoscaling,omitempty"`
+}
+
+// KnativeAutoscalingSpec are the autoscaling defaults of the Knative Services, the ones of Knative being kept
+// when unset
+type KnativeAutoscalingSpec struct {
+	// Concurrent requests per replica targeted by the autoscaler
+	ContainerConcurrencyTarget *int32 `json:"containerConcurrencyTarget,omitempty"`
+	// Scale the Knative Services down to zero replicas when they receive no request
+	ScaleToZero *bool `json:"scaleToZero,omitempty"`
+	// Delay before the last replica is removed, e.g. 30s
+	ScaleToZeroGracePeriod string `json:"scaleToZeroGracePeriod,omitempty"`
+	// Maximum number of replicas of a revision
+	MaxScale *int32 `json:"maxScale,omitempty"`
+}
+
+// KnativeEventingSpec configures the KnativeEventing of knative-eventing
+type KnativeEventingSpec struct {
+	// Class of the Brokers, defaults to MTChannelBasedBroker
+	BrokerClass string `json:"brokerClass,omitempty"`
+	// Names of the Brokers created in the staging project of every user
+	Brokers []string `json:"brokers,omitempty"`
 }
 
 // CodeReadyWorkspaceSpec ...
 type CodeReadyWorkspaceSpec struct {
-	Enabled             bool            `json:"enabled"`
-	OperatorHub         OperatorHubSpec `json:"operatorHub"`
-	OpenshiftOAuth      bool            `json:"openshiftOAuth"`
-	PluginRegistryImage ImageSpec       `json:"pluginRegistryImage,omitempty"`
+	Enabled             bool                         `json:"enabled"`
+	OperatorHub         OperatorHubSpec              `json:"operatorHub"`
+	OpenshiftOAuth      bool                         `json:"openshiftOAuth"`
+	PluginRegistryImage ImageSpec                    `json:"pluginRegistryImage,omitempty"`
+	Server              CodeReadyWorkspaceServerSpec `json:"server,omitempty"`
+}
+
+// CodeReadyWorkspaceServerSpec ...
+type CodeReadyWorkspaceServerSpec struct {
+	// Che properties of the server, overriding the ones set from the other fields
+	CustomCheProperties map[string]string `json:"customCheProperties,omitempty"`
+	// Namespace of the workspaces of a user, defaults to <username>-workspace
+	WorkspaceNamespace string `json:"workspaceNamespace,omitempty"`
+	// Workspaces a user can run at once, defaults to 2
+	RunningWorkspacesLimit int `json:"runningWorkspacesLimit,omitempty"`
+	// Idle timeout of the workspaces in milliseconds, defaults to 0 which never stops them
+	IdleTimeout int `json:"idleTimeout,omitempty"`
+	// Serve CodeReady Workspaces over HTTP instead of HTTPS
+	DisableTLS bool                          `json:"disableTLS,omitempty"`
+	Storage    CodeReadyWorkspaceStorageSpec `json:"storage,omitempty"`
+	// Image of the devfile registry, the one of the operator by default
+	DevfileRegistryImage ImageSpec `json:"devfileRegistryImage,omitempty"`
+	// Image of the plugin registry, overriding pluginRegistryImage
+	PluginRegistryImage ImageSpec `json:"pluginRegistryImage,omitempty"`
+	// Secret of the workshop namespace with the username and password keys of the Keycloak admin, defaults to
+	// the codeready-keycloak-admin Secret with a generated password. Keycloak reads it when it is installed only.
+	IdentityProviderAdminSecretName string `json:"identityProviderAdminSecretName,omitempty"`
+}
+
+// CodeReadyWorkspaceStorageSpec ...
+type CodeReadyWorkspaceStorageSpec struct {
+	// Claims of the workspaces: common, per-user or per-workspace, defaults to per-workspace
+	Strategy string `json:"strategy,omitempty"`
+	// Size of the claims, defaults to 1Gi
+	ClaimSize string `json:"claimSize,omitempty"`
+	// Storage class of the claims, the default one unless set
+	StorageClassName string `json:"storageClassName,omitempty"`
+}
+
+// DevSpacesSpec ...
+type DevSpacesSpec struct {
+	Enabled bool `json:"enabled"`
+	// Flavor of the IDE: devspaces (default) installs OpenShift Dev Spaces, devworkspace only the DevWorkspace operator
+	Flavor      string          `json:"flavor,omitempty"`
+	OperatorHub OperatorHubSpec `json:"operatorHub"`
+	// Namespace of the workspace of a user, defaults to <username>-devspaces
+	WorkspaceNamespace string `json:"workspaceNamespace,omitempty"`
+	// Path of the devfile v2 in the workshop source, defaults to the devfile path of the source
+	DevfilePath string `json:"devfilePath,omitempty"`
+	// Start the workspaces once created
+	Started bool `json:"started,omitempty"`
 }
 
 // OperatorHubSpec ...
@@ -149,6 +540,14 @@ type ImageSpec struct {
 	Tag  string `json:"tag"`
 }
 
+// SSOSpec ...
+type SSOSpec struct {
+	// Log users in to Gitea, Nexus and Argo CD with their OpenShift identity instead of local accounts
+	Enabled bool `json:"enabled"`
+	// Image of the OpenShift OAuth proxy put in front of Gitea and Nexus
+	ProxyImage ImageSpec `json:"proxyImage,omitempty"`
+}
+
 // VaultSpec ...
 type VaultSpec struct {
 	Enabled            bool      `json:"enabled"`
@@ -161,11 +560,15 @@ type WorkshopStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
+	// The components below are Installed once enabled, and deleted with the workshop even if disabled later
+
 	Bookbag              string `json:"bookbag"`
 	CertManager          string `json:"certManager"`
 	CodeReadyWorkspace   string `json:"codeReadyWorkspace"`
+	DevSpaces            string `json:"devSpaces,omitempty"`
 	Gitea                string `json:"gitea"`
 	GitOps               string `json:"gitops"`
+	Kafka                string `json:"kafka,omitempty"`
 	Nexus                string `json:"nexus"`
 	Pipeline             string `json:"pipeline"`
 	Project              string `json:"project"`
@@ -173,6 +576,90 @@ type WorkshopStatus struct {
 	Serverless           string `json:"serverless"`
 	UsernameDistribution string `json:"usernameDistribution"`
 	Vault                string `json:"vault"`
+
+	GitUsers      []GitUserStatus          `json:"gitUsers,omitempty"`
+	NexusCache    NexusCacheStatus         `json:"nexusCache,omitempty"`
+	DevWorkspaces []DevWorkspaceUserStatus `json:"devWorkspaces,omitempty"`
+	ImagePuller   ImagePullerStatus        `json:"imagePuller,omitempty"`
+
+	GitOpsApplications []GitOpsApplicationUserStatus `json:"gitopsApplications,omitempty"`
+	Knative            KnativeStatus                 `json:"knative,omitempty"`
+
+	// Ready is true once the workshop is reconciled and its images are pulled on the nodes
+	Ready bool `json:"ready"`
+}
+
+// ImagePullerStatus is the progress of the image pre-pulling
+type ImagePullerStatus struct {
+	// Phase of the pulls: Pulling or Completed
+	Phase string `json:"phase,omitempty"`
+	// Images pulled on the nodes
+	Images []string `json:"images,omitempty"`
+	// Nodes having pulled the images, out of the desired ones
+	NodesReady   int32 `json:"nodesReady,omitempty"`
+	NodesDesired int32 `json:"nodesDesired,omitempty"`
+}
+
+// KnativeStatus is the readiness of Knative Serving and Eventing
+type KnativeStatus struct {
+	Serving  KnativeComponentStatus `json:"serving,omitempty"`
+	Eventing KnativeComponentStatus `json:"eventing,omitempty"`
+}
+
+// KnativeComponentStatus is the Ready condition of a KnativeServing or KnativeEventing
+type KnativeComponentStatus struct {
+	Ready   bool   `json:"ready"`
+	Message string `json:"message,omitempty"`
+}
+
+// NexusCacheStatus is the progress of the Nexus warm-up
+type NexusCacheStatus struct {
+	// Phase of the warm-up: Running, Completed or Failed
+	Phase string `json:"phase,omitempty"`
+	// Checksum of the warm-up configuration the phase applies to
+	Checksum       string       `json:"checksum,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Number of times the failed warm-up of the configuration was retried
+	Retries int `json:"retries,omitempty"`
+}
+
+// DevWorkspaceUserStatus is the state of the DevWorkspace of a user
+type DevWorkspaceUserStatus struct {
+	Username string `json:"username"`
+	// Phase of the DevWorkspace: Starting, Running, Stopped or Failed
+	Phase   string `json:"phase,omitempty"`
+	URL     string `json:"url,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// GitOpsApplicationUserStatus summarizes the Argo CD Applications of a user
+type GitOpsApplicationUserStatus struct {
+	Username     string                    `json:"username"`
+	Applications []GitOpsApplicationStatus `json:"applications,omitempty"`
+	// Applications Synced and Healthy, out of the total
+	Synced  int `json:"synced"`
+	Healthy int `json:"healthy"`
+	Total   int `json:"total"`
+}
+
+// GitOpsApplicationStatus is the state of an Argo CD Application
+type GitOpsApplicationStatus struct {
+	Name string `json:"name"`
+	// Sync status: Synced, OutOfSync or Unknown
+	Sync string `json:"sync,omitempty"`
+	// Health status: Healthy, Progressing, Degraded, Suspended, Missing or Unknown
+	Health  string `json:"health,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// GitUserStatus is the result of the reconciliation of a git user
+type GitUserStatus struct {
+	Username string `json:"username"`
+	Status   string `json:"status"`
+	// Created is true if the account was created by the operator, only these accounts are deleted
+	Created   bool     `json:"created,omitempty"`
+	Message   string   `json:"message,omitempty"`
+	CloneURLs []string `json:"cloneURLs,omitempty"`
 }
 
 // +kubebuilder:object:root=true
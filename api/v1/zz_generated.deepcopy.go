@@ -57,11 +57,37 @@ func (in *CertManagerSpec) DeepCopy() *CertManagerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CodeReadyWorkspaceServerSpec) DeepCopyInto(out *CodeReadyWorkspaceServerSpec) {
+	*out = *in
+	if in.CustomCheProperties != nil {
+		in, out := &in.CustomCheProperties, &out.CustomCheProperties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.Storage = in.Storage
+	out.DevfileRegistryImage = in.DevfileRegistryImage
+	out.PluginRegistryImage = in.PluginRegistryImage
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CodeReadyWorkspaceServerSpec.
+func (in *CodeReadyWorkspaceServerSpec) DeepCopy() *CodeReadyWorkspaceServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CodeReadyWorkspaceServerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CodeReadyWorkspaceSpec) DeepCopyInto(out *CodeReadyWorkspaceSpec) {
 	*out = *in
 	out.OperatorHub = in.OperatorHub
 	out.PluginRegistryImage = in.PluginRegistryImage
+	in.Server.DeepCopyInto(&out.Server)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CodeReadyWorkspaceSpec.
@@ -74,10 +100,148 @@ func (in *CodeReadyWorkspaceSpec) DeepCopy() *CodeReadyWorkspaceSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CodeReadyWorkspaceStorageSpec) DeepCopyInto(out *CodeReadyWorkspaceStorageSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CodeReadyWorkspaceStorageSpec.
+func (in *CodeReadyWorkspaceStorageSpec) DeepCopy() *CodeReadyWorkspaceStorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CodeReadyWorkspaceStorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevSpacesSpec) DeepCopyInto(out *DevSpacesSpec) {
+	*out = *in
+	out.OperatorHub = in.OperatorHub
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevSpacesSpec.
+func (in *DevSpacesSpec) DeepCopy() *DevSpacesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DevSpacesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevWorkspaceUserStatus) DeepCopyInto(out *DevWorkspaceUserStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspaceUserStatus.
+func (in *DevWorkspaceUserStatus) DeepCopy() *DevWorkspaceUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DevWorkspaceUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitOpsApplicationSetSpec) DeepCopyInto(out *GitOpsApplicationSetSpec) {
+	*out = *in
+	out.SyncPolicy = in.SyncPolicy
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsApplicationSetSpec.
+func (in *GitOpsApplicationSetSpec) DeepCopy() *GitOpsApplicationSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GitOpsApplicationSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitOpsApplicationSpec) DeepCopyInto(out *GitOpsApplicationSpec) {
+	*out = *in
+	out.SyncPolicy = in.SyncPolicy
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsApplicationSpec.
+func (in *GitOpsApplicationSpec) DeepCopy() *GitOpsApplicationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GitOpsApplicationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitOpsApplicationStatus) DeepCopyInto(out *GitOpsApplicationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsApplicationStatus.
+func (in *GitOpsApplicationStatus) DeepCopy() *GitOpsApplicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GitOpsApplicationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitOpsApplicationSyncPolicy) DeepCopyInto(out *GitOpsApplicationSyncPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsApplicationSyncPolicy.
+func (in *GitOpsApplicationSyncPolicy) DeepCopy() *GitOpsApplicationSyncPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(GitOpsApplicationSyncPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitOpsApplicationUserStatus) DeepCopyInto(out *GitOpsApplicationUserStatus) {
+	*out = *in
+	if in.Applications != nil {
+		in, out := &in.Applications, &out.Applications
+		*out = make([]GitOpsApplicationStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsApplicationUserStatus.
+func (in *GitOpsApplicationUserStatus) DeepCopy() *GitOpsApplicationUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GitOpsApplicationUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GitOpsSpec) DeepCopyInto(out *GitOpsSpec) {
 	*out = *in
 	out.OperatorHub = in.OperatorHub
+	if in.Applications != nil {
+		in, out := &in.Applications, &out.Applications
+		*out = make([]GitOpsApplicationSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.ApplicationSets != nil {
+		in, out := &in.ApplicationSets, &out.ApplicationSets
+		*out = make([]GitOpsApplicationSetSpec, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsSpec.
@@ -90,10 +254,226 @@ func (in *GitOpsSpec) DeepCopy() *GitOpsSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitRepositoriesSpec) DeepCopyInto(out *GitRepositoriesSpec) {
+	*out = *in
+	if in.Extra != nil {
+		in, out := &in.Extra, &out.Extra
+		*out = make([]GitRepositorySpec, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitRepositoriesSpec.
+func (in *GitRepositoriesSpec) DeepCopy() *GitRepositoriesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GitRepositoriesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitRepositorySpec) DeepCopyInto(out *GitRepositorySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitRepositorySpec.
+func (in *GitRepositorySpec) DeepCopy() *GitRepositorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GitRepositorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitSpec) DeepCopyInto(out *GitSpec) {
+	*out = *in
+	if in.Logins != nil {
+		in, out := &in.Logins, &out.Logins
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Repositories.DeepCopyInto(&out.Repositories)
+	if in.Webhooks != nil {
+		in, out := &in.Webhooks, &out.Webhooks
+		*out = make([]GitWebhookSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitSpec.
+func (in *GitSpec) DeepCopy() *GitSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GitSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitUserStatus) DeepCopyInto(out *GitUserStatus) {
+	*out = *in
+	if in.CloneURLs != nil {
+		in, out := &in.CloneURLs, &out.CloneURLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitUserStatus.
+func (in *GitUserStatus) DeepCopy() *GitUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GitUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitWebhookSpec) DeepCopyInto(out *GitWebhookSpec) {
+	*out = *in
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitWebhookSpec.
+func (in *GitWebhookSpec) DeepCopy() *GitWebhookSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GitWebhookSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in GiteaConfigSection) DeepCopyInto(out *GiteaConfigSection) {
+	{
+		in := &in
+		*out = make(GiteaConfigSection, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GiteaConfigSection.
+func (in GiteaConfigSection) DeepCopy() GiteaConfigSection {
+	if in == nil {
+		return nil
+	}
+	out := new(GiteaConfigSection)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GiteaDatabaseSpec) DeepCopyInto(out *GiteaDatabaseSpec) {
+	*out = *in
+	out.Image = in.Image
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GiteaDatabaseSpec.
+func (in *GiteaDatabaseSpec) DeepCopy() *GiteaDatabaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GiteaDatabaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GiteaOrganizationSpec) DeepCopyInto(out *GiteaOrganizationSpec) {
+	*out = *in
+	if in.Instructors != nil {
+		in, out := &in.Instructors, &out.Instructors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GiteaOrganizationSpec.
+func (in *GiteaOrganizationSpec) DeepCopy() *GiteaOrganizationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GiteaOrganizationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GiteaSSHSpec) DeepCopyInto(out *GiteaSSHSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GiteaSSHSpec.
+func (in *GiteaSSHSpec) DeepCopy() *GiteaSSHSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GiteaSSHSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GiteaServerSpec) DeepCopyInto(out *GiteaServerSpec) {
+	*out = *in
+	out.Image = in.Image
+	in.Resources.DeepCopyInto(&out.Resources)
+	out.SSH = in.SSH
+	in.Database.DeepCopyInto(&out.Database)
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]GiteaConfigSection, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(GiteaConfigSection, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GiteaServerSpec.
+func (in *GiteaServerSpec) DeepCopy() *GiteaServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GiteaServerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GiteaSpec) DeepCopyInto(out *GiteaSpec) {
 	*out = *in
 	out.Image = in.Image
+	in.Server.DeepCopyInto(&out.Server)
+	in.Organization.DeepCopyInto(&out.Organization)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GiteaSpec.
@@ -123,6 +503,59 @@ func (in *GuideSpec) DeepCopy() *GuideSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePullerSpec) DeepCopyInto(out *ImagePullerSpec) {
+	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.Image = in.Image
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePullerSpec.
+func (in *ImagePullerSpec) DeepCopy() *ImagePullerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePullerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePullerStatus) DeepCopyInto(out *ImagePullerStatus) {
+	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePullerStatus.
+func (in *ImagePullerStatus) DeepCopy() *ImagePullerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePullerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageSpec) DeepCopyInto(out *ImageSpec) {
 	*out = *in
@@ -142,15 +575,21 @@ func (in *ImageSpec) DeepCopy() *ImageSpec {
 func (in *InfrastructureSpec) DeepCopyInto(out *InfrastructureSpec) {
 	*out = *in
 	out.CertManager = in.CertManager
-	out.CodeReadyWorkspace = in.CodeReadyWorkspace
-	out.Gitea = in.Gitea
-	out.GitOps = in.GitOps
+	in.CodeReadyWorkspace.DeepCopyInto(&out.CodeReadyWorkspace)
+	out.DevSpaces = in.DevSpaces
+	in.Git.DeepCopyInto(&out.Git)
+	in.Gitea.DeepCopyInto(&out.Gitea)
+	in.GitOps.DeepCopyInto(&out.GitOps)
 	in.Guide.DeepCopyInto(&out.Guide)
-	out.Nexus = in.Nexus
+	in.ImagePuller.DeepCopyInto(&out.ImagePuller)
+	in.Kafka.DeepCopyInto(&out.Kafka)
+	in.Nexus.DeepCopyInto(&out.Nexus)
 	out.Pipeline = in.Pipeline
+	out.Portal = in.Portal
 	out.Project = in.Project
-	out.ServiceMesh = in.ServiceMesh
-	out.Serverless = in.Serverless
+	in.ServiceMesh.DeepCopyInto(&out.ServiceMesh)
+	in.Serverless.DeepCopyInto(&out.Serverless)
+	out.SSO = in.SSO
 	out.Vault = in.Vault
 }
 
@@ -164,10 +603,238 @@ func (in *InfrastructureSpec) DeepCopy() *InfrastructureSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IstioSpec) DeepCopyInto(out *IstioSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IstioSpec.
+func (in *IstioSpec) DeepCopy() *IstioSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IstioSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaSpec) DeepCopyInto(out *KafkaSpec) {
+	*out = *in
+	out.OperatorHub = in.OperatorHub
+	if in.Topics != nil {
+		in, out := &in.Topics, &out.Topics
+		*out = make([]KafkaTopicSpec, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaSpec.
+func (in *KafkaSpec) DeepCopy() *KafkaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaTopicSpec) DeepCopyInto(out *KafkaTopicSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaTopicSpec.
+func (in *KafkaTopicSpec) DeepCopy() *KafkaTopicSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaTopicSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KnativeAutoscalingSpec) DeepCopyInto(out *KnativeAutoscalingSpec) {
+	*out = *in
+	if in.ContainerConcurrencyTarget != nil {
+		in, out := &in.ContainerConcurrencyTarget, &out.ContainerConcurrencyTarget
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ScaleToZero != nil {
+		in, out := &in.ScaleToZero, &out.ScaleToZero
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxScale != nil {
+		in, out := &in.MaxScale, &out.MaxScale
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KnativeAutoscalingSpec.
+func (in *KnativeAutoscalingSpec) DeepCopy() *KnativeAutoscalingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KnativeAutoscalingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KnativeComponentStatus) DeepCopyInto(out *KnativeComponentStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KnativeComponentStatus.
+func (in *KnativeComponentStatus) DeepCopy() *KnativeComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KnativeComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KnativeEventingSpec) DeepCopyInto(out *KnativeEventingSpec) {
+	*out = *in
+	if in.Brokers != nil {
+		in, out := &in.Brokers, &out.Brokers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KnativeEventingSpec.
+func (in *KnativeEventingSpec) DeepCopy() *KnativeEventingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KnativeEventingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KnativeServingSpec) DeepCopyInto(out *KnativeServingSpec) {
+	*out = *in
+	in.Autoscaling.DeepCopyInto(&out.Autoscaling)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KnativeServingSpec.
+func (in *KnativeServingSpec) DeepCopy() *KnativeServingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KnativeServingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KnativeStatus) DeepCopyInto(out *KnativeStatus) {
+	*out = *in
+	out.Serving = in.Serving
+	out.Eventing = in.Eventing
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KnativeStatus.
+func (in *KnativeStatus) DeepCopy() *KnativeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KnativeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusCacheSpec) DeepCopyInto(out *NexusCacheSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusCacheSpec.
+func (in *NexusCacheSpec) DeepCopy() *NexusCacheSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusCacheSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusCacheStatus) DeepCopyInto(out *NexusCacheStatus) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusCacheStatus.
+func (in *NexusCacheStatus) DeepCopy() *NexusCacheStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusCacheStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusRepositorySpec) DeepCopyInto(out *NexusRepositorySpec) {
+	*out = *in
+	if in.MemberRepos != nil {
+		in, out := &in.MemberRepos, &out.MemberRepos
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusRepositorySpec.
+func (in *NexusRepositorySpec) DeepCopy() *NexusRepositorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusRepositorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusServerSpec) DeepCopyInto(out *NexusServerSpec) {
+	*out = *in
+	out.Image = in.Image
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusServerSpec.
+func (in *NexusServerSpec) DeepCopy() *NexusServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusServerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NexusSpec) DeepCopyInto(out *NexusSpec) {
 	*out = *in
 	out.Image = in.Image
+	out.Server = in.Server
+	if in.Repositories != nil {
+		in, out := &in.Repositories, &out.Repositories
+		*out = make([]NexusRepositorySpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.WarmUp.DeepCopyInto(&out.WarmUp)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusSpec.
@@ -180,6 +847,33 @@ func (in *NexusSpec) DeepCopy() *NexusSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusWarmUpSpec) DeepCopyInto(out *NexusWarmUpSpec) {
+	*out = *in
+	out.Image = in.Image
+	if in.Maven != nil {
+		in, out := &in.Maven, &out.Maven
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Npm != nil {
+		in, out := &in.Npm, &out.Npm
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.Cache = in.Cache
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusWarmUpSpec.
+func (in *NexusWarmUpSpec) DeepCopy() *NexusWarmUpSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusWarmUpSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OperatorHubSpec) DeepCopyInto(out *OperatorHubSpec) {
 	*out = *in
@@ -211,6 +905,22 @@ func (in *PipelineSpec) DeepCopy() *PipelineSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PortalSpec) DeepCopyInto(out *PortalSpec) {
+	*out = *in
+	out.Image = in.Image
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PortalSpec.
+func (in *PortalSpec) DeepCopy() *PortalSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PortalSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProjectSpec) DeepCopyInto(out *ProjectSpec) {
 	*out = *in
@@ -226,6 +936,22 @@ func (in *ProjectSpec) DeepCopy() *ProjectSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSOSpec) DeepCopyInto(out *SSOSpec) {
+	*out = *in
+	out.ProxyImage = in.ProxyImage
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSOSpec.
+func (in *SSOSpec) DeepCopy() *SSOSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SSOSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScholarsSpec) DeepCopyInto(out *ScholarsSpec) {
 	*out = *in
@@ -252,6 +978,8 @@ func (in *ScholarsSpec) DeepCopy() *ScholarsSpec {
 func (in *ServerlessSpec) DeepCopyInto(out *ServerlessSpec) {
 	*out = *in
 	out.OperatorHub = in.OperatorHub
+	in.Serving.DeepCopyInto(&out.Serving)
+	in.Eventing.DeepCopyInto(&out.Eventing)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerlessSpec.
@@ -264,6 +992,67 @@ func (in *ServerlessSpec) DeepCopy() *ServerlessSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceMeshAddonsSpec) DeepCopyInto(out *ServiceMeshAddonsSpec) {
+	*out = *in
+	if in.Kiali != nil {
+		in, out := &in.Kiali, &out.Kiali
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Grafana != nil {
+		in, out := &in.Grafana, &out.Grafana
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Jaeger != nil {
+		in, out := &in.Jaeger, &out.Jaeger
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceMeshAddonsSpec.
+func (in *ServiceMeshAddonsSpec) DeepCopy() *ServiceMeshAddonsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceMeshAddonsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceMeshControlPlaneSpec) DeepCopyInto(out *ServiceMeshControlPlaneSpec) {
+	*out = *in
+	if in.TracingSampling != nil {
+		in, out := &in.TracingSampling, &out.TracingSampling
+		*out = new(int32)
+		**out = **in
+	}
+	in.Addons.DeepCopyInto(&out.Addons)
+	if in.IngressGatewayReplicas != nil {
+		in, out := &in.IngressGatewayReplicas, &out.IngressGatewayReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceMeshControlPlaneSpec.
+func (in *ServiceMeshControlPlaneSpec) DeepCopy() *ServiceMeshControlPlaneSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceMeshControlPlaneSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceMeshSpec) DeepCopyInto(out *ServiceMeshSpec) {
 	*out = *in
@@ -271,6 +1060,8 @@ func (in *ServiceMeshSpec) DeepCopyInto(out *ServiceMeshSpec) {
 	out.ElasticSearchOperatorHub = in.ElasticSearchOperatorHub
 	out.JaegerOperatorHub = in.JaegerOperatorHub
 	out.KialiOperatorHub = in.KialiOperatorHub
+	out.Istio = in.Istio
+	in.ControlPlane.DeepCopyInto(&out.ControlPlane)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceMeshSpec.
@@ -336,7 +1127,7 @@ func (in *Workshop) DeepCopyInto(out *Workshop) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Workshop.
@@ -410,6 +1201,28 @@ func (in *WorkshopSpec) DeepCopy() *WorkshopSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkshopStatus) DeepCopyInto(out *WorkshopStatus) {
 	*out = *in
+	if in.GitUsers != nil {
+		in, out := &in.GitUsers, &out.GitUsers
+		*out = make([]GitUserStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.NexusCache.DeepCopyInto(&out.NexusCache)
+	if in.DevWorkspaces != nil {
+		in, out := &in.DevWorkspaces, &out.DevWorkspaces
+		*out = make([]DevWorkspaceUserStatus, len(*in))
+		copy(*out, *in)
+	}
+	in.ImagePuller.DeepCopyInto(&out.ImagePuller)
+	if in.GitOpsApplications != nil {
+		in, out := &in.GitOpsApplications, &out.GitOpsApplications
+		*out = make([]GitOpsApplicationUserStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Knative = in.Knative
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkshopStatus.
@@ -0,0 +1,58 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	"github.com/prometheus/common/log"
+)
+
+// The header filter runs in front of the OAuth proxy and removes the user headers sent by the clients, so that the
+// upstream only receives the ones set by the proxy, including on the paths the proxy lets through unauthenticated
+func main() {
+	var addr, upstream, headers string
+	flag.StringVar(&addr, "addr", ":8080", "The address the filter binds to.")
+	flag.StringVar(&upstream, "upstream", "http://127.0.0.1:8081", "The URL of the OAuth proxy.")
+	flag.StringVar(&headers, "strip-headers", "X-Forwarded-User,X-Forwarded-Email", "The comma separated headers removed from the requests.")
+	flag.Parse()
+
+	upstreamURL, err := url.Parse(upstream)
+	if err != nil {
+		log.Fatalf("Invalid upstream %s: %v", upstream, err)
+	}
+
+	proxy := httputil.NewSingleHostReverseProxy(upstreamURL)
+	director := proxy.Director
+	proxy.Director = func(req *http.Request) {
+		director(req)
+		for _, header := range strings.Split(headers, ",") {
+			req.Header.Del(strings.TrimSpace(header))
+		}
+	}
+	// Stream the Git and registry transfers
+	proxy.FlushInterval = -1
+
+	log.Infof("Starting header filter on %s", addr)
+	if err := http.ListenAndServe(addr, proxy); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,58 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"net/http"
+	"os"
+
+	"github.com/prometheus/common/log"
+	"github.com/stakater/workshop-operator/common/portal"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+func main() {
+	var addr, configPath, claimsName string
+	flag.StringVar(&addr, "addr", ":8080", "The address the portal binds to.")
+	flag.StringVar(&configPath, "config", "/etc/portal/config.json", "The portal configuration generated by the operator.")
+	flag.StringVar(&claimsName, "claims", "portal-claims", "The ConfigMap storing the user claims.")
+	flag.Parse()
+
+	namespace := os.Getenv("POD_NAMESPACE")
+	adminPassword := os.Getenv("PORTAL_ADMIN_PASSWORD")
+	cookieSecret := os.Getenv("PORTAL_COOKIE_SECRET")
+	if namespace == "" || adminPassword == "" || cookieSecret == "" {
+		log.Fatal("POD_NAMESPACE, PORTAL_ADMIN_PASSWORD and PORTAL_COOKIE_SECRET must be set")
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		log.Fatalf("Unable to get cluster configuration: %v", err)
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		log.Fatalf("Unable to create Kubernetes client: %v", err)
+	}
+
+	server := portal.NewServer(configPath, adminPassword, cookieSecret, portal.NewClaimStore(clientset, namespace, claimsName))
+	log.Infof("Starting portal on %s", addr)
+	if err := http.ListenAndServe(addr, server.Handler()); err != nil {
+		log.Fatal(err)
+	}
+}
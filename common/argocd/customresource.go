@@ -23,9 +23,10 @@ func NewArgoCDCustomResource(workshop *workshopv1.Workshop, scheme *runtime.Sche
 		},
 		Spec: argocdoperator.ArgoCDSpec{
 			ApplicationInstanceLabelKey: "argocd.argoproj.io/instance",
-			// Dex: argocd.ArgoCDDexSpec{
-			// 	OpenShiftOAuth: true,
-			// },
+			// Users log in with their OpenShift identity through Dex instead of local accounts
+			Dex: argocdoperator.ArgoCDDexSpec{
+				OpenShiftOAuth: workshop.Spec.Infrastructure.SSO.Enabled,
+			},
 			Server: argocdoperator.ArgoCDServerSpec{
 				Insecure: true,
 				Route: argocdoperator.ArgoCDRouteSpec{
@@ -42,9 +43,9 @@ func NewArgoCDCustomResource(workshop *workshopv1.Workshop, scheme *runtime.Sche
 	return cr
 }
 
-// NewAppProjectCustomResource create a AppProject Custom Resource
+// NewAppProjectCustomResource create a AppProject Custom Resource, its Applications only syncing from sourceRepos
 func NewAppProjectCustomResource(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
-	name string, namespace string, labels map[string]string, argocdPolicy string) *argocd.AppProject {
+	name string, namespace string, labels map[string]string, sourceRepos []string) *argocd.AppProject {
 
 	cr := &argocd.AppProject{
 		ObjectMeta: metav1.ObjectMeta{
@@ -59,8 +60,83 @@ func NewAppProjectCustomResource(workshop *workshopv1.Workshop, scheme *runtime.
 					Server:    "https://kubernetes.default.svc",
 				},
 			},
-			SourceRepos: []string{
-				"*",
+			SourceRepos: sourceRepos,
+		},
+	}
+	return cr
+}
+
+// NewApplicationCustomResource create an Application Custom Resource of the project, synced from repoURL to the
+// namespace of the application spec
+func NewApplicationCustomResource(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, namespace string, labels map[string]string, project string, repoURL string,
+	applicationSpec workshopv1.GitOpsApplicationSpec) *argocd.Application {
+
+	cr := &argocd.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: argocd.ApplicationSpec{
+			Project: project,
+			Source: argocd.ApplicationSource{
+				RepoURL:        repoURL,
+				Path:           applicationSpec.Path,
+				TargetRevision: applicationSpec.TargetRevision,
+			},
+			Destination: argocd.ApplicationDestination{
+				Namespace: applicationSpec.Namespace,
+				Server:    "https://kubernetes.default.svc",
+			},
+		},
+	}
+
+	if applicationSpec.SyncPolicy.Automated {
+		cr.Spec.SyncPolicy = &argocd.SyncPolicy{
+			Automated: &argocd.SyncPolicyAutomated{
+				Prune:    applicationSpec.SyncPolicy.Prune,
+				SelfHeal: applicationSpec.SyncPolicy.SelfHeal,
+			},
+		}
+	}
+	return cr
+}
+
+// NewApplicationSetCustomResource create an ApplicationSet Custom Resource with a list generator, its template
+// reading the username, project and namespace keys of the elements
+func NewApplicationSetCustomResource(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, namespace string, labels map[string]string, applicationLabels map[string]string, repoURL string,
+	applicationSetSpec workshopv1.GitOpsApplicationSetSpec, elements []map[string]string) *ApplicationSet {
+
+	application := NewApplicationCustomResource(workshop, scheme, "{{username}}-"+name, namespace, applicationLabels,
+		"{{project}}", repoURL, workshopv1.GitOpsApplicationSpec{
+			Path:           applicationSetSpec.Path,
+			TargetRevision: applicationSetSpec.TargetRevision,
+			Namespace:      "{{namespace}}",
+			SyncPolicy:     applicationSetSpec.SyncPolicy,
+		})
+
+	cr := &ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: ApplicationSetSpec{
+			Generators: []ApplicationSetGenerator{
+				{
+					List: &ListGenerator{
+						Elements: elements,
+					},
+				},
+			},
+			Template: ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: ApplicationSetTemplateMeta{
+					Name:   application.Name,
+					Labels: application.Labels,
+				},
+				Spec: application.Spec,
 			},
 		},
 	}
@@ -0,0 +1,71 @@
+package argocd
+
+import "k8s.io/apimachinery/pkg/runtime"
+
+// DeepCopyInto copies all properties of this object into another object of the
+// same type that is provided as a pointer.
+func (in *ApplicationSet) DeepCopyInto(out *ApplicationSet) {
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy returns a copy of the object
+func (in *ApplicationSet) DeepCopy() *ApplicationSet {
+	out := ApplicationSet{}
+	in.DeepCopyInto(&out)
+
+	return &out
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *ApplicationSet) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the generators and the template of the spec
+func (in *ApplicationSetSpec) DeepCopyInto(out *ApplicationSetSpec) {
+	if in.Generators != nil {
+		out.Generators = make([]ApplicationSetGenerator, len(in.Generators))
+		for i := range in.Generators {
+			if in.Generators[i].List != nil {
+				list := &ListGenerator{}
+				if in.Generators[i].List.Elements != nil {
+					list.Elements = make([]map[string]string, len(in.Generators[i].List.Elements))
+					for j, element := range in.Generators[i].List.Elements {
+						list.Elements[j] = map[string]string{}
+						for key, value := range element {
+							list.Elements[j][key] = value
+						}
+					}
+				}
+				out.Generators[i].List = list
+			}
+		}
+	}
+
+	out.Template.Name = in.Template.Name
+	if in.Template.Labels != nil {
+		out.Template.Labels = map[string]string{}
+		for key, value := range in.Template.Labels {
+			out.Template.Labels[key] = value
+		}
+	}
+	in.Template.Spec.DeepCopyInto(&out.Template.Spec)
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *ApplicationSetList) DeepCopyObject() runtime.Object {
+	out := ApplicationSetList{}
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+
+	if in.Items != nil {
+		out.Items = make([]ApplicationSet, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+
+	return &out
+}
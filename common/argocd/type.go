@@ -0,0 +1,47 @@
+package argocd
+
+import (
+	argocd "github.com/argoproj/argo-cd/pkg/apis/application/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ApplicationSetSpec holds the fields of the ApplicationSet set by the Workshop: a list generator and the
+// template of the Applications
+type ApplicationSetSpec struct {
+	Generators []ApplicationSetGenerator `json:"generators"`
+	Template   ApplicationSetTemplate    `json:"template"`
+}
+
+type ApplicationSetGenerator struct {
+	List *ListGenerator `json:"list,omitempty"`
+}
+
+// ListGenerator generates an Application for each element, its values replacing the {{key}} parameters of the
+// template
+type ListGenerator struct {
+	Elements []map[string]string `json:"elements"`
+}
+
+type ApplicationSetTemplate struct {
+	ApplicationSetTemplateMeta `json:"metadata"`
+	Spec                       argocd.ApplicationSpec `json:"spec"`
+}
+
+type ApplicationSetTemplateMeta struct {
+	Name   string            `json:"name,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type ApplicationSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ApplicationSetSpec `json:"spec"`
+}
+
+type ApplicationSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ApplicationSet `json:"items"`
+}
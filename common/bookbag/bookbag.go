@@ -13,7 +13,7 @@ import (
 // NewDeployment create a deployment
 func NewDeployment(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
 	name string, namespace string, labels map[string]string,
-	userID string, appsHostnameSuffix string, openshiftConsoleURL string) *appsv1.Deployment {
+	userID string, appsHostnameSuffix string, openshiftConsoleURL string, gitURL string) *appsv1.Deployment {
 
 	user := fmt.Sprintf("user%s", userID)
 	image := workshop.Spec.Infrastructure.Guide.Bookbag.Image.Name + ":" + workshop.Spec.Infrastructure.Guide.Bookbag.Image.Tag
@@ -25,7 +25,7 @@ func NewDeployment(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
 	"USER_ID": "` + userID + `",
 	"OPENSHIFT_PASSWORD": "` + workshop.Spec.User.Password + `",
 	"CHE_URL": "http://codeready-workspaces.` + appsHostnameSuffix + `",
-	"GIT_URL": "https://gitea-server-gitea.` + appsHostnameSuffix + `",
+	"GIT_URL": "` + gitURL + `",
 	"JAEGER_URL": "https://jaeger-istio-system.` + appsHostnameSuffix + `",
 	"KIALI_URL": "https://kiali-istio-system.` + appsHostnameSuffix + `",
 	"KIBANA_URL": "https://kibana-openshift-logging.` + appsHostnameSuffix + `",
@@ -1,38 +1,56 @@
 package codeready
 
 import (
+	"reflect"
+	"strconv"
+
 	che "github.com/eclipse/che-operator/pkg/apis/org/v1"
 	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/keycloak"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
-type codeReadyUser struct {
-	Username    string       `json:"username"`
-	Enabled     bool         `json:"enabled"`
-	Email       string       `json:"email"`
-	Credentials []credential `json:"credentials"`
-	ClientRoles clientRoles  `json:"clientRoles"`
-}
-
-type credential struct {
-	Type  string `json:"type"`
-	Value string `json:"value"`
-}
-
-type clientRoles struct {
-	RealmManagement []string `json:"realm-management"`
-}
+// Defaults of the server
+const (
+	DefaultWorkspaceNamespace     = "<username>-workspace"
+	DefaultRunningWorkspacesLimit = 2
+	DefaultStorageStrategy        = "per-workspace"
+	DefaultStorageClaimSize       = "1Gi"
+	DefaultAdminUsername          = "admin"
+)
 
-// NewCustomResource creates a Custom Resource
+// NewCustomResource creates a Custom Resource, the identity provider admin being created with the credentials
 func NewCustomResource(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
-	name string, namespace string) *che.CheCluster {
+	name string, namespace string, adminUsername string, adminPassword string) *che.CheCluster {
 
-	pluginRegistryImage := workshop.Spec.Infrastructure.CodeReadyWorkspace.PluginRegistryImage.Name +
-		":" + workshop.Spec.Infrastructure.CodeReadyWorkspace.PluginRegistryImage.Tag
+	spec := workshop.Spec.Infrastructure.CodeReadyWorkspace
+	server := spec.Server
 
-	if pluginRegistryImage == ":" {
-		pluginRegistryImage = ""
+	workspaceNamespace := server.WorkspaceNamespace
+	if workspaceNamespace == "" {
+		workspaceNamespace = DefaultWorkspaceNamespace
+	}
+	runningWorkspacesLimit := server.RunningWorkspacesLimit
+	if runningWorkspacesLimit == 0 {
+		runningWorkspacesLimit = DefaultRunningWorkspacesLimit
+	}
+	customCheProperties := map[string]string{
+		"CHE_INFRA_KUBERNETES_NAMESPACE_DEFAULT": workspaceNamespace,
+		"CHE_LIMITS_USER_WORKSPACES_RUN_COUNT":   strconv.Itoa(runningWorkspacesLimit),
+		"CHE_LIMITS_WORKSPACE_IDLE_TIMEOUT":      strconv.Itoa(server.IdleTimeout),
+	}
+	for property, value := range server.CustomCheProperties {
+		customCheProperties[property] = value
+	}
+
+	storageStrategy := server.Storage.Strategy
+	if storageStrategy == "" {
+		storageStrategy = DefaultStorageStrategy
+	}
+	storageClaimSize := server.Storage.ClaimSize
+	if storageClaimSize == "" {
+		storageClaimSize = DefaultStorageClaimSize
 	}
 
 	cr := &che.CheCluster{
@@ -46,16 +64,12 @@ func NewCustomResource(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
 		},
 		Spec: che.CheClusterSpec{
 			Server: che.CheClusterSpecServer{
-				CheImageTag: "",
-				CheFlavor:   "codeready",
-				CustomCheProperties: map[string]string{
-					"CHE_INFRA_KUBERNETES_NAMESPACE_DEFAULT": "<username>-workspace",
-					"CHE_LIMITS_USER_WORKSPACES_RUN_COUNT":   "2",
-					"CHE_LIMITS_WORKSPACE_IDLE_TIMEOUT":      "0",
-				},
-				DevfileRegistryImage: "",
-				PluginRegistryImage:  pluginRegistryImage,
-				TlsSupport:           true,
+				CheImageTag:          "",
+				CheFlavor:            "codeready",
+				CustomCheProperties:  customCheProperties,
+				DevfileRegistryImage: imageName(server.DevfileRegistryImage),
+				PluginRegistryImage:  PluginRegistryImage(workshop),
+				TlsSupport:           !server.DisableTLS,
 				SelfSignedCert:       false,
 			},
 			Database: che.CheClusterSpecDB{
@@ -67,41 +81,77 @@ func NewCustomResource(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
 				ChePostgresDb:       "",
 			},
 			Auth: che.CheClusterSpecAuth{
-				OpenShiftoAuth:                workshop.Spec.Infrastructure.CodeReadyWorkspace.OpenshiftOAuth,
+				OpenShiftoAuth:                spec.OpenshiftOAuth,
 				IdentityProviderImage:         "",
 				ExternalIdentityProvider:      false,
 				IdentityProviderURL:           "",
 				IdentityProviderRealm:         "",
 				IdentityProviderClientId:      "",
-				IdentityProviderAdminUserName: "admin",
-				IdentityProviderPassword:      "admin",
+				IdentityProviderAdminUserName: adminUsername,
+				IdentityProviderPassword:      adminPassword,
 			},
 			Storage: che.CheClusterSpecStorage{
-				PvcStrategy:       "per-workspace",
-				PvcClaimSize:      "1Gi",
-				PreCreateSubPaths: true,
+				PvcStrategy:                  storageStrategy,
+				PvcClaimSize:                 storageClaimSize,
+				PreCreateSubPaths:            true,
+				WorkspacePVCStorageClassName: server.Storage.StorageClassName,
 			},
 		},
 	}
 	return cr
 }
 
-// NewUser creates a user
-func NewUser(username string, password string) *codeReadyUser {
-	return &codeReadyUser{
-		Username: username,
-		Enabled:  true,
-		Email:    username + "@none.com",
-		Credentials: []credential{
-			{
-				Type:  "password",
-				Value: password,
-			},
-		},
-		ClientRoles: clientRoles{
-			RealmManagement: []string{
-				"user",
-			},
-		},
+// UpdateCustomResource copies the fields set from the Workshop to the found Custom Resource, the other ones being
+// managed by the operator, and returns true if one changed
+func UpdateCustomResource(found *che.CheCluster, cr *che.CheCluster) bool {
+	if reflect.DeepEqual(found.Spec.Server.CustomCheProperties, cr.Spec.Server.CustomCheProperties) &&
+		found.Spec.Server.DevfileRegistryImage == cr.Spec.Server.DevfileRegistryImage &&
+		found.Spec.Server.PluginRegistryImage == cr.Spec.Server.PluginRegistryImage &&
+		found.Spec.Server.TlsSupport == cr.Spec.Server.TlsSupport &&
+		found.Spec.Auth.OpenShiftoAuth == cr.Spec.Auth.OpenShiftoAuth &&
+		found.Spec.Storage.PvcStrategy == cr.Spec.Storage.PvcStrategy &&
+		found.Spec.Storage.PvcClaimSize == cr.Spec.Storage.PvcClaimSize &&
+		found.Spec.Storage.WorkspacePVCStorageClassName == cr.Spec.Storage.WorkspacePVCStorageClassName {
+		return false
+	}
+
+	found.Spec.Server.CustomCheProperties = cr.Spec.Server.CustomCheProperties
+	found.Spec.Server.DevfileRegistryImage = cr.Spec.Server.DevfileRegistryImage
+	found.Spec.Server.PluginRegistryImage = cr.Spec.Server.PluginRegistryImage
+	found.Spec.Server.TlsSupport = cr.Spec.Server.TlsSupport
+	found.Spec.Auth.OpenShiftoAuth = cr.Spec.Auth.OpenShiftoAuth
+	found.Spec.Storage.PvcStrategy = cr.Spec.Storage.PvcStrategy
+	found.Spec.Storage.PvcClaimSize = cr.Spec.Storage.PvcClaimSize
+	found.Spec.Storage.WorkspacePVCStorageClassName = cr.Spec.Storage.WorkspacePVCStorageClassName
+	return true
+}
+
+// PluginRegistryImage returns the image of the plugin registry, or an empty string for the default one of the
+// operator
+func PluginRegistryImage(workshop *workshopv1.Workshop) string {
+	spec := workshop.Spec.Infrastructure.CodeReadyWorkspace
+	if spec.Server.PluginRegistryImage.Name != "" {
+		return imageName(spec.Server.PluginRegistryImage)
 	}
+	return imageName(spec.PluginRegistryImage)
+}
+
+// imageName returns the image of the spec, or an empty string for the default one of the operator
+func imageName(image workshopv1.ImageSpec) string {
+	if image.Name == "" {
+		return ""
+	}
+	if image.Tag == "" {
+		return image.Name
+	}
+	return image.Name + ":" + image.Tag
+}
+
+// NewUser creates a user of the CodeReady Workspaces realm
+func NewUser(username string, password string) keycloak.User {
+	return keycloak.NewUser(username, username+"@none.com", password, map[string][]string{
+		"realm-management": {
+			"user",
+		},
+	})
 }
@@ -0,0 +1,128 @@
+package devspaces
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// Supported flavors
+const (
+	FlavorDevSpaces    = "devspaces"
+	FlavorDevWorkspace = "devworkspace"
+)
+
+// Defaults of the workspaces
+const (
+	DefaultWorkspaceNamespace = "<username>-devspaces"
+)
+
+// Phases of a DevWorkspace
+const (
+	PhaseStarting = "Starting"
+	PhaseRunning  = "Running"
+	PhaseStopped  = "Stopped"
+	PhaseFailed   = "Failed"
+)
+
+// FlavorName returns the flavor of the workshop
+func FlavorName(workshop *workshopv1.Workshop) string {
+	if workshop.Spec.Infrastructure.DevSpaces.Flavor == "" {
+		return FlavorDevSpaces
+	}
+	return workshop.Spec.Infrastructure.DevSpaces.Flavor
+}
+
+// WorkspaceNamespace returns the namespace of the workspace of the user
+func WorkspaceNamespace(workshop *workshopv1.Workshop, username string) string {
+	workspaceNamespace := workshop.Spec.Infrastructure.DevSpaces.WorkspaceNamespace
+	if workspaceNamespace == "" {
+		workspaceNamespace = DefaultWorkspaceNamespace
+	}
+	return strings.ReplaceAll(workspaceNamespace, "<username>", username)
+}
+
+// NewCheCluster creates a CheCluster of Dev Spaces, the workspaces namespaces being created by the Workshop
+func NewCheCluster(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, namespace string, labels map[string]string) *CheCluster {
+
+	workspaceNamespace := workshop.Spec.Infrastructure.DevSpaces.WorkspaceNamespace
+	if workspaceNamespace == "" {
+		workspaceNamespace = DefaultWorkspaceNamespace
+	}
+
+	cr := &CheCluster{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "CheCluster",
+			APIVersion: CheSchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: CheClusterSpec{
+			DevEnvironments: CheClusterDevEnvironments{
+				DefaultNamespace: CheClusterDefaultNamespace{
+					Template: workspaceNamespace,
+				},
+			},
+		},
+	}
+	return cr
+}
+
+// NewDevWorkspace creates a DevWorkspace from a devfile v2, its schema version and metadata being dropped from
+// the template
+func NewDevWorkspace(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, namespace string, labels map[string]string, devfile []byte) (*DevWorkspace, error) {
+
+	devfileJSON, err := yaml.YAMLToJSON(devfile)
+	if err != nil {
+		return nil, err
+	}
+
+	template := map[string]interface{}{}
+	if err := json.Unmarshal(devfileJSON, &template); err != nil {
+		return nil, err
+	}
+	schemaVersion, _ := template["schemaVersion"].(string)
+	if !strings.HasPrefix(schemaVersion, "2.") {
+		return nil, fmt.Errorf("devspaces: the devfile schema version is %q, 2.x is required", schemaVersion)
+	}
+	delete(template, "schemaVersion")
+	delete(template, "metadata")
+
+	templateJSON, err := json.Marshal(template)
+	if err != nil {
+		return nil, err
+	}
+
+	routingClass := "che"
+	if FlavorName(workshop) == FlavorDevWorkspace {
+		routingClass = "basic"
+	}
+
+	cr := &DevWorkspace{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "DevWorkspace",
+			APIVersion: WorkspaceSchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: DevWorkspaceSpec{
+			Started:      workshop.Spec.Infrastructure.DevSpaces.Started,
+			RoutingClass: routingClass,
+			Template:     runtime.RawExtension{Raw: templateJSON},
+		},
+	}
+	return cr, nil
+}
@@ -0,0 +1,80 @@
+package devspaces
+
+import "k8s.io/apimachinery/pkg/runtime"
+
+// DeepCopyInto copies all properties of this object into another object of the
+// same type that is provided as a pointer.
+func (in *CheCluster) DeepCopyInto(out *CheCluster) {
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+}
+
+// DeepCopy returns a copy of the object
+func (in *CheCluster) DeepCopy() *CheCluster {
+	out := CheCluster{}
+	in.DeepCopyInto(&out)
+
+	return &out
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *CheCluster) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *CheClusterList) DeepCopyObject() runtime.Object {
+	out := CheClusterList{}
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+
+	if in.Items != nil {
+		out.Items = make([]CheCluster, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+
+	return &out
+}
+
+// DeepCopyInto copies all properties of this object into another object of the
+// same type that is provided as a pointer.
+func (in *DevWorkspace) DeepCopyInto(out *DevWorkspace) {
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Spec.Template.DeepCopyInto(&out.Spec.Template)
+	out.Status = in.Status
+}
+
+// DeepCopy returns a copy of the object
+func (in *DevWorkspace) DeepCopy() *DevWorkspace {
+	out := DevWorkspace{}
+	in.DeepCopyInto(&out)
+
+	return &out
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *DevWorkspace) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *DevWorkspaceList) DeepCopyObject() runtime.Object {
+	out := DevWorkspaceList{}
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+
+	if in.Items != nil {
+		out.Items = make([]DevWorkspace, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+
+	return &out
+}
@@ -0,0 +1,33 @@
+package devspaces
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// CheSchemeGroupVersion is the group version of the CheCluster of Dev Spaces
+var CheSchemeGroupVersion = schema.GroupVersion{Group: "org.eclipse.che", Version: "v2"}
+
+// WorkspaceSchemeGroupVersion is the group version of the DevWorkspaces
+var WorkspaceSchemeGroupVersion = schema.GroupVersion{Group: "workspace.devfile.io", Version: "v1alpha2"}
+
+var (
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+	AddToScheme   = SchemeBuilder.AddToScheme
+)
+
+// Adds the list of known types to the given scheme.
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(CheSchemeGroupVersion,
+		&CheCluster{},
+		&CheClusterList{},
+	)
+	metav1.AddToGroupVersion(scheme, CheSchemeGroupVersion)
+	scheme.AddKnownTypes(WorkspaceSchemeGroupVersion,
+		&DevWorkspace{},
+		&DevWorkspaceList{},
+	)
+	metav1.AddToGroupVersion(scheme, WorkspaceSchemeGroupVersion)
+	return nil
+}
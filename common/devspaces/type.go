@@ -0,0 +1,69 @@
+package devspaces
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// CheClusterSpec holds the fields of the CheCluster v2 set by the Workshop, the other ones being defaulted by
+// the Dev Spaces operator
+type CheClusterSpec struct {
+	DevEnvironments CheClusterDevEnvironments `json:"devEnvironments"`
+}
+
+type CheClusterDevEnvironments struct {
+	DefaultNamespace CheClusterDefaultNamespace `json:"defaultNamespace"`
+}
+
+type CheClusterDefaultNamespace struct {
+	Template string `json:"template,omitempty"`
+}
+
+type CheClusterStatus struct {
+	ChePhase string `json:"chePhase,omitempty"`
+	CheURL   string `json:"cheURL,omitempty"`
+}
+
+type CheCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CheClusterSpec   `json:"spec"`
+	Status CheClusterStatus `json:"status,omitempty"`
+}
+
+type CheClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []CheCluster `json:"items"`
+}
+
+// DevWorkspaceSpec holds the devfile v2 of the workspace in its template
+type DevWorkspaceSpec struct {
+	Started      bool                 `json:"started"`
+	RoutingClass string               `json:"routingClass,omitempty"`
+	Template     runtime.RawExtension `json:"template"`
+}
+
+type DevWorkspaceStatus struct {
+	DevWorkspaceID string `json:"devworkspaceId,omitempty"`
+	Phase          string `json:"phase,omitempty"`
+	MainURL        string `json:"mainUrl,omitempty"`
+	Message        string `json:"message,omitempty"`
+}
+
+type DevWorkspace struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DevWorkspaceSpec   `json:"spec"`
+	Status DevWorkspaceStatus `json:"status,omitempty"`
+}
+
+type DevWorkspaceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []DevWorkspace `json:"items"`
+}
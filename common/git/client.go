@@ -0,0 +1,97 @@
+package git
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// APIError is returned when a provider API answers with an unexpected status code
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("git: %s %s returned %d: %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// IsNotFound returns true if the error is a provider API 404
+func IsNotFound(err error) bool {
+	apiErr, ok := err.(*APIError)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// restClient is a JSON client of a provider API
+type restClient struct {
+	baseURL    string
+	headers    map[string]string
+	httpClient *http.Client
+}
+
+func newRESTClient(baseURL string, headers map[string]string) *restClient {
+	return &restClient{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		headers:    headers,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (c *restClient) do(method string, path string, in interface{}, out interface{}) error {
+	body := &bytes.Buffer{}
+	if in != nil {
+		if err := json.NewEncoder(body).Encode(in); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := ioutil.ReadAll(resp.Body)
+		return &APIError{Method: method, Path: path, StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(message))}
+	}
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+// sameEvents returns true if both lists hold the same events
+func sameEvents(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := map[string]bool{}
+	for _, event := range a {
+		seen[event] = true
+	}
+	for _, event := range b {
+		if !seen[event] {
+			return false
+		}
+	}
+	return true
+}
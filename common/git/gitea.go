@@ -0,0 +1,50 @@
+package git
+
+import (
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/gitea"
+)
+
+// GiteaProvider is the provider of the bundled Gitea
+type GiteaProvider struct {
+	Client *gitea.Client
+}
+
+// NewGiteaProvider returns a GiteaProvider using an admin client
+func NewGiteaProvider(client *gitea.Client) *GiteaProvider {
+	return &GiteaProvider{Client: client}
+}
+
+// EnsureUser creates the user or updates its email and password
+func (p *GiteaProvider) EnsureUser(username string, email string, password string) (string, error) {
+	_, status, err := p.Client.EnsureUser(gitea.CreateUserOption{
+		Username: username,
+		Email:    email,
+		Password: password,
+	})
+	return status, err
+}
+
+// EnsureUserDeleted deletes the user if it exists
+func (p *GiteaProvider) EnsureUserDeleted(username string) (string, error) {
+	return p.Client.EnsureUserDeleted(username)
+}
+
+// EnsureRepository migrates the repository into the account of the user
+func (p *GiteaProvider) EnsureRepository(username string, repository workshopv1.GitRepositorySpec, private bool) (string, error) {
+	user, err := p.Client.GetUser(username)
+	if err != nil {
+		return "", err
+	}
+	repo, err := p.Client.EnsureMigratedRepo(user, repository.URL, repository.Name, repository.Branch, private)
+	if err != nil {
+		return "", err
+	}
+	return repo.CloneURL, nil
+}
+
+// EnsureWebhook registers the webhook on the repository of the user
+func (p *GiteaProvider) EnsureWebhook(username string, repositoryName string, hookURL string, webhook workshopv1.GitWebhookSpec) error {
+	_, err := p.Client.EnsureRepoHook(username, repositoryName, hookURL, webhook.ContentType, webhook.Secret, webhook.Events)
+	return err
+}
@@ -0,0 +1,235 @@
+package git
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+)
+
+const githubURL = "https://github.com"
+
+// GitHubProvider is the provider of GitHub or GitHub Enterprise Server, driven with an admin token. GitHub can
+// not import into user accounts, the repositories of the users are generated from template repositories into one
+// organization and named after the user. Accounts are only managed on GitHub Enterprise Server, on github.com
+// the users are existing accounts, mapped to the workshop users by logins, and are invited into the organization.
+type GitHubProvider struct {
+	serverURL    string
+	organization string
+	logins       map[string]string
+	client       *restClient
+}
+
+type githubUser struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+}
+
+type githubRepository struct {
+	Name          string `json:"name"`
+	DefaultBranch string `json:"default_branch"`
+	Private       bool   `json:"private"`
+	CloneURL      string `json:"clone_url"`
+}
+
+type githubHookConfig struct {
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Secret      string `json:"secret,omitempty"`
+}
+
+type githubHook struct {
+	ID     int64            `json:"id,omitempty"`
+	Active bool             `json:"active"`
+	Events []string         `json:"events"`
+	Config githubHookConfig `json:"config"`
+}
+
+// NewGitHubProvider returns a GitHubProvider, logins mapping the workshop users to their github.com accounts
+func NewGitHubProvider(serverURL string, organization string, token string, logins map[string]string) *GitHubProvider {
+	serverURL = strings.TrimSuffix(serverURL, "/")
+	apiURL := serverURL + "/api/v3"
+	if serverURL == githubURL {
+		apiURL = "https://api.github.com"
+	}
+	return &GitHubProvider{
+		serverURL:    serverURL,
+		organization: organization,
+		logins:       logins,
+		client: newRESTClient(apiURL, map[string]string{
+			"Accept":        "application/vnd.github.v3+json",
+			"Authorization": "token " + token,
+		}),
+	}
+}
+
+func (p *GitHubProvider) enterprise() bool {
+	return p.serverURL != githubURL
+}
+
+// login returns the GitHub account of the workshop user, only the mapped accounts being used on github.com so
+// that strangers named after the workshop users are never invited nor removed
+func (p *GitHubProvider) login(username string) (string, error) {
+	if p.enterprise() {
+		return username, nil
+	}
+	if login, ok := p.logins[username]; ok && login != "" {
+		return login, nil
+	}
+	return "", fmt.Errorf("git: no github.com login is mapped to %s", username)
+}
+
+// EnsureUser creates the user on GitHub Enterprise Server and makes it a member of the organization. Passwords
+// are not managed by the API, users set them from the invitation email.
+func (p *GitHubProvider) EnsureUser(username string, email string, password string) (string, error) {
+	login, err := p.login(username)
+	if err != nil {
+		return UserFailed, err
+	}
+
+	status := UserUnchanged
+	err = p.client.do(http.MethodGet, "/users/"+url.PathEscape(login), nil, &githubUser{})
+	if IsNotFound(err) {
+		if !p.enterprise() {
+			return UserFailed, fmt.Errorf("git: github.com user %s does not exist", login)
+		}
+		in := map[string]string{"login": login, "email": email}
+		if err := p.client.do(http.MethodPost, "/admin/users", in, nil); err != nil {
+			return UserFailed, err
+		}
+		status = UserCreated
+	} else if err != nil {
+		return UserFailed, err
+	}
+
+	membership := "/orgs/" + url.PathEscape(p.organization) + "/memberships/" + url.PathEscape(login)
+	if err := p.client.do(http.MethodGet, membership, nil, nil); IsNotFound(err) {
+		if err := p.client.do(http.MethodPut, membership, map[string]string{"role": "member"}, nil); err != nil {
+			return UserFailed, err
+		}
+		if status == UserUnchanged {
+			status = UserUpdated
+		}
+	} else if err != nil {
+		return UserFailed, err
+	}
+	return status, nil
+}
+
+// EnsureUserDeleted deletes the user on GitHub Enterprise Server, on github.com its mapped account is removed from
+// the organization
+func (p *GitHubProvider) EnsureUserDeleted(username string) (string, error) {
+	login, err := p.login(username)
+	if err != nil {
+		return UserFailed, err
+	}
+
+	path := "/admin/users/" + url.PathEscape(login)
+	if !p.enterprise() {
+		path = "/orgs/" + url.PathEscape(p.organization) + "/members/" + url.PathEscape(login)
+	}
+	err = p.client.do(http.MethodDelete, path, nil, nil)
+	if IsNotFound(err) {
+		return UserUnchanged, nil
+	} else if err != nil {
+		return UserFailed, err
+	}
+	return UserDeleted, nil
+}
+
+// templateRepository returns the owner and the name of a template repository hosted on the server
+func (p *GitHubProvider) templateRepository(cloneURL string) (string, string, error) {
+	path := strings.TrimSuffix(strings.TrimPrefix(cloneURL, p.serverURL+"/"), ".git")
+	parts := strings.Split(path, "/")
+	if !strings.HasPrefix(cloneURL, p.serverURL+"/") || len(parts) != 2 {
+		return "", "", fmt.Errorf("git: %s is not a template repository of %s", cloneURL, p.serverURL)
+	}
+	return parts[0], parts[1], nil
+}
+
+// EnsureRepository generates the repository of the user from its template and grants the user push access
+func (p *GitHubProvider) EnsureRepository(username string, repository workshopv1.GitRepositorySpec, private bool) (string, error) {
+	login, err := p.login(username)
+	if err != nil {
+		return "", err
+	}
+
+	name := repository.Name + "-" + username
+	repoPath := "/repos/" + url.PathEscape(p.organization) + "/" + url.PathEscape(name)
+
+	repo := &githubRepository{}
+	err = p.client.do(http.MethodGet, repoPath, nil, repo)
+	if IsNotFound(err) {
+		owner, template, err := p.templateRepository(repository.URL)
+		if err != nil {
+			return "", err
+		}
+		in := map[string]interface{}{
+			"owner":                p.organization,
+			"name":                 name,
+			"private":              private,
+			"include_all_branches": true,
+		}
+		if err := p.client.do(http.MethodPost, "/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(template)+"/generate", in, repo); err != nil {
+			return "", err
+		}
+	} else if err != nil {
+		return "", err
+	}
+
+	in := map[string]interface{}{}
+	if repo.Private != private {
+		in["private"] = private
+	}
+	if repository.Branch != "" && repo.DefaultBranch != "" && repo.DefaultBranch != repository.Branch {
+		in["default_branch"] = repository.Branch
+	}
+	if len(in) > 0 {
+		if err := p.client.do(http.MethodPatch, repoPath, in, repo); err != nil {
+			return "", err
+		}
+	}
+
+	if err := p.client.do(http.MethodPut, repoPath+"/collaborators/"+url.PathEscape(login), map[string]string{"permission": "push"}, nil); err != nil {
+		return "", err
+	}
+	return repo.CloneURL, nil
+}
+
+// EnsureWebhook registers the webhook on the repository of the user
+func (p *GitHubProvider) EnsureWebhook(username string, repositoryName string, hookURL string, webhook workshopv1.GitWebhookSpec) error {
+	hook := githubHook{
+		Active: true,
+		Events: webhook.Events,
+		Config: githubHookConfig{
+			URL:         hookURL,
+			ContentType: webhook.ContentType,
+			Secret:      webhook.Secret,
+		},
+	}
+	if len(hook.Events) == 0 {
+		hook.Events = []string{"push"}
+	}
+	if hook.Config.ContentType == "" {
+		hook.Config.ContentType = "json"
+	}
+
+	path := "/repos/" + url.PathEscape(p.organization) + "/" + url.PathEscape(repositoryName+"-"+username) + "/hooks"
+	hooks := []githubHook{}
+	if err := p.client.do(http.MethodGet, path, nil, &hooks); err != nil {
+		return err
+	}
+	for _, existing := range hooks {
+		if existing.Config.URL != hookURL {
+			continue
+		}
+		// The secret is not returned by the API, it is rewritten with the other settings
+		if existing.Active && existing.Config.ContentType == hook.Config.ContentType && sameEvents(existing.Events, hook.Events) {
+			return nil
+		}
+		return p.client.do(http.MethodPatch, fmt.Sprintf("%s/%d", path, existing.ID), hook, nil)
+	}
+	return p.client.do(http.MethodPost, path, hook, nil)
+}
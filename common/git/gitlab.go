@@ -0,0 +1,234 @@
+package git
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+)
+
+// GitLabProvider is the provider of an external GitLab server, driven with an admin personal access token
+type GitLabProvider struct {
+	serverURL string
+	client    *restClient
+}
+
+type gitlabUser struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+type gitlabProject struct {
+	ID            int64  `json:"id"`
+	DefaultBranch string `json:"default_branch"`
+	Visibility    string `json:"visibility"`
+	HTTPURLToRepo string `json:"http_url_to_repo"`
+}
+
+type gitlabHook struct {
+	ID                    int64  `json:"id,omitempty"`
+	URL                   string `json:"url"`
+	Token                 string `json:"token,omitempty"`
+	PushEvents            bool   `json:"push_events"`
+	TagPushEvents         bool   `json:"tag_push_events"`
+	MergeRequestsEvents   bool   `json:"merge_requests_events"`
+	EnableSSLVerification bool   `json:"enable_ssl_verification"`
+}
+
+// NewGitLabProvider returns a GitLabProvider
+func NewGitLabProvider(serverURL string, token string) *GitLabProvider {
+	serverURL = strings.TrimSuffix(serverURL, "/")
+	return &GitLabProvider{
+		serverURL: serverURL,
+		client:    newRESTClient(serverURL+"/api/v4", map[string]string{"Private-Token": token}),
+	}
+}
+
+func (p *GitLabProvider) getUser(username string) (*gitlabUser, error) {
+	users := []gitlabUser{}
+	if err := p.client.do(http.MethodGet, "/users?username="+url.QueryEscape(username), nil, &users); err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, &APIError{Method: http.MethodGet, Path: "/users/" + username, StatusCode: http.StatusNotFound}
+	}
+	return &users[0], nil
+}
+
+// checkedPasswords holds the digest of the password last known to be set for each user of a server, as every
+// failed password grant counts towards locking the account
+var checkedPasswords sync.Map
+
+func (p *GitLabProvider) passwordKey(username string) string {
+	return p.serverURL + "/" + username
+}
+
+func passwordDigest(password string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(password)))
+}
+
+// checkPassword returns true if the password grant accepts the credentials, the grant only being tried once per
+// password and operator run
+func (p *GitLabProvider) checkPassword(username string, password string) bool {
+	if digest, ok := checkedPasswords.Load(p.passwordKey(username)); ok {
+		return digest == passwordDigest(password)
+	}
+
+	form := url.Values{"grant_type": {"password"}, "username": {username}, "password": {password}}
+	resp, err := p.client.httpClient.PostForm(p.serverURL+"/oauth/token", form)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+	checkedPasswords.Store(p.passwordKey(username), passwordDigest(password))
+	return true
+}
+
+// EnsureUser creates the user or updates its email and password
+func (p *GitLabProvider) EnsureUser(username string, email string, password string) (string, error) {
+	user, err := p.getUser(username)
+	if IsNotFound(err) {
+		in := map[string]interface{}{
+			"username":          username,
+			"name":              username,
+			"email":             email,
+			"password":          password,
+			"skip_confirmation": true,
+		}
+		if err := p.client.do(http.MethodPost, "/users", in, nil); err != nil {
+			return UserFailed, err
+		}
+		checkedPasswords.Store(p.passwordKey(username), passwordDigest(password))
+		return UserCreated, nil
+	} else if err != nil {
+		return UserFailed, err
+	}
+
+	if user.Email == email && p.checkPassword(username, password) {
+		return UserUnchanged, nil
+	}
+
+	in := map[string]interface{}{
+		"email":               email,
+		"password":            password,
+		"skip_reconfirmation": true,
+	}
+	if err := p.client.do(http.MethodPut, fmt.Sprintf("/users/%d", user.ID), in, nil); err != nil {
+		return UserFailed, err
+	}
+	checkedPasswords.Store(p.passwordKey(username), passwordDigest(password))
+	return UserUpdated, nil
+}
+
+// EnsureUserDeleted deletes the user and its personal projects if it exists, GitLab keeping its contributions to
+// the other projects under the ghost user
+func (p *GitLabProvider) EnsureUserDeleted(username string) (string, error) {
+	user, err := p.getUser(username)
+	if IsNotFound(err) {
+		return UserUnchanged, nil
+	} else if err != nil {
+		return UserFailed, err
+	}
+
+	if err := p.client.do(http.MethodDelete, fmt.Sprintf("/users/%d", user.ID), nil, nil); err != nil && !IsNotFound(err) {
+		return UserFailed, err
+	}
+	checkedPasswords.Delete(p.passwordKey(username))
+	return UserDeleted, nil
+}
+
+func projectPath(owner string, name string) string {
+	return "/projects/" + url.PathEscape(owner+"/"+name)
+}
+
+// EnsureRepository imports the repository into the namespace of the user
+func (p *GitLabProvider) EnsureRepository(username string, repository workshopv1.GitRepositorySpec, private bool) (string, error) {
+	visibility := "public"
+	if private {
+		visibility = "private"
+	}
+
+	project := &gitlabProject{}
+	err := p.client.do(http.MethodGet, projectPath(username, repository.Name), nil, project)
+	if IsNotFound(err) {
+		user, err := p.getUser(username)
+		if err != nil {
+			return "", err
+		}
+		in := map[string]interface{}{
+			"name":       repository.Name,
+			"path":       repository.Name,
+			"import_url": repository.URL,
+			"visibility": visibility,
+		}
+		if err := p.client.do(http.MethodPost, fmt.Sprintf("/projects/user/%d", user.ID), in, project); err != nil {
+			return "", err
+		}
+	} else if err != nil {
+		return "", err
+	}
+
+	in := map[string]interface{}{}
+	if project.Visibility != visibility {
+		in["visibility"] = visibility
+	}
+	// The default branch can only be set once the import has fetched it, the next reconciliation retries
+	if repository.Branch != "" && project.DefaultBranch != "" && project.DefaultBranch != repository.Branch {
+		in["default_branch"] = repository.Branch
+	}
+	if len(in) > 0 {
+		if err := p.client.do(http.MethodPut, fmt.Sprintf("/projects/%d", project.ID), in, project); err != nil {
+			return "", err
+		}
+	}
+	return project.HTTPURLToRepo, nil
+}
+
+// EnsureWebhook registers the webhook on the project of the user, GitLab always sends JSON payloads
+func (p *GitLabProvider) EnsureWebhook(username string, repositoryName string, hookURL string, webhook workshopv1.GitWebhookSpec) error {
+	events := webhook.Events
+	if len(events) == 0 {
+		events = []string{"push"}
+	}
+	hook := gitlabHook{
+		URL:                   hookURL,
+		Token:                 webhook.Secret,
+		EnableSSLVerification: true,
+	}
+	for _, event := range events {
+		switch event {
+		case "push":
+			hook.PushEvents = true
+		case "create", "tag_push":
+			hook.TagPushEvents = true
+		case "pull_request", "merge_request":
+			hook.MergeRequestsEvents = true
+		}
+	}
+
+	path := projectPath(username, repositoryName) + "/hooks"
+	hooks := []gitlabHook{}
+	if err := p.client.do(http.MethodGet, path, nil, &hooks); err != nil {
+		return err
+	}
+	for _, existing := range hooks {
+		if existing.URL != hookURL {
+			continue
+		}
+		// The token is not returned by the API, it is rewritten with the events
+		if existing.PushEvents == hook.PushEvents && existing.TagPushEvents == hook.TagPushEvents &&
+			existing.MergeRequestsEvents == hook.MergeRequestsEvents {
+			return nil
+		}
+		return p.client.do(http.MethodPut, fmt.Sprintf("%s/%d", path, existing.ID), hook, nil)
+	}
+	return p.client.do(http.MethodPost, path, hook, nil)
+}
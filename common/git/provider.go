@@ -0,0 +1,123 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/gitea"
+)
+
+// Supported providers
+const (
+	ProviderGitea  = "gitea"
+	ProviderGitLab = "gitlab"
+	ProviderGitHub = "github"
+)
+
+// User reconciliation results
+const (
+	UserCreated   = gitea.UserCreated
+	UserUpdated   = gitea.UserUpdated
+	UserUnchanged = gitea.UserUnchanged
+	UserDeleted   = gitea.UserDeleted
+	UserFailed    = gitea.UserFailed
+)
+
+// Provider is a Git server hosting the repositories of the users
+type Provider interface {
+	// EnsureUser creates the user or updates its email and password, and returns what was done
+	EnsureUser(username string, email string, password string) (string, error)
+	// EnsureUserDeleted deletes the user if it exists, and returns what was done
+	EnsureUserDeleted(username string) (string, error)
+	// EnsureRepository imports the repository for the user if it does not exist yet, sets its default branch
+	// and visibility, and returns its clone URL
+	EnsureRepository(username string, repository workshopv1.GitRepositorySpec, private bool) (string, error)
+	// EnsureWebhook registers the webhook on the repository of the user
+	EnsureWebhook(username string, repositoryName string, hookURL string, webhook workshopv1.GitWebhookSpec) error
+}
+
+// ProviderName returns the provider of the workshop, gitea by default
+func ProviderName(workshop *workshopv1.Workshop) string {
+	if workshop.Spec.Infrastructure.Git.Provider == "" {
+		return ProviderGitea
+	}
+	return workshop.Spec.Infrastructure.Git.Provider
+}
+
+// NewExternalProvider returns the provider of an external server, authenticated with an admin token
+func NewExternalProvider(workshop *workshopv1.Workshop, token string) (Provider, error) {
+	spec := workshop.Spec.Infrastructure.Git
+	switch ProviderName(workshop) {
+	case ProviderGitLab:
+		if spec.URL == "" {
+			return nil, fmt.Errorf("git: the url of the gitlab server is required")
+		}
+		return NewGitLabProvider(spec.URL, token), nil
+	case ProviderGitHub:
+		if spec.Organization == "" {
+			return nil, fmt.Errorf("git: the github organization is required")
+		}
+		// The users of github.com are real people, never guessed from the workshop usernames
+		serverURL := strings.TrimSuffix(ServerURL(workshop, ""), "/")
+		if serverURL == githubURL && len(spec.Logins) == 0 {
+			return nil, fmt.Errorf("git: github.com requires the logins of the users, or the url of a GitHub Enterprise Server")
+		}
+		return NewGitHubProvider(serverURL, spec.Organization, token, spec.Logins), nil
+	default:
+		return nil, fmt.Errorf("git: %s is not an external provider", spec.Provider)
+	}
+}
+
+// ServerURL returns the URL of an external server, or defaultURL for the bundled Gitea
+func ServerURL(workshop *workshopv1.Workshop, defaultURL string) string {
+	spec := workshop.Spec.Infrastructure.Git
+	switch {
+	case ProviderName(workshop) == ProviderGitea:
+		return defaultURL
+	case spec.URL != "":
+		return spec.URL
+	case ProviderName(workshop) == ProviderGitHub:
+		return githubURL
+	default:
+		return defaultURL
+	}
+}
+
+// RepositoryOwner returns the account holding the repositories of the user
+func RepositoryOwner(workshop *workshopv1.Workshop, username string) string {
+	if ProviderName(workshop) == ProviderGitHub {
+		return workshop.Spec.Infrastructure.Git.Organization
+	}
+	return username
+}
+
+// RepositoryName returns the name of the repository of the user, GitHub repositories sharing one organization
+func RepositoryName(workshop *workshopv1.Workshop, username string, name string) string {
+	if ProviderName(workshop) == ProviderGitHub {
+		return name + "-" + username
+	}
+	return name
+}
+
+// Repositories returns the repositories to import for every user
+func Repositories(workshop *workshopv1.Workshop) []workshopv1.GitRepositorySpec {
+	spec := workshop.Spec.Infrastructure.Git.Repositories
+	branch := spec.Branch
+	if branch == "" {
+		branch = workshop.Spec.Source.GitBranch
+	}
+
+	repositories := []workshopv1.GitRepositorySpec{}
+	if spec.MirrorSource && workshop.Spec.Source.GitURL != "" {
+		repositories = append(repositories, workshopv1.GitRepositorySpec{URL: workshop.Spec.Source.GitURL, Branch: branch})
+	}
+	repositories = append(repositories, spec.Extra...)
+
+	for i := range repositories {
+		if repositories[i].Name == "" {
+			repositories[i].Name = gitea.RepositoryName(repositories[i].URL)
+		}
+	}
+	return repositories
+}
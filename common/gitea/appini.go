@@ -0,0 +1,65 @@
+package gitea
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"sort"
+	"strings"
+
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+)
+
+// AppIni holds the app.ini settings of Gitea by section, the empty section being the global one
+type AppIni map[string]map[string]string
+
+// Set sets a setting of a section
+func (a AppIni) Set(section string, key string, value string) {
+	if a[section] == nil {
+		a[section] = map[string]string{}
+	}
+	a[section][key] = value
+}
+
+// Merge applies the settings of overrides over a
+func (a AppIni) Merge(overrides map[string]workshopv1.GiteaConfigSection) {
+	for section, settings := range overrides {
+		for key, value := range settings {
+			a.Set(section, key, value)
+		}
+	}
+}
+
+// String renders the app.ini file, sorted by section and key
+func (a AppIni) String() string {
+	sections := make([]string, 0, len(a))
+	for section := range a {
+		sections = append(sections, section)
+	}
+	sort.Strings(sections)
+
+	var b strings.Builder
+	for _, section := range sections {
+		if section != "" {
+			b.WriteString("[" + section + "]\n")
+		}
+		keys := make([]string, 0, len(a[section]))
+		for key := range a[section] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			b.WriteString(key + " = " + a[section][key] + "\n")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// GenerateSecret returns a random base64 secret, as expected by the JWT settings of Gitea
+func GenerateSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(secret), nil
+}
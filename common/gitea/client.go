@@ -0,0 +1,193 @@
+package gitea
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client is a client of the Gitea API
+type Client struct {
+	baseURL    string
+	username   string
+	password   string
+	token      string
+	httpClient *http.Client
+}
+
+// APIError is returned when the Gitea API answers with an unexpected status code
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("gitea: %s %s returned %d: %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// IsNotFound returns true if the error is a Gitea API 404
+func IsNotFound(err error) bool {
+	apiErr, ok := err.(*APIError)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsUnauthorized returns true if the error is a Gitea API 401
+func IsUnauthorized(err error) bool {
+	apiErr, ok := err.(*APIError)
+	return ok && apiErr.StatusCode == http.StatusUnauthorized
+}
+
+// NewClient returns a Client authenticated with an access token
+func NewClient(baseURL string, token string) *Client {
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// NewBasicAuthClient returns a Client authenticated with a username and a password
+func NewBasicAuthClient(baseURL string, username string, password string) *Client {
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		username:   username,
+		password:   password,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (c *Client) do(method string, path string, in interface{}, out interface{}) error {
+	body := &bytes.Buffer{}
+	if in != nil {
+		if err := json.NewEncoder(body).Encode(in); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+"/api/v1"+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "token "+c.token)
+	} else {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := ioutil.ReadAll(resp.Body)
+		return &APIError{Method: method, Path: path, StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(message))}
+	}
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+// AccessToken is a Gitea access token
+type AccessToken struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Sha1 string `json:"sha1"`
+}
+
+// CreateAccessToken creates an access token for the authenticated user, requires basic auth
+func (c *Client) CreateAccessToken(name string) (*AccessToken, error) {
+	token := &AccessToken{}
+	err := c.do(http.MethodPost, "/users/"+url.PathEscape(c.username)+"/tokens", map[string]string{"name": name}, token)
+	return token, err
+}
+
+// User is a Gitea user
+type User struct {
+	ID       int64  `json:"id"`
+	Login    string `json:"login"`
+	FullName string `json:"full_name"`
+	Email    string `json:"email"`
+	IsAdmin  bool   `json:"is_admin"`
+}
+
+// CreateUserOption are the options to create a user
+type CreateUserOption struct {
+	Username           string `json:"username"`
+	Email              string `json:"email"`
+	FullName           string `json:"full_name,omitempty"`
+	Password           string `json:"password"`
+	MustChangePassword bool   `json:"must_change_password"`
+	SendNotify         bool   `json:"send_notify"`
+}
+
+// EditUserOption are the options to edit a user
+type EditUserOption struct {
+	LoginName string `json:"login_name"`
+	SourceID  int64  `json:"source_id"`
+	Email     string `json:"email,omitempty"`
+	FullName  string `json:"full_name,omitempty"`
+	Password  string `json:"password,omitempty"`
+	Admin     *bool  `json:"admin,omitempty"`
+}
+
+// CurrentUser returns the authenticated user
+func (c *Client) CurrentUser() (*User, error) {
+	user := &User{}
+	err := c.do(http.MethodGet, "/user", nil, user)
+	return user, err
+}
+
+// GetUser returns a user
+func (c *Client) GetUser(username string) (*User, error) {
+	user := &User{}
+	err := c.do(http.MethodGet, "/users/"+url.PathEscape(username), nil, user)
+	return user, err
+}
+
+// ListUsers returns all the users, requires admin rights
+func (c *Client) ListUsers() ([]User, error) {
+	users := []User{}
+	for page := 1; ; page++ {
+		pageUsers := []User{}
+		if err := c.do(http.MethodGet, fmt.Sprintf("/admin/users?page=%d&limit=50", page), nil, &pageUsers); err != nil {
+			return nil, err
+		}
+		if len(pageUsers) == 0 {
+			return users, nil
+		}
+		users = append(users, pageUsers...)
+	}
+}
+
+// CreateUser creates a user, requires admin rights
+func (c *Client) CreateUser(opt CreateUserOption) (*User, error) {
+	user := &User{}
+	err := c.do(http.MethodPost, "/admin/users", opt, user)
+	return user, err
+}
+
+// EditUser edits a user, requires admin rights
+func (c *Client) EditUser(username string, opt EditUserOption) (*User, error) {
+	user := &User{}
+	err := c.do(http.MethodPatch, "/admin/users/"+url.PathEscape(username), opt, user)
+	return user, err
+}
+
+// DeleteUser deletes a user and purges what it owns, requires admin rights
+func (c *Client) DeleteUser(username string) error {
+	return c.do(http.MethodDelete, "/admin/users/"+url.PathEscape(username)+"?purge=true", nil, nil)
+}
@@ -0,0 +1,233 @@
+package gitea
+
+import (
+	"github.com/prometheus/common/log"
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/kubernetes"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// Binary is the path of the gitea binary in the server image
+	Binary = "/home/gitea/gitea"
+	// ConfigDir is where app.ini is mounted in the server image
+	ConfigDir = "/home/gitea/conf"
+	// ConfigFile is the app.ini file of the server
+	ConfigFile = ConfigDir + "/app.ini"
+	// DataDir is where the data volume of the server is mounted
+	DataDir = "/gitea-data"
+	// HTTPPort is the HTTP port of the server
+	HTTPPort = 3000
+	// PostgreSQLPort is the port of the database
+	PostgreSQLPort = 5432
+)
+
+// NewServerDeployment create a deployment for the Gitea server
+func NewServerDeployment(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, namespace string, labels map[string]string, image string, resources corev1.ResourceRequirements,
+	configSecretName string, configChecksum string, dataClaimName string, sshPort int32) *appsv1.Deployment {
+
+	ports := []corev1.ContainerPort{
+		{
+			Name:          "http",
+			ContainerPort: HTTPPort,
+			Protocol:      "TCP",
+		},
+	}
+	if sshPort > 0 {
+		ports = append(ports, corev1.ContainerPort{
+			Name:          "ssh",
+			ContainerPort: sshPort,
+			Protocol:      "TCP",
+		})
+	}
+
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			// The data volume is ReadWriteOnce, upgrades stop the old server first
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RecreateDeploymentStrategyType,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+					Annotations: map[string]string{
+						// Rolls the server out when app.ini changes
+						kubernetes.ChecksumAnnotation: configChecksum,
+					},
+				},
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{
+						{
+							Name: "config",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									SecretName: configSecretName,
+								},
+							},
+						},
+						{
+							Name: "data",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: dataClaimName,
+								},
+							},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:            name,
+							Image:           image,
+							Command:         []string{Binary, "web", "--config", ConfigFile},
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							Ports:           ports,
+							Resources:       resources,
+							ReadinessProbe: &corev1.Probe{
+								Handler: corev1.Handler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Path: "/api/v1/version",
+										Port: intstr.FromInt(HTTPPort),
+									},
+								},
+								InitialDelaySeconds: 5,
+								TimeoutSeconds:      1,
+							},
+							LivenessProbe: &corev1.Probe{
+								Handler: corev1.Handler{
+									TCPSocket: &corev1.TCPSocketAction{
+										Port: intstr.FromInt(HTTPPort),
+									},
+								},
+								InitialDelaySeconds: 30,
+								TimeoutSeconds:      1,
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "config",
+									MountPath: ConfigDir,
+									ReadOnly:  true,
+								},
+								{
+									Name:      "data",
+									MountPath: DataDir,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// Set Workshop instance as the owner and controller
+	err := ctrl.SetControllerReference(workshop, dep, scheme)
+	if err != nil {
+		log.Error(err, "Failed to set SetControllerReference")
+	}
+	return dep
+}
+
+// NewPostgreSQLDeployment create a deployment for the database of the Gitea server
+func NewPostgreSQLDeployment(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, namespace string, labels map[string]string, image string, resources corev1.ResourceRequirements,
+	secretName string, dataClaimName string) *appsv1.Deployment {
+
+	secretEnv := func(envName string, key string) corev1.EnvVar {
+		return corev1.EnvVar{
+			Name: envName,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					Key: key,
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: secretName,
+					},
+				},
+			},
+		}
+	}
+
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RecreateDeploymentStrategyType,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{
+						{
+							Name: "data",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: dataClaimName,
+								},
+							},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:            name,
+							Image:           image,
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							Env: []corev1.EnvVar{
+								secretEnv("POSTGRESQL_USER", "database-user"),
+								secretEnv("POSTGRESQL_PASSWORD", "database-password"),
+								secretEnv("POSTGRESQL_DATABASE", "database-name"),
+							},
+							Ports: []corev1.ContainerPort{
+								{
+									Name:          "postgresql",
+									ContainerPort: PostgreSQLPort,
+									Protocol:      "TCP",
+								},
+							},
+							Resources: resources,
+							ReadinessProbe: &corev1.Probe{
+								Handler: corev1.Handler{
+									TCPSocket: &corev1.TCPSocketAction{
+										Port: intstr.FromInt(PostgreSQLPort),
+									},
+								},
+								InitialDelaySeconds: 5,
+								TimeoutSeconds:      1,
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "data",
+									MountPath: "/var/lib/pgsql/data",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// Set Workshop instance as the owner and controller
+	err := ctrl.SetControllerReference(workshop, dep, scheme)
+	if err != nil {
+		log.Error(err, "Failed to set SetControllerReference")
+	}
+	return dep
+}
@@ -0,0 +1,179 @@
+package gitea
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Team permissions
+const (
+	PermissionRead  = "read"
+	PermissionWrite = "write"
+	PermissionAdmin = "admin"
+)
+
+// teamUnits are the repository units a team has access to
+var teamUnits = []string{"repo.code", "repo.issues", "repo.pulls", "repo.releases", "repo.wiki"}
+
+// Organization is a Gitea organization
+type Organization struct {
+	ID         int64  `json:"id"`
+	UserName   string `json:"username"`
+	FullName   string `json:"full_name"`
+	Visibility string `json:"visibility"`
+}
+
+// CreateOrgOption are the options to create an organization
+type CreateOrgOption struct {
+	UserName   string `json:"username"`
+	FullName   string `json:"full_name,omitempty"`
+	Visibility string `json:"visibility,omitempty"`
+}
+
+// Team is a team of an organization
+type Team struct {
+	ID                      int64  `json:"id"`
+	Name                    string `json:"name"`
+	Description             string `json:"description"`
+	Permission              string `json:"permission"`
+	IncludesAllRepositories bool   `json:"includes_all_repositories"`
+	CanCreateOrgRepo        bool   `json:"can_create_org_repo"`
+}
+
+// CreateTeamOption are the options to create or edit a team
+type CreateTeamOption struct {
+	Name                    string   `json:"name"`
+	Description             string   `json:"description,omitempty"`
+	Permission              string   `json:"permission"`
+	IncludesAllRepositories bool     `json:"includes_all_repositories"`
+	CanCreateOrgRepo        bool     `json:"can_create_org_repo"`
+	Units                   []string `json:"units"`
+}
+
+// GetOrg returns an organization
+func (c *Client) GetOrg(name string) (*Organization, error) {
+	org := &Organization{}
+	err := c.do(http.MethodGet, "/orgs/"+url.PathEscape(name), nil, org)
+	return org, err
+}
+
+// CreateOrg creates an organization owned by the authenticated user
+func (c *Client) CreateOrg(opt CreateOrgOption) (*Organization, error) {
+	org := &Organization{}
+	err := c.do(http.MethodPost, "/orgs", opt, org)
+	return org, err
+}
+
+// ListOrgTeams returns the teams of an organization
+func (c *Client) ListOrgTeams(org string) ([]Team, error) {
+	teams := []Team{}
+	for page := 1; ; page++ {
+		pageTeams := []Team{}
+		if err := c.do(http.MethodGet, fmt.Sprintf("/orgs/%s/teams?page=%d&limit=50", url.PathEscape(org), page), nil, &pageTeams); err != nil {
+			return nil, err
+		}
+		if len(pageTeams) == 0 {
+			return teams, nil
+		}
+		teams = append(teams, pageTeams...)
+	}
+}
+
+// CreateTeam creates a team in an organization
+func (c *Client) CreateTeam(org string, opt CreateTeamOption) (*Team, error) {
+	team := &Team{}
+	err := c.do(http.MethodPost, "/orgs/"+url.PathEscape(org)+"/teams", opt, team)
+	return team, err
+}
+
+// EditTeam edits a team
+func (c *Client) EditTeam(id int64, opt CreateTeamOption) (*Team, error) {
+	team := &Team{}
+	err := c.do(http.MethodPatch, fmt.Sprintf("/teams/%d", id), opt, team)
+	return team, err
+}
+
+// DeleteTeam deletes a team
+func (c *Client) DeleteTeam(id int64) error {
+	return c.do(http.MethodDelete, fmt.Sprintf("/teams/%d", id), nil, nil)
+}
+
+// AddTeamMember adds a user to a team
+func (c *Client) AddTeamMember(id int64, username string) error {
+	return c.do(http.MethodPut, fmt.Sprintf("/teams/%d/members/%s", id, url.PathEscape(username)), nil, nil)
+}
+
+// AddCollaborator adds a user as collaborator of a repository
+func (c *Client) AddCollaborator(owner string, repo string, username string, permission string) error {
+	return c.do(http.MethodPut, "/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(repo)+"/collaborators/"+url.PathEscape(username),
+		map[string]string{"permission": permission}, nil)
+}
+
+// EnsureOrg creates the organization if it does not exist
+func (c *Client) EnsureOrg(opt CreateOrgOption) (*Organization, error) {
+	org, err := c.GetOrg(opt.UserName)
+	if IsNotFound(err) {
+		return c.CreateOrg(opt)
+	}
+	return org, err
+}
+
+// EnsureTeam creates the team or updates its permissions, and adds the members to it
+func (c *Client) EnsureTeam(org string, name string, description string, permission string,
+	includesAllRepositories bool, canCreateOrgRepo bool, members []string) (*Team, error) {
+
+	opt := CreateTeamOption{
+		Name:                    name,
+		Description:             description,
+		Permission:              permission,
+		IncludesAllRepositories: includesAllRepositories,
+		CanCreateOrgRepo:        canCreateOrgRepo,
+		Units:                   teamUnits,
+	}
+
+	teams, err := c.ListOrgTeams(org)
+	if err != nil {
+		return nil, err
+	}
+	var team *Team
+	for i := range teams {
+		if teams[i].Name == name {
+			team = &teams[i]
+			break
+		}
+	}
+
+	if team == nil {
+		if team, err = c.CreateTeam(org, opt); err != nil {
+			return nil, err
+		}
+	} else if team.Permission != permission || team.IncludesAllRepositories != includesAllRepositories ||
+		team.CanCreateOrgRepo != canCreateOrgRepo {
+		if team, err = c.EditTeam(team.ID, opt); err != nil {
+			return nil, err
+		}
+	}
+
+	// Adding an existing member is a no-op
+	for _, member := range members {
+		if err := c.AddTeamMember(team.ID, member); err != nil {
+			return nil, err
+		}
+	}
+	return team, nil
+}
+
+// EnsureTeamDeleted deletes the team of the organization if it exists
+func (c *Client) EnsureTeamDeleted(org string, name string) (bool, error) {
+	teams, err := c.ListOrgTeams(org)
+	if err != nil {
+		return false, err
+	}
+	for _, team := range teams {
+		if team.Name == name {
+			return true, c.DeleteTeam(team.ID)
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,117 @@
+package gitea
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Repository is a Gitea repository
+type Repository struct {
+	ID            int64  `json:"id"`
+	Owner         *User  `json:"owner"`
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	Private       bool   `json:"private"`
+	Empty         bool   `json:"empty"`
+	Mirror        bool   `json:"mirror"`
+	HTMLURL       string `json:"html_url"`
+	CloneURL      string `json:"clone_url"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+// MigrateRepoOption are the options to migrate a repository
+type MigrateRepoOption struct {
+	CloneAddr   string `json:"clone_addr"`
+	UID         int64  `json:"uid"`
+	RepoOwner   string `json:"repo_owner"`
+	RepoName    string `json:"repo_name"`
+	Mirror      bool   `json:"mirror"`
+	Private     bool   `json:"private"`
+	Description string `json:"description,omitempty"`
+}
+
+// EditRepoOption are the options to edit a repository
+type EditRepoOption struct {
+	Private       *bool   `json:"private,omitempty"`
+	DefaultBranch *string `json:"default_branch,omitempty"`
+}
+
+// Branch is a Gitea branch
+type Branch struct {
+	Name string `json:"name"`
+}
+
+// RepositoryName returns the name of a repository from its clone URL
+func RepositoryName(cloneURL string) string {
+	repoPath := cloneURL
+	if u, err := url.Parse(cloneURL); err == nil && u.Path != "" {
+		repoPath = u.Path
+	}
+	return strings.TrimSuffix(path.Base(strings.TrimSuffix(repoPath, "/")), ".git")
+}
+
+// GetRepo returns a repository
+func (c *Client) GetRepo(owner string, name string) (*Repository, error) {
+	repo := &Repository{}
+	err := c.do(http.MethodGet, "/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(name), nil, repo)
+	return repo, err
+}
+
+// MigrateRepo imports a remote repository
+func (c *Client) MigrateRepo(opt MigrateRepoOption) (*Repository, error) {
+	repo := &Repository{}
+	err := c.do(http.MethodPost, "/repos/migrate", opt, repo)
+	return repo, err
+}
+
+// EditRepo edits a repository
+func (c *Client) EditRepo(owner string, name string, opt EditRepoOption) (*Repository, error) {
+	repo := &Repository{}
+	err := c.do(http.MethodPatch, "/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(name), opt, repo)
+	return repo, err
+}
+
+// GetBranch returns a branch of a repository
+func (c *Client) GetBranch(owner string, name string, branch string) (*Branch, error) {
+	b := &Branch{}
+	err := c.do(http.MethodGet, "/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(name)+"/branches/"+url.PathEscape(branch), nil, b)
+	return b, err
+}
+
+// EnsureMigratedRepo migrates the repository into the account of the owner if it does not exist yet,
+// then sets its default branch and visibility
+func (c *Client) EnsureMigratedRepo(owner *User, cloneAddr string, name string, branch string, private bool) (*Repository, error) {
+	repo, err := c.GetRepo(owner.Login, name)
+	if IsNotFound(err) {
+		repo, err = c.MigrateRepo(MigrateRepoOption{
+			CloneAddr: cloneAddr,
+			UID:       owner.ID,
+			RepoOwner: owner.Login,
+			RepoName:  name,
+			Private:   private,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	edit := EditRepoOption{}
+	changed := false
+	if repo.Private != private {
+		edit.Private = &private
+		changed = true
+	}
+	if branch != "" && repo.DefaultBranch != branch {
+		if _, err := c.GetBranch(owner.Login, name, branch); err != nil {
+			return nil, err
+		}
+		edit.DefaultBranch = &branch
+		changed = true
+	}
+	if !changed {
+		return repo, nil
+	}
+	return c.EditRepo(owner.Login, name, edit)
+}
@@ -0,0 +1,55 @@
+package gitea
+
+// User reconciliation results
+const (
+	UserCreated   = "Created"
+	UserUpdated   = "Updated"
+	UserUnchanged = "Unchanged"
+	UserDeleted   = "Deleted"
+	UserFailed    = "Failed"
+)
+
+// EnsureUser creates the user or updates its email and password, and returns it with what was done
+func (c *Client) EnsureUser(opt CreateUserOption) (*User, string, error) {
+	user, err := c.GetUser(opt.Username)
+	if IsNotFound(err) {
+		if user, err = c.CreateUser(opt); err != nil {
+			return nil, UserFailed, err
+		}
+		return user, UserCreated, nil
+	} else if err != nil {
+		return nil, UserFailed, err
+	}
+
+	edit := EditUserOption{LoginName: opt.Username}
+	changed := false
+	if user.Email != opt.Email {
+		edit.Email = opt.Email
+		changed = true
+	}
+	// The password can not be read back, check it by authenticating as the user
+	if _, err := NewBasicAuthClient(c.baseURL, opt.Username, opt.Password).CurrentUser(); IsUnauthorized(err) {
+		edit.Password = opt.Password
+		changed = true
+	} else if err != nil {
+		return nil, UserFailed, err
+	}
+	if !changed {
+		return user, UserUnchanged, nil
+	}
+
+	if user, err = c.EditUser(opt.Username, edit); err != nil {
+		return nil, UserFailed, err
+	}
+	return user, UserUpdated, nil
+}
+
+// EnsureUserDeleted deletes the user if it exists, and returns what was done
+func (c *Client) EnsureUserDeleted(username string) (string, error) {
+	if err := c.DeleteUser(username); IsNotFound(err) {
+		return UserUnchanged, nil
+	} else if err != nil {
+		return UserFailed, err
+	}
+	return UserDeleted, nil
+}
@@ -0,0 +1,96 @@
+package gitea
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+)
+
+// Hook is a webhook of a repository
+type Hook struct {
+	ID     int64             `json:"id"`
+	Type   string            `json:"type"`
+	Config map[string]string `json:"config"`
+	Events []string          `json:"events"`
+	Active bool              `json:"active"`
+}
+
+// CreateHookOption are the options to create or edit a webhook
+type CreateHookOption struct {
+	Type   string            `json:"type,omitempty"`
+	Config map[string]string `json:"config"`
+	Events []string          `json:"events"`
+	Active bool              `json:"active"`
+}
+
+func hooksPath(owner string, repo string) string {
+	return "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) + "/hooks"
+}
+
+// ListRepoHooks returns the webhooks of a repository
+func (c *Client) ListRepoHooks(owner string, repo string) ([]Hook, error) {
+	hooks := []Hook{}
+	err := c.do(http.MethodGet, hooksPath(owner, repo), nil, &hooks)
+	return hooks, err
+}
+
+// CreateRepoHook creates a webhook on a repository
+func (c *Client) CreateRepoHook(owner string, repo string, opt CreateHookOption) (*Hook, error) {
+	hook := &Hook{}
+	err := c.do(http.MethodPost, hooksPath(owner, repo), opt, hook)
+	return hook, err
+}
+
+// EditRepoHook edits a webhook of a repository
+func (c *Client) EditRepoHook(owner string, repo string, id int64, opt CreateHookOption) (*Hook, error) {
+	hook := &Hook{}
+	err := c.do(http.MethodPatch, fmt.Sprintf("%s/%d", hooksPath(owner, repo), id), opt, hook)
+	return hook, err
+}
+
+// EnsureRepoHook creates the webhook of the repository pointing at hookURL, or updates its events.
+// Webhooks are identified by their URL.
+func (c *Client) EnsureRepoHook(owner string, repo string, hookURL string, contentType string, secret string, events []string) (*Hook, error) {
+	if contentType == "" {
+		contentType = "json"
+	}
+	if len(events) == 0 {
+		events = []string{"push"}
+	}
+	opt := CreateHookOption{
+		Type: "gitea",
+		Config: map[string]string{
+			"url":          hookURL,
+			"content_type": contentType,
+			"secret":       secret,
+		},
+		Events: events,
+		Active: true,
+	}
+
+	hooks, err := c.ListRepoHooks(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	for _, hook := range hooks {
+		if hook.Config["url"] != hookURL {
+			continue
+		}
+		if hook.Active && hook.Config["content_type"] == contentType && sameEvents(hook.Events, events) {
+			return &hook, nil
+		}
+		opt.Type = ""
+		return c.EditRepoHook(owner, repo, hook.ID, opt)
+	}
+	return c.CreateRepoHook(owner, repo, opt)
+}
+
+func sameEvents(a []string, b []string) bool {
+	a = append([]string{}, a...)
+	b = append([]string{}, b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,178 @@
+package imagepuller
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/common/log"
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/kubernetes"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// Defaults of the image puller
+const (
+	DefaultImageName         = "docker.io/library/busybox"
+	DefaultImageTag          = "1.36.1"
+	ImagesChecksumAnnotation = "workshop.stakater.com/images-checksum"
+	sleepMountPath           = "/puller"
+)
+
+// DevfileImages returns the images of the container components of a devfile, in the 1.0 or the 2.x schema,
+// in YAML or JSON
+func DevfileImages(devfile []byte) ([]string, error) {
+	devfileJSON, err := yaml.YAMLToJSON(devfile)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed struct {
+		Components []struct {
+			// Devfile 1.0
+			Image string `json:"image"`
+			// Devfile 2.x
+			Container struct {
+				Image string `json:"image"`
+			} `json:"container"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(devfileJSON, &parsed); err != nil {
+		return nil, err
+	}
+
+	images := []string{}
+	for _, component := range parsed.Components {
+		if component.Image != "" {
+			images = append(images, component.Image)
+		}
+		if component.Container.Image != "" {
+			images = append(images, component.Container.Image)
+		}
+	}
+	return images, nil
+}
+
+// SortedImages returns the images without duplicates nor empty names, sorted for a stable DaemonSet
+func SortedImages(images []string) []string {
+	found := map[string]bool{}
+	sorted := []string{}
+	for _, image := range images {
+		image = strings.TrimSpace(image)
+		if image == "" || found[image] {
+			continue
+		}
+		found[image] = true
+		sorted = append(sorted, image)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// ImagesChecksum returns the checksum of the images and the settings of the DaemonSet
+func ImagesChecksum(workshop *workshopv1.Workshop, images []string) (string, error) {
+	return kubernetes.Checksum([]interface{}{
+		images,
+		workshop.Spec.Infrastructure.ImagePuller.Image,
+		workshop.Spec.Infrastructure.ImagePuller.NodeSelector,
+		workshop.Spec.Infrastructure.ImagePuller.ImagePullSecrets,
+	})
+}
+
+// NewDaemonSet creates the DaemonSet pulling the images on every node. Each image runs as a container sleeping
+// with a static binary copied from the puller image, as the images may have no shell, so that a pod is ready once
+// all its images are pulled. The pull secrets must be in the namespace of the DaemonSet.
+func NewDaemonSet(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, namespace string, labels map[string]string, pullerImage string, images []string,
+	checksum string) *appsv1.DaemonSet {
+
+	resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1m"),
+			corev1.ResourceMemory: resource.MustParse("5Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("20m"),
+			corev1.ResourceMemory: resource.MustParse("20Mi"),
+		},
+	}
+	volumeMounts := []corev1.VolumeMount{
+		{Name: "puller", MountPath: sleepMountPath},
+	}
+
+	containers := []corev1.Container{}
+	for i, image := range images {
+		containers = append(containers, corev1.Container{
+			Name:            fmt.Sprintf("image-%d", i),
+			Image:           image,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Command:         []string{sleepMountPath + "/sleep", "720h"},
+			Resources:       resources,
+			VolumeMounts:    volumeMounts,
+		})
+	}
+
+	imagePullSecrets := []corev1.LocalObjectReference{}
+	for _, secretName := range workshop.Spec.Infrastructure.ImagePuller.ImagePullSecrets {
+		imagePullSecrets = append(imagePullSecrets, corev1.LocalObjectReference{Name: secretName})
+	}
+
+	terminationGracePeriodSeconds := int64(0)
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+			Annotations: map[string]string{
+				ImagesChecksumAnnotation: checksum,
+			},
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					NodeSelector:                  workshop.Spec.Infrastructure.ImagePuller.NodeSelector,
+					TerminationGracePeriodSeconds: &terminationGracePeriodSeconds,
+					ImagePullSecrets:              imagePullSecrets,
+					Volumes: []corev1.Volume{
+						{
+							Name: "puller",
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+					},
+					InitContainers: []corev1.Container{
+						{
+							Name:         "copy-sleep",
+							Image:        pullerImage,
+							Command:      []string{"/bin/cp", "/bin/sleep", sleepMountPath + "/sleep"},
+							Resources:    resources,
+							VolumeMounts: volumeMounts,
+						},
+					},
+					Containers: containers,
+				},
+			},
+		},
+	}
+
+	// Set Workshop instance as the owner and controller
+	err := ctrl.SetControllerReference(workshop, daemonSet, scheme)
+	if err != nil {
+		log.Error(err, "Failed to set SetControllerReference")
+	}
+	return daemonSet
+}
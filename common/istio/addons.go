@@ -0,0 +1,248 @@
+package istio
+
+import (
+	"fmt"
+
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/kubernetes"
+	"github.com/stakater/workshop-operator/common/maistra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+)
+
+// Names, ports and images of the addons, deployed next to the control plane
+const (
+	KialiName           = "kiali-server"
+	KialiPort           = 20001
+	KialiImage          = "quay.io/kiali/kiali:v2.18.0"
+	KialiConfigKey      = "config.yaml"
+	KialiSigningKey     = "key"
+	JaegerName          = "jaeger-server"
+	JaegerCollectorName = "jaeger-collector"
+	JaegerCollectorPort = 4317
+	JaegerQueryName     = "jaeger-query"
+	JaegerQueryPort     = 16686
+	JaegerImage         = "docker.io/jaegertracing/jaeger:2.12.0"
+	PrometheusName      = "prometheus"
+	PrometheusPort      = 9090
+	PrometheusImage     = "docker.io/prom/prometheus:v3.5.0"
+	PrometheusConfigKey = "prometheus.yml"
+)
+
+// PrometheusConfig scrapes the pods annotated by Istio, i.e. istiod, the gateways and the sidecars
+const PrometheusConfig = `global:
+  scrape_interval: 15s
+scrape_configs:
+- job_name: kubernetes-pods
+  kubernetes_sd_configs:
+  - role: pod
+  relabel_configs:
+  - source_labels: [__meta_kubernetes_pod_annotation_prometheus_io_scrape]
+    action: keep
+    regex: true
+  - source_labels: [__meta_kubernetes_pod_annotation_prometheus_io_path]
+    action: replace
+    target_label: __metrics_path__
+    regex: (.+)
+  - source_labels: [__address__, __meta_kubernetes_pod_annotation_prometheus_io_port]
+    action: replace
+    regex: ([^:]+)(?::\d+)?;(\d+)
+    replacement: $1:$2
+    target_label: __address__
+  - action: labelmap
+    regex: __meta_kubernetes_pod_label_(.+)
+  - source_labels: [__meta_kubernetes_namespace]
+    target_label: namespace
+  - source_labels: [__meta_kubernetes_pod_name]
+    target_label: pod
+`
+
+// NewKialiConfig returns the configuration of Kiali, authenticating the users with the OpenShift token passed by
+// the OAuth proxy in front of it, so that they only see the namespaces they can access. The traces are read from
+// the Jaeger query service, its UI being linked at jaegerURL.
+func NewKialiConfig(workshop *workshopv1.Workshop, namespace string, jaegerURL string) (string, error) {
+
+	tracing := map[string]interface{}{
+		"enabled": false,
+	}
+	if maistra.JaegerEnabled(workshop) {
+		tracing = map[string]interface{}{
+			"enabled":      true,
+			"provider":     "jaeger",
+			"use_grpc":     false,
+			"internal_url": fmt.Sprintf("http://%s.%s:%d", JaegerQueryName, namespace, JaegerQueryPort),
+			"external_url": jaegerURL,
+		}
+	}
+
+	config, err := yaml.Marshal(map[string]interface{}{
+		"auth": map[string]interface{}{
+			"strategy": "header",
+		},
+		"deployment": map[string]interface{}{
+			"cluster_wide_access": true,
+			"namespace":           namespace,
+		},
+		"istio_namespace": namespace,
+		"external_services": map[string]interface{}{
+			"grafana": map[string]interface{}{
+				"enabled": false,
+			},
+			"prometheus": map[string]interface{}{
+				"url": fmt.Sprintf("http://%s.%s:%d", PrometheusName, namespace, PrometheusPort),
+			},
+			"tracing": tracing,
+		},
+		"server": map[string]interface{}{
+			"port":     KialiPort,
+			"web_root": "/",
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(config), nil
+}
+
+// newAddonDeployment creates the Deployment of an addon, its pods being restarted when the checksum of their
+// configuration changes
+func newAddonDeployment(name string, namespace string, labels map[string]string, serviceAccountName string,
+	container corev1.Container, volumes []corev1.Volume, checksum string) *appsv1.Deployment {
+
+	container.ImagePullPolicy = corev1.PullIfNotPresent
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+					Annotations: map[string]string{
+						kubernetes.ChecksumAnnotation: checksum,
+						// Keep the addons out of the mesh they observe
+						"sidecar.istio.io/inject": "false",
+					},
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: serviceAccountName,
+					Volumes:            volumes,
+					Containers:         []corev1.Container{container},
+				},
+			},
+		},
+	}
+}
+
+// configVolume returns the volume of a ConfigMap
+func configVolume(configMapName string) corev1.Volume {
+	return corev1.Volume{
+		Name: "config",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: configMapName},
+			},
+		},
+	}
+}
+
+// httpProbe returns a probe of an HTTP path
+func httpProbe(path string, port int) *corev1.Probe {
+	return &corev1.Probe{
+		Handler: corev1.Handler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: path,
+				Port: intstr.FromInt(port),
+			},
+		},
+		InitialDelaySeconds: 5,
+		TimeoutSeconds:      1,
+	}
+}
+
+// NewKialiDeployment creates the Deployment of Kiali, its configuration being in the ConfigMap and the key signing
+// its sessions in the Secret
+func NewKialiDeployment(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, namespace string, labels map[string]string, serviceAccountName string, configMapName string,
+	signingKeySecretName string, checksum string) *appsv1.Deployment {
+
+	return newAddonDeployment(name, namespace, labels, serviceAccountName, corev1.Container{
+		Name:    "kiali",
+		Image:   KialiImage,
+		Command: []string{"/opt/kiali/kiali", "-config", "/kiali-configuration/" + KialiConfigKey},
+		Env: []corev1.EnvVar{
+			{
+				Name: "ACTIVE_NAMESPACE",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"},
+				},
+			},
+			{
+				Name: "LOGIN_TOKEN_SIGNING_KEY",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: signingKeySecretName},
+						Key:                  KialiSigningKey,
+					},
+				},
+			},
+		},
+		Ports: []corev1.ContainerPort{
+			{Name: "http", ContainerPort: KialiPort, Protocol: "TCP"},
+		},
+		ReadinessProbe: httpProbe("/healthz", KialiPort),
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "config", MountPath: "/kiali-configuration", ReadOnly: true},
+		},
+	}, []corev1.Volume{configVolume(configMapName)}, checksum)
+}
+
+// NewJaegerDeployment creates the Deployment of the Jaeger all-in-one, receiving the spans with OTLP and keeping
+// them in memory
+func NewJaegerDeployment(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, namespace string, labels map[string]string) *appsv1.Deployment {
+
+	return newAddonDeployment(name, namespace, labels, "", corev1.Container{
+		Name:  "jaeger",
+		Image: JaegerImage,
+		Ports: []corev1.ContainerPort{
+			{Name: "grpc-otlp", ContainerPort: JaegerCollectorPort, Protocol: "TCP"},
+			{Name: "http-query", ContainerPort: JaegerQueryPort, Protocol: "TCP"},
+		},
+		ReadinessProbe: httpProbe("/", JaegerQueryPort),
+	}, nil, "")
+}
+
+// NewPrometheusDeployment creates the Deployment of the Prometheus read by Kiali, keeping a day of metrics
+func NewPrometheusDeployment(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, namespace string, labels map[string]string, serviceAccountName string, configMapName string,
+	checksum string) *appsv1.Deployment {
+
+	return newAddonDeployment(name, namespace, labels, serviceAccountName, corev1.Container{
+		Name:  "prometheus",
+		Image: PrometheusImage,
+		Args: []string{
+			"--config.file=/etc/prometheus/" + PrometheusConfigKey,
+			"--storage.tsdb.path=/prometheus",
+			"--storage.tsdb.retention.time=1d",
+		},
+		Ports: []corev1.ContainerPort{
+			{Name: "http", ContainerPort: PrometheusPort, Protocol: "TCP"},
+		},
+		ReadinessProbe: httpProbe("/-/ready", PrometheusPort),
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "config", MountPath: "/etc/prometheus", ReadOnly: true},
+			{Name: "data", MountPath: "/prometheus"},
+		},
+	}, []corev1.Volume{
+		configVolume(configMapName),
+		{Name: "data", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+	}, checksum)
+}
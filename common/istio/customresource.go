@@ -0,0 +1,136 @@
+package istio
+
+import (
+	"fmt"
+
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/maistra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Defaults of upstream Istio
+const (
+	DefaultHub           = "docker.io/istio"
+	DefaultVersion       = "1.28.1"
+	Profile              = "openshift"
+	TracingProvider      = "jaeger"
+	IngressGatewayName   = "istio-ingressgateway"
+	CNINetworkAttachment = "istio-cni"
+)
+
+var (
+	// IstioOperatorGroupVersionKind of the configuration installed by istioctl
+	IstioOperatorGroupVersionKind = schema.GroupVersionKind{Group: "install.istio.io", Version: "v1alpha1", Kind: "IstioOperator"}
+	// PeerAuthenticationGroupVersionKind of the mesh-wide mTLS policy
+	PeerAuthenticationGroupVersionKind = schema.GroupVersionKind{Group: "security.istio.io", Version: "v1", Kind: "PeerAuthentication"}
+	// NetworkAttachmentDefinitionGroupVersionKind of the Istio CNI plugin, required by Multus in the injected namespaces
+	NetworkAttachmentDefinitionGroupVersionKind = schema.GroupVersionKind{Group: "k8s.cni.cncf.io", Version: "v1", Kind: "NetworkAttachmentDefinition"}
+)
+
+// Hub returns the hub of the Istio images
+func Hub(workshop *workshopv1.Workshop) string {
+	if hub := workshop.Spec.Infrastructure.ServiceMesh.Istio.Hub; hub != "" {
+		return hub
+	}
+	return DefaultHub
+}
+
+// Version returns the version of Istio
+func Version(workshop *workshopv1.Workshop) string {
+	if version := workshop.Spec.Infrastructure.ServiceMesh.Istio.Version; version != "" {
+		return version
+	}
+	return DefaultVersion
+}
+
+// NewIstioOperatorConfig create the IstioOperator configuration installed by istioctl from the control plane of the
+// Workshop, the spans being sent to the Jaeger collector of the namespace through OpenTelemetry
+func NewIstioOperatorConfig(workshop *workshopv1.Workshop, name string, namespace string) *unstructured.Unstructured {
+
+	controlPlane := workshop.Spec.Infrastructure.ServiceMesh.ControlPlane
+
+	sampling := maistra.DefaultTracingSampling
+	if controlPlane.TracingSampling != nil {
+		sampling = *controlPlane.TracingSampling
+	}
+
+	ingressGateway := map[string]interface{}{
+		"name":    IngressGatewayName,
+		"enabled": true,
+	}
+	if controlPlane.IngressGatewayReplicas != nil {
+		ingressGateway["k8s"] = map[string]interface{}{
+			"replicaCount": int64(*controlPlane.IngressGatewayReplicas),
+		}
+	}
+
+	meshConfig := map[string]interface{}{}
+	if maistra.JaegerEnabled(workshop) {
+		meshConfig["extensionProviders"] = []interface{}{
+			map[string]interface{}{
+				"name": TracingProvider,
+				"opentelemetry": map[string]interface{}{
+					"service": fmt.Sprintf("%s.%s.svc.cluster.local", JaegerCollectorName, namespace),
+					"port":    int64(JaegerCollectorPort),
+				},
+			},
+		}
+		meshConfig["defaultProviders"] = map[string]interface{}{
+			"tracing": []interface{}{TracingProvider},
+		}
+	}
+
+	config := &unstructured.Unstructured{}
+	config.SetGroupVersionKind(IstioOperatorGroupVersionKind)
+	config.SetName(name)
+	config.SetNamespace(namespace)
+	config.Object["spec"] = map[string]interface{}{
+		"profile":    Profile,
+		"hub":        Hub(workshop),
+		"tag":        Version(workshop),
+		"namespace":  namespace,
+		"meshConfig": meshConfig,
+		"components": map[string]interface{}{
+			"ingressGateways": []interface{}{ingressGateway},
+		},
+		"values": map[string]interface{}{
+			"pilot": map[string]interface{}{
+				// Percentage of the requests traced, the control plane spec being in hundredths of a percent
+				"traceSampling": float64(sampling) / 100,
+			},
+		},
+	}
+	return config
+}
+
+// NewPeerAuthenticationCR create the PeerAuthentication Custom Resource requiring mTLS in the whole mesh
+func NewPeerAuthenticationCR(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, namespace string, labels map[string]string) *unstructured.Unstructured {
+
+	cr := &unstructured.Unstructured{}
+	cr.SetGroupVersionKind(PeerAuthenticationGroupVersionKind)
+	cr.SetName(name)
+	cr.SetNamespace(namespace)
+	cr.SetLabels(labels)
+	cr.Object["spec"] = map[string]interface{}{
+		"mtls": map[string]interface{}{
+			"mode": "STRICT",
+		},
+	}
+	return cr
+}
+
+// NewNetworkAttachmentDefinitionCR create the NetworkAttachmentDefinition letting Multus run the Istio CNI plugin
+// for the pods of a namespace
+func NewNetworkAttachmentDefinitionCR(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	namespace string, labels map[string]string) *unstructured.Unstructured {
+
+	cr := &unstructured.Unstructured{}
+	cr.SetGroupVersionKind(NetworkAttachmentDefinitionGroupVersionKind)
+	cr.SetName(CNINetworkAttachment)
+	cr.SetNamespace(namespace)
+	cr.SetLabels(labels)
+	return cr
+}
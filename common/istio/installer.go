@@ -0,0 +1,94 @@
+package istio
+
+import (
+	"github.com/prometheus/common/log"
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/kubernetes"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// Mount path and key of the IstioOperator configuration of the installer
+const (
+	ConfigKey       = "istio.yaml"
+	configMountPath = "/etc/istio/config"
+)
+
+// InstallArgs returns the istioctl arguments installing the configuration of the ConfigMap
+func InstallArgs() []string {
+	return []string{"install", "--skip-confirmation", "--filename", configMountPath + "/" + ConfigKey}
+}
+
+// UninstallArgs returns the istioctl arguments removing every Istio resource of the cluster
+func UninstallArgs() []string {
+	return []string{"uninstall", "--purge", "--skip-confirmation"}
+}
+
+// NewInstallerJob creates a Job running istioctl with args, in the version of the mesh, with the IstioOperator
+// configuration of the ConfigMap, annotated with its checksum
+func NewInstallerJob(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, namespace string, labels map[string]string, serviceAccountName string, configMapName string,
+	checksum string, args []string) *batchv1.Job {
+
+	backoffLimit := int32(2)
+	// The configuration is only read when installing
+	optional := true
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+			Annotations: map[string]string{
+				kubernetes.ChecksumAnnotation: checksum,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: serviceAccountName,
+					RestartPolicy:      corev1.RestartPolicyNever,
+					Volumes: []corev1.Volume{
+						{
+							Name: "config",
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{Name: configMapName},
+									Optional:             &optional,
+								},
+							},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:            "istioctl",
+							Image:           Hub(workshop) + "/istioctl:" + Version(workshop),
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							Args:            args,
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "config",
+									MountPath: configMountPath,
+									ReadOnly:  true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// Set Workshop instance as the owner and controller
+	err := ctrl.SetControllerReference(workshop, job, scheme)
+	if err != nil {
+		log.Error(err, "Failed to set SetControllerReference")
+	}
+	return job
+}
@@ -0,0 +1,260 @@
+package istio
+
+import (
+	rbac "k8s.io/api/rbac/v1"
+)
+
+// NewRules returns the rules of istioctl, installing and uninstalling the components of the control plane
+func NewRules() []rbac.PolicyRule {
+	return []rbac.PolicyRule{
+		{
+			APIGroups: []string{
+				"extensions.istio.io",
+				"networking.istio.io",
+				"security.istio.io",
+				"telemetry.istio.io",
+			},
+			Resources: []string{
+				"*",
+			},
+			Verbs: []string{
+				"*",
+			},
+		},
+		{
+			APIGroups: []string{
+				"admissionregistration.k8s.io",
+			},
+			Resources: []string{
+				"mutatingwebhookconfigurations",
+				"validatingwebhookconfigurations",
+			},
+			Verbs: []string{
+				"*",
+			},
+		},
+		{
+			APIGroups: []string{
+				"apiextensions.k8s.io",
+			},
+			Resources: []string{
+				"customresourcedefinitions",
+			},
+			Verbs: []string{
+				"*",
+			},
+		},
+		{
+			APIGroups: []string{
+				"apps",
+				"extensions",
+			},
+			Resources: []string{
+				"daemonsets",
+				"deployments",
+				"deployments/finalizers",
+				"ingresses",
+				"replicasets",
+				"statefulsets",
+			},
+			Verbs: []string{
+				"*",
+			},
+		},
+		{
+			APIGroups: []string{
+				"autoscaling",
+			},
+			Resources: []string{
+				"horizontalpodautoscalers",
+			},
+			Verbs: []string{
+				"*",
+			},
+		},
+		{
+			APIGroups: []string{
+				"monitoring.coreos.com",
+			},
+			Resources: []string{
+				"servicemonitors",
+			},
+			Verbs: []string{
+				"get",
+				"create",
+			},
+		},
+		{
+			APIGroups: []string{
+				"policy",
+			},
+			Resources: []string{
+				"poddisruptionbudgets",
+			},
+			Verbs: []string{
+				"*",
+			},
+		},
+		{
+			APIGroups: []string{
+				"rbac.authorization.k8s.io",
+			},
+			Resources: []string{
+				"clusterrolebindings",
+				"clusterroles",
+				"roles",
+				"rolebindings",
+			},
+			Verbs: []string{
+				"*",
+			},
+		},
+		{
+			APIGroups: []string{
+				"",
+			},
+			Resources: []string{
+				"configmaps",
+				"endpoints",
+				"events",
+				"namespaces",
+				"pods",
+				"persistentvolumeclaims",
+				"secrets",
+				"services",
+				"serviceaccounts",
+			},
+			Verbs: []string{
+				"*",
+			},
+		},
+	}
+}
+
+// KialiRules returns the rules of the Kiali service account, reading the mesh of the cluster, the users acting with
+// their own token
+func KialiRules() []rbac.PolicyRule {
+	return []rbac.PolicyRule{
+		{
+			APIGroups: []string{
+				"",
+			},
+			Resources: []string{
+				"configmaps",
+				"endpoints",
+				"namespaces",
+				"nodes",
+				"pods",
+				"pods/log",
+				"replicationcontrollers",
+				"services",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+			},
+		},
+		{
+			APIGroups: []string{
+				"apps",
+				"autoscaling",
+				"batch",
+			},
+			Resources: []string{
+				"cronjobs",
+				"daemonsets",
+				"deployments",
+				"horizontalpodautoscalers",
+				"jobs",
+				"replicasets",
+				"statefulsets",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+			},
+		},
+		{
+			APIGroups: []string{
+				"admissionregistration.k8s.io",
+				"extensions.istio.io",
+				"gateway.networking.k8s.io",
+				"networking.istio.io",
+				"security.istio.io",
+				"telemetry.istio.io",
+			},
+			Resources: []string{
+				"*",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+			},
+		},
+		{
+			APIGroups: []string{
+				"apps.openshift.io",
+				"project.openshift.io",
+				"route.openshift.io",
+			},
+			Resources: []string{
+				"deploymentconfigs",
+				"projects",
+				"routes",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+			},
+		},
+		{
+			APIGroups: []string{
+				"authentication.k8s.io",
+			},
+			Resources: []string{
+				"tokenreviews",
+			},
+			Verbs: []string{
+				"create",
+			},
+		},
+		{
+			APIGroups: []string{
+				"authorization.k8s.io",
+			},
+			Resources: []string{
+				"selfsubjectaccessreviews",
+				"subjectaccessreviews",
+			},
+			Verbs: []string{
+				"create",
+			},
+		},
+	}
+}
+
+// PrometheusRules returns the rules of the Prometheus service account, discovering the pods to scrape
+func PrometheusRules() []rbac.PolicyRule {
+	return []rbac.PolicyRule{
+		{
+			APIGroups: []string{
+				"",
+			},
+			Resources: []string{
+				"endpoints",
+				"nodes",
+				"pods",
+				"services",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+			},
+		},
+	}
+}
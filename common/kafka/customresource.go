@@ -0,0 +1,217 @@
+package kafka
+
+import (
+	"fmt"
+
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/kubernetes"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Defaults, labels and annotations of the Strimzi Custom Resources
+const (
+	DefaultReplicas     = 1
+	DefaultPartitions   = 1
+	ListenerName        = "plain"
+	ListenerPort        = 9092
+	NodePoolName        = "dual-role"
+	ClusterLabel        = "strimzi.io/cluster"
+	NodePoolsAnnotation = "strimzi.io/node-pools"
+	KRaftAnnotation     = "strimzi.io/kraft"
+	AnnotationEnabled   = "enabled"
+)
+
+var (
+	// KafkaGroupVersionKind of the Kafka clusters
+	KafkaGroupVersionKind = schema.GroupVersionKind{Group: "kafka.strimzi.io", Version: "v1beta2", Kind: "Kafka"}
+	// KafkaNodePoolGroupVersionKind of the nodes of the clusters
+	KafkaNodePoolGroupVersionKind = schema.GroupVersionKind{Group: "kafka.strimzi.io", Version: "v1beta2", Kind: "KafkaNodePool"}
+	// KafkaTopicGroupVersionKind of the topics of the users
+	KafkaTopicGroupVersionKind = schema.GroupVersionKind{Group: "kafka.strimzi.io", Version: "v1beta2", Kind: "KafkaTopic"}
+	// KafkaUserGroupVersionKind of the users authenticating to the clusters
+	KafkaUserGroupVersionKind = schema.GroupVersionKind{Group: "kafka.strimzi.io", Version: "v1beta2", Kind: "KafkaUser"}
+)
+
+// Replicas returns the number of nodes of a cluster, each of them being both a KRaft controller and a broker
+func Replicas(workshop *workshopv1.Workshop) int64 {
+	if replicas := workshop.Spec.Infrastructure.Kafka.Replicas; replicas > 0 {
+		return int64(replicas)
+	}
+	return DefaultReplicas
+}
+
+// BootstrapServers returns the address of the bootstrap service of a cluster
+func BootstrapServers(clusterName string, namespace string) string {
+	return fmt.Sprintf("%s-kafka-bootstrap.%s.svc:%d", clusterName, namespace, ListenerPort)
+}
+
+// storage returns the storage of the nodes, holding both the logs and the KRaft metadata
+func storage(workshop *workshopv1.Workshop) map[string]interface{} {
+	if size := workshop.Spec.Infrastructure.Kafka.StorageSize; size != "" {
+		return map[string]interface{}{
+			"type": "jbod",
+			"volumes": []interface{}{
+				map[string]interface{}{
+					"id":            int64(0),
+					"type":          "persistent-claim",
+					"size":          size,
+					"deleteClaim":   true,
+					"kraftMetadata": "shared",
+				},
+			},
+		}
+	}
+	return map[string]interface{}{
+		"type": "ephemeral",
+	}
+}
+
+// NewKafkaCR create a KRaft Kafka Custom Resource, its nodes being in a KafkaNodePool, with a plain listener
+// authenticating the users with SCRAM-SHA-512, annotated with the checksum of its spec
+func NewKafkaCR(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, namespace string, labels map[string]string) (*unstructured.Unstructured, error) {
+
+	replicas := Replicas(workshop)
+	minInSyncReplicas := replicas - 1
+	if minInSyncReplicas < 1 {
+		minInSyncReplicas = 1
+	}
+
+	kafka := map[string]interface{}{
+		"listeners": []interface{}{
+			map[string]interface{}{
+				"name": ListenerName,
+				"port": int64(ListenerPort),
+				"type": "internal",
+				"tls":  false,
+				"authentication": map[string]interface{}{
+					"type": "scram-sha-512",
+				},
+			},
+		},
+		"authorization": map[string]interface{}{
+			"type": "simple",
+		},
+		"config": map[string]interface{}{
+			"offsets.topic.replication.factor":         replicas,
+			"transaction.state.log.replication.factor": replicas,
+			"transaction.state.log.min.isr":            minInSyncReplicas,
+			"default.replication.factor":               replicas,
+			"min.insync.replicas":                      minInSyncReplicas,
+		},
+	}
+	if version := workshop.Spec.Infrastructure.Kafka.Version; version != "" {
+		kafka["version"] = version
+	}
+
+	cr, err := kubernetes.NewChecksumCustomResource(KafkaGroupVersionKind, name, namespace, labels, map[string]interface{}{
+		"kafka": kafka,
+		"entityOperator": map[string]interface{}{
+			"topicOperator": map[string]interface{}{},
+			"userOperator":  map[string]interface{}{},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	annotations := cr.GetAnnotations()
+	annotations[NodePoolsAnnotation] = AnnotationEnabled
+	annotations[KRaftAnnotation] = AnnotationEnabled
+	cr.SetAnnotations(annotations)
+	return cr, nil
+}
+
+// NewKafkaNodePoolCR create the KafkaNodePool of a cluster, its nodes being both KRaft controllers and brokers,
+// annotated with the checksum of its spec
+func NewKafkaNodePoolCR(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, namespace string, labels map[string]string, clusterName string) (*unstructured.Unstructured, error) {
+
+	return kubernetes.NewChecksumCustomResource(KafkaNodePoolGroupVersionKind, name, namespace, clusterLabels(clusterName, labels),
+		map[string]interface{}{
+			"replicas": Replicas(workshop),
+			"roles": []interface{}{
+				"controller",
+				"broker",
+			},
+			"storage": storage(workshop),
+		})
+}
+
+// clusterLabels returns the labels binding a topic or a user to its cluster
+func clusterLabels(clusterName string, labels map[string]string) map[string]string {
+	clusterLabels := map[string]string{
+		ClusterLabel: clusterName,
+	}
+	for key, value := range labels {
+		clusterLabels[key] = value
+	}
+	return clusterLabels
+}
+
+// NewKafkaTopicCR create a KafkaTopic Custom Resource of a cluster
+func NewKafkaTopicCR(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, namespace string, labels map[string]string, clusterName string, topic workshopv1.KafkaTopicSpec) *unstructured.Unstructured {
+
+	partitions := int64(DefaultPartitions)
+	if topic.Partitions > 0 {
+		partitions = int64(topic.Partitions)
+	}
+	replicas := Replicas(workshop)
+	if topic.Replicas > 0 {
+		replicas = int64(topic.Replicas)
+	}
+
+	cr := &unstructured.Unstructured{}
+	cr.SetGroupVersionKind(KafkaTopicGroupVersionKind)
+	cr.SetName(name)
+	cr.SetNamespace(namespace)
+	cr.SetLabels(clusterLabels(clusterName, labels))
+	cr.Object["spec"] = map[string]interface{}{
+		"topicName":  name,
+		"partitions": partitions,
+		"replicas":   replicas,
+	}
+	return cr
+}
+
+// NewKafkaUserCR create a KafkaUser Custom Resource of a cluster, authenticated with SCRAM-SHA-512 and allowed to
+// use the topics and the consumer groups starting with the prefix, or all of them when the prefix is empty
+func NewKafkaUserCR(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, namespace string, labels map[string]string, clusterName string, prefix string) *unstructured.Unstructured {
+
+	resourceName, patternType := prefix, "prefix"
+	if prefix == "" {
+		resourceName, patternType = "*", "literal"
+	}
+
+	acls := []interface{}{}
+	for _, resourceType := range []string{"topic", "group"} {
+		acls = append(acls, map[string]interface{}{
+			"resource": map[string]interface{}{
+				"type":        resourceType,
+				"name":        resourceName,
+				"patternType": patternType,
+			},
+			"operation": "All",
+		})
+	}
+
+	cr := &unstructured.Unstructured{}
+	cr.SetGroupVersionKind(KafkaUserGroupVersionKind)
+	cr.SetName(name)
+	cr.SetNamespace(namespace)
+	cr.SetLabels(clusterLabels(clusterName, labels))
+	cr.Object["spec"] = map[string]interface{}{
+		"authentication": map[string]interface{}{
+			"type": "scram-sha-512",
+		},
+		"authorization": map[string]interface{}{
+			"type": "simple",
+			"acls": acls,
+		},
+	}
+	return cr
+}
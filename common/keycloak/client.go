@@ -0,0 +1,209 @@
+package keycloak
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stakater/workshop-operator/common/util"
+)
+
+// Retries of a request failing with a network error or a 5xx/429 status code
+const (
+	maxAttempts  = 4
+	retryBackoff = time.Second
+)
+
+// tokenExpiryMargin is how long before its expiry an admin token is renewed
+const tokenExpiryMargin = 30 * time.Second
+
+// Client is a client of the Keycloak admin API, authenticated as an admin of the master realm
+type Client struct {
+	baseURL       string
+	adminUsername string
+	adminPassword string
+	httpClient    *http.Client
+
+	mutex       sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// APIError is returned when Keycloak answers with an unexpected status code
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("keycloak: %s %s returned %d: %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// IsNotFound returns true if the error is a Keycloak API 404
+func IsNotFound(err error) bool {
+	apiErr, ok := err.(*APIError)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsUnauthorized returns true if Keycloak rejected the credentials of a request
+func IsUnauthorized(err error) bool {
+	apiErr, ok := err.(*APIError)
+	return ok && (apiErr.StatusCode == http.StatusUnauthorized ||
+		(apiErr.StatusCode == http.StatusBadRequest && strings.Contains(apiErr.Message, "invalid_grant")))
+}
+
+// NewClient returns a Client of the Keycloak at baseURL, its /auth context path included. The TLS configuration
+// holds the CAs trusted for the route of Keycloak.
+func NewClient(baseURL string, adminUsername string, adminPassword string, tlsConfig *tls.Config) *Client {
+	return &Client{
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		adminUsername: adminUsername,
+		adminPassword: adminPassword,
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+			// Do not follow Redirect
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// adminToken returns the cached access token of the admin, requesting a new one when it is about to expire
+func (c *Client) adminToken() (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.token != "" && time.Now().Before(c.tokenExpiry) {
+		return c.token, nil
+	}
+
+	token, err := c.requestToken("master", url.Values{
+		"username":   {c.adminUsername},
+		"password":   {c.adminPassword},
+		"grant_type": {"password"},
+		"client_id":  {"admin-cli"},
+	})
+	if err != nil {
+		return "", err
+	}
+	c.token = token.AccessToken
+	c.tokenExpiry = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - tokenExpiryMargin)
+	return c.token, nil
+}
+
+// resetAdminToken drops the cached admin token
+func (c *Client) resetAdminToken() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.token = ""
+}
+
+// requestToken requests a token from the OpenID Connect endpoint of the realm
+func (c *Client) requestToken(realm string, data url.Values) (*util.Token, error) {
+	token := &util.Token{}
+	path := "/realms/" + url.PathEscape(realm) + "/protocol/openid-connect/token"
+	err := c.send(http.MethodPost, path, "application/x-www-form-urlencoded", []byte(data.Encode()), "", token)
+	return token, err
+}
+
+// UserToken returns an access token of the user of the realm, authenticated with its password
+func (c *Client) UserToken(realm string, clientID string, username string, password string) (string, error) {
+	token, err := c.requestToken(realm, url.Values{
+		"username":   {username},
+		"password":   {password},
+		"grant_type": {"password"},
+		"client_id":  {clientID},
+	})
+	if err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+// do sends a request to the admin API, renewing the admin token once if it was rejected
+func (c *Client) do(method string, path string, in interface{}, out interface{}) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+
+	for renewed := false; ; renewed = true {
+		token, err := c.adminToken()
+		if err != nil {
+			return err
+		}
+		err = c.send(method, "/admin"+path, "application/json", body, token, out)
+		if apiErr, ok := err.(*APIError); ok && apiErr.StatusCode == http.StatusUnauthorized && !renewed {
+			c.resetAdminToken()
+			continue
+		}
+		return err
+	}
+}
+
+// send sends a request, retrying with a backoff on network errors and on 5xx and 429 status codes
+func (c *Client) send(method string, path string, contentType string, body []byte, token string, out interface{}) error {
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(retryBackoff << uint(attempt-1))
+		}
+
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		req, reqErr := http.NewRequest(method, c.baseURL+path, reader)
+		if reqErr != nil {
+			return reqErr
+		}
+		req.Header.Set("Accept", "application/json")
+		if body != nil {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		var resp *http.Response
+		resp, err = c.httpClient.Do(req)
+		if err != nil {
+			continue
+		}
+		err = readResponse(method, path, resp, out)
+		if apiErr, ok := err.(*APIError); ok &&
+			(apiErr.StatusCode >= http.StatusInternalServerError || apiErr.StatusCode == http.StatusTooManyRequests) {
+			continue
+		}
+		return err
+	}
+	return err
+}
+
+func readResponse(method string, path string, resp *http.Response, out interface{}) error {
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := ioutil.ReadAll(resp.Body)
+		return &APIError{Method: method, Path: path, StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(message))}
+	}
+	if out != nil && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusCreated {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package keycloak
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// User is a Keycloak user
+type User struct {
+	ID          string              `json:"id,omitempty"`
+	Username    string              `json:"username"`
+	Enabled     bool                `json:"enabled"`
+	Email       string              `json:"email,omitempty"`
+	Credentials []Credential        `json:"credentials,omitempty"`
+	ClientRoles map[string][]string `json:"clientRoles,omitempty"`
+}
+
+// Credential is a credential of a user
+type Credential struct {
+	Type      string `json:"type"`
+	Value     string `json:"value"`
+	Temporary bool   `json:"temporary"`
+}
+
+// Policies of a partial import for the existing resources
+const (
+	ImportSkip      = "SKIP"
+	ImportOverwrite = "OVERWRITE"
+	ImportFail      = "FAIL"
+)
+
+// Actions reported by a partial import
+const (
+	ImportAdded       = "ADDED"
+	ImportSkipped     = "SKIPPED"
+	ImportOverwritten = "OVERWRITTEN"
+)
+
+// PartialImport is a partial import of a realm
+type PartialImport struct {
+	IfResourceExists string `json:"ifResourceExists"`
+	Users            []User `json:"users,omitempty"`
+}
+
+// PartialImportResult is the result of a partial import
+type PartialImportResult struct {
+	Added       int                           `json:"added"`
+	Skipped     int                           `json:"skipped"`
+	Overwritten int                           `json:"overwritten"`
+	Results     []PartialImportResourceResult `json:"results"`
+}
+
+// PartialImportResourceResult is the result of the import of a resource
+type PartialImportResourceResult struct {
+	Action       string `json:"action"`
+	ResourceType string `json:"resourceType"`
+	ResourceName string `json:"resourceName"`
+	ID           string `json:"id"`
+}
+
+// EnsureUsersResult is the result of EnsureUsers
+type EnsureUsersResult struct {
+	Added   int
+	Updated int
+	// IDs of the users by username
+	IDs map[string]string
+}
+
+// NewUser returns an enabled user with a password
+func NewUser(username string, email string, password string, clientRoles map[string][]string) User {
+	return User{
+		Username: username,
+		Enabled:  true,
+		Email:    email,
+		Credentials: []Credential{
+			{
+				Type:  "password",
+				Value: password,
+			},
+		},
+		ClientRoles: clientRoles,
+	}
+}
+
+// PartialImport imports resources into the realm
+func (c *Client) PartialImport(realm string, partialImport PartialImport) (*PartialImportResult, error) {
+	result := &PartialImportResult{}
+	err := c.do(http.MethodPost, "/realms/"+url.PathEscape(realm)+"/partialImport", partialImport, result)
+	return result, err
+}
+
+// ListUsers returns the users of the realm
+func (c *Client) ListUsers(realm string) ([]User, error) {
+	users := []User{}
+	for first := 0; ; first += 100 {
+		pageUsers := []User{}
+		if err := c.do(http.MethodGet, fmt.Sprintf("/realms/%s/users?first=%d&max=100", url.PathEscape(realm), first), nil, &pageUsers); err != nil {
+			return nil, err
+		}
+		users = append(users, pageUsers...)
+		if len(pageUsers) < 100 {
+			return users, nil
+		}
+	}
+}
+
+// GetUser returns the user of the realm with the username
+func (c *Client) GetUser(realm string, username string) (*User, error) {
+	users := []User{}
+	path := "/realms/" + url.PathEscape(realm) + "/users?exact=true&username=" + url.QueryEscape(username)
+	if err := c.do(http.MethodGet, path, nil, &users); err != nil {
+		return nil, err
+	}
+	for i := range users {
+		if users[i].Username == username {
+			return &users[i], nil
+		}
+	}
+	return nil, &APIError{Method: http.MethodGet, Path: path, StatusCode: http.StatusNotFound, Message: "user not found"}
+}
+
+// UpdateUser updates the user of the realm with the fields set
+func (c *Client) UpdateUser(realm string, user User) error {
+	return c.do(http.MethodPut, "/realms/"+url.PathEscape(realm)+"/users/"+url.PathEscape(user.ID), user, nil)
+}
+
+// ResetPassword sets the password of the user of the realm
+func (c *Client) ResetPassword(realm string, userID string, password string) error {
+	credential := Credential{Type: "password", Value: password}
+	return c.do(http.MethodPut, "/realms/"+url.PathEscape(realm)+"/users/"+url.PathEscape(userID)+"/reset-password", credential, nil)
+}
+
+// EnsureUsers creates the missing users through a partial import of the realm and updates the email and the
+// enabled flag of the existing ones. The existing users are skipped by the import rather than overwritten, which
+// would recreate them with new ids, and their passwords are left unchanged.
+func (c *Client) EnsureUsers(realm string, users []User) (*EnsureUsersResult, error) {
+	existingUsers, err := c.ListUsers(realm)
+	if err != nil {
+		return nil, err
+	}
+	found := map[string]User{}
+	for _, user := range existingUsers {
+		found[user.Username] = user
+	}
+
+	result := &EnsureUsersResult{IDs: map[string]string{}}
+	missingUsers := []User{}
+	for _, user := range users {
+		existingUser, ok := found[user.Username]
+		if !ok {
+			missingUsers = append(missingUsers, user)
+			continue
+		}
+		result.IDs[user.Username] = existingUser.ID
+		if existingUser.Email != user.Email || existingUser.Enabled != user.Enabled {
+			existingUser.Email = user.Email
+			existingUser.Enabled = user.Enabled
+			if err := c.UpdateUser(realm, existingUser); err != nil {
+				return nil, err
+			}
+			result.Updated++
+		}
+	}
+
+	if len(missingUsers) == 0 {
+		return result, nil
+	}
+	importResult, err := c.PartialImport(realm, PartialImport{IfResourceExists: ImportSkip, Users: missingUsers})
+	if err != nil {
+		return nil, err
+	}
+	for _, resource := range importResult.Results {
+		result.IDs[resource.ResourceName] = resource.ID
+	}
+	result.Added = importResult.Added
+	return result, nil
+}
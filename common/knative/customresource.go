@@ -0,0 +1,94 @@
+package knative
+
+import (
+	"strconv"
+
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/kubernetes"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Defaults and annotations of the Knative Custom Resources
+const (
+	DefaultBrokerClass    = "MTChannelBasedBroker"
+	BrokerClassAnnotation = "eventing.knative.dev/broker.class"
+)
+
+var (
+	// KnativeServingGroupVersionKind of the custom resource installing Knative Serving
+	KnativeServingGroupVersionKind = schema.GroupVersionKind{Group: "operator.knative.dev", Version: "v1alpha1", Kind: "KnativeServing"}
+	// KnativeEventingGroupVersionKind of the custom resource installing Knative Eventing
+	KnativeEventingGroupVersionKind = schema.GroupVersionKind{Group: "operator.knative.dev", Version: "v1alpha1", Kind: "KnativeEventing"}
+	// BrokerGroupVersionKind of the Brokers of the users
+	BrokerGroupVersionKind = schema.GroupVersionKind{Group: "eventing.knative.dev", Version: "v1", Kind: "Broker"}
+)
+
+// BrokerClass returns the class of the Brokers
+func BrokerClass(workshop *workshopv1.Workshop) string {
+	if brokerClass := workshop.Spec.Infrastructure.Serverless.Eventing.BrokerClass; brokerClass != "" {
+		return brokerClass
+	}
+	return DefaultBrokerClass
+}
+
+// NewKnativeServingCR create a KnativeServing Custom Resource with the domain and the autoscaling defaults
+func NewKnativeServingCR(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, namespace string, labels map[string]string) (*unstructured.Unstructured, error) {
+
+	serving := workshop.Spec.Infrastructure.Serverless.Serving
+
+	config := map[string]interface{}{}
+	if serving.Domain != "" {
+		config["domain"] = map[string]interface{}{
+			serving.Domain: "",
+		}
+	}
+
+	autoscaler := map[string]interface{}{}
+	if serving.Autoscaling.ContainerConcurrencyTarget != nil {
+		autoscaler["container-concurrency-target-default"] = strconv.Itoa(int(*serving.Autoscaling.ContainerConcurrencyTarget))
+	}
+	if serving.Autoscaling.ScaleToZero != nil {
+		autoscaler["enable-scale-to-zero"] = strconv.FormatBool(*serving.Autoscaling.ScaleToZero)
+	}
+	if serving.Autoscaling.ScaleToZeroGracePeriod != "" {
+		autoscaler["scale-to-zero-grace-period"] = serving.Autoscaling.ScaleToZeroGracePeriod
+	}
+	if serving.Autoscaling.MaxScale != nil {
+		autoscaler["max-scale"] = strconv.Itoa(int(*serving.Autoscaling.MaxScale))
+	}
+	if len(autoscaler) > 0 {
+		config["autoscaler"] = autoscaler
+	}
+
+	return kubernetes.NewChecksumCustomResource(KnativeServingGroupVersionKind, name, namespace, labels, map[string]interface{}{
+		"config": config,
+	})
+}
+
+// NewKnativeEventingCR create a KnativeEventing Custom Resource with the class of the Brokers
+func NewKnativeEventingCR(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, namespace string, labels map[string]string) (*unstructured.Unstructured, error) {
+
+	return kubernetes.NewChecksumCustomResource(KnativeEventingGroupVersionKind, name, namespace, labels, map[string]interface{}{
+		"defaultBrokerClass": BrokerClass(workshop),
+	})
+}
+
+// NewBrokerCR create a Broker Custom Resource of the class of the workshop
+func NewBrokerCR(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, namespace string, labels map[string]string) *unstructured.Unstructured {
+
+	cr := &unstructured.Unstructured{}
+	cr.SetGroupVersionKind(BrokerGroupVersionKind)
+	cr.SetName(name)
+	cr.SetNamespace(namespace)
+	cr.SetLabels(labels)
+	cr.SetAnnotations(map[string]string{
+		BrokerClassAnnotation: BrokerClass(workshop),
+	})
+	cr.Object["spec"] = map[string]interface{}{}
+	return cr
+}
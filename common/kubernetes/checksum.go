@@ -0,0 +1,44 @@
+package kubernetes
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ChecksumAnnotation holds the checksum of the configuration a resource was created or last updated with
+const ChecksumAnnotation = "workshop.stakater.com/config-checksum"
+
+// Checksum returns the sha256 checksum of the JSON encoding of a configuration
+func Checksum(config interface{}) (string, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
+// NewChecksumCustomResource creates an unstructured Custom Resource annotated with the checksum of its spec, for
+// the operators whose types are not vendored
+func NewChecksumCustomResource(gvk schema.GroupVersionKind, name string, namespace string, labels map[string]string,
+	spec map[string]interface{}) (*unstructured.Unstructured, error) {
+
+	checksum, err := Checksum(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	cr := &unstructured.Unstructured{}
+	cr.SetGroupVersionKind(gvk)
+	cr.SetName(name)
+	cr.SetNamespace(namespace)
+	cr.SetLabels(labels)
+	cr.SetAnnotations(map[string]string{
+		ChecksumAnnotation: checksum,
+	})
+	cr.Object["spec"] = spec
+	return cr, nil
+}
@@ -0,0 +1,20 @@
+package kubernetes
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ReadyCondition returns the status and the message of the Ready condition of a Custom Resource, such as the
+// ones of Knative and Strimzi
+func ReadyCondition(cr *unstructured.Unstructured) (bool, string) {
+	conditions, _, _ := unstructured.NestedSlice(cr.Object, "status", "conditions")
+	for _, condition := range conditions {
+		condition, ok := condition.(map[string]interface{})
+		if !ok || condition["type"] != "Ready" {
+			continue
+		}
+		message, _ := condition["message"].(string)
+		return condition["status"] == "True", message
+	}
+	return false, "Waiting for the Ready condition"
+}
@@ -15,12 +15,16 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 )
 
 type k8s struct {
 	clientset kubernetes.Interface
+	config    *rest.Config
 }
 
 // GetK8Client returns k8s client
@@ -29,7 +33,7 @@ func GetK8Client() *k8s {
 	if err != nil {
 		log.Errorf(err.Error())
 	}
-	client := k8s{}
+	client := k8s{config: cfg}
 	client.clientset, err = kubernetes.NewForConfig(cfg)
 
 	if err != nil {
@@ -157,3 +161,29 @@ func (cl *k8s) GetDeploymentPod(name string, namespace string, label string) (po
 	podName = podListItems[0].Name
 	return podName, nil
 }
+
+// ExecInPod runs a command in the first container of a pod and returns its stdout and stderr
+func (cl *k8s) ExecInPod(podName string, namespace string, command []string) (string, string, error) {
+	req := cl.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(podName).
+		Namespace(namespace).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Command: command,
+			Stdout:  true,
+			Stderr:  true,
+		}, scheme.ParameterCodec)
+
+	exec, err := remotecommand.NewSPDYExecutor(cl.config, "POST", req.URL())
+	if err != nil {
+		return "", "", err
+	}
+
+	var stdout, stderr bytes.Buffer
+	err = exec.Stream(remotecommand.StreamOptions{
+		Stdout: &stdout,
+		Stderr: &stderr,
+	})
+	return stdout.String(), stderr.String(), err
+}
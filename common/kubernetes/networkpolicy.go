@@ -0,0 +1,72 @@
+package kubernetes
+
+import (
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// NewNetworkPolicy creates a NetworkPolicy only letting the pods of the namespace matching one of the from labels,
+// and the pods matching fromNamespacesPods in the namespaces matching one of the fromNamespaces labels, reach the
+// ports of the pods matching podLabels, their openPorts staying reachable from everywhere
+func NewNetworkPolicy(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, namespace string, labels map[string]string, podLabels map[string]string, ports []int32,
+	from []map[string]string, fromNamespaces []map[string]string, fromNamespacesPods map[string]string,
+	openPorts []int32) *networkingv1.NetworkPolicy {
+
+	policyPort := func(port int32) networkingv1.NetworkPolicyPort {
+		protocol := corev1.ProtocolTCP
+		portNumber := intstr.FromInt(int(port))
+		return networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &portNumber}
+	}
+
+	peers := []networkingv1.NetworkPolicyPeer{}
+	for _, fromLabels := range from {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			PodSelector: &metav1.LabelSelector{MatchLabels: fromLabels},
+		})
+	}
+	for _, namespaceLabels := range fromNamespaces {
+		peer := networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: namespaceLabels},
+		}
+		if len(fromNamespacesPods) > 0 {
+			peer.PodSelector = &metav1.LabelSelector{MatchLabels: fromNamespacesPods}
+		}
+		peers = append(peers, peer)
+	}
+	policyPorts := []networkingv1.NetworkPolicyPort{}
+	for _, port := range ports {
+		policyPorts = append(policyPorts, policyPort(port))
+	}
+	rules := []networkingv1.NetworkPolicyIngressRule{
+		{
+			From:  peers,
+			Ports: policyPorts,
+		},
+	}
+	if len(openPorts) > 0 {
+		ports := []networkingv1.NetworkPolicyPort{}
+		for _, openPort := range openPorts {
+			ports = append(ports, policyPort(openPort))
+		}
+		rules = append(rules, networkingv1.NetworkPolicyIngressRule{Ports: ports})
+	}
+
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: podLabels},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress:     rules,
+		},
+	}
+	return policy
+}
@@ -0,0 +1,24 @@
+package kubernetes
+
+import (
+	oauthv1 "github.com/openshift/api/oauth/v1"
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// NewOAuthClient returns an OpenShift OAuthClient, granted automatically to the users
+func NewOAuthClient(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, labels map[string]string, secret string, redirectURIs []string) *oauthv1.OAuthClient {
+
+	oauthClient := &oauthv1.OAuthClient{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Secret:       secret,
+		RedirectURIs: redirectURIs,
+		GrantMethod:  oauthv1.GrantHandlerAuto,
+	}
+	return oauthClient
+}
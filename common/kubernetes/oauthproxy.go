@@ -0,0 +1,131 @@
+package kubernetes
+
+import (
+	"fmt"
+
+	"github.com/prometheus/common/log"
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// OAuthProxyPort is the HTTP port of the OAuth proxy, served by its header filter
+	OAuthProxyPort = 8080
+	// OAuthProxyUserHeader is the header holding the name of the user authenticated by the OAuth proxy
+	OAuthProxyUserHeader = "X-Forwarded-User"
+	// OAuthProxyEmailHeader is the header holding the email of the user authenticated by the OAuth proxy
+	OAuthProxyEmailHeader = "X-Forwarded-Email"
+	// HeaderFilterCommand is the binary of the header filter in the operator image
+	HeaderFilterCommand = "/header-filter"
+	// oauthProxyLocalPort is the port of the OAuth proxy, only reachable by its header filter
+	oauthProxyLocalPort = 8081
+)
+
+// NewOAuthProxyDeployment creates an OpenShift OAuth proxy Deployment, authenticating the users in front of upstream.
+// The secret holds the client-secret of the OAuthClient clientID and the cookie-secret of the proxy, and extraArgs
+// are appended to the arguments of the proxy. The proxy is only reached through a header filter run from filterImage,
+// removing the user headers sent by the clients, as the requests matching skipAuthRegex are passed through as is.
+func NewOAuthProxyDeployment(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, namespace string, labels map[string]string, image string, filterImage string,
+	clientID string, secretName string, upstream string, skipAuthRegex []string, extraArgs []string) *appsv1.Deployment {
+
+	args := []string{
+		"--provider=openshift",
+		fmt.Sprintf("--http-address=127.0.0.1:%d", oauthProxyLocalPort),
+		"--https-address=",
+		"--upstream=" + upstream,
+		"--client-id=" + clientID,
+		"--client-secret-file=/etc/proxy/secrets/client-secret",
+		"--cookie-secret-file=/etc/proxy/secrets/cookie-secret",
+		"--pass-user-headers=true",
+		"--email-domain=*",
+	}
+	for _, regex := range skipAuthRegex {
+		args = append(args, "--skip-auth-regex="+regex)
+	}
+	args = append(args, extraArgs...)
+
+	proxy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{
+						{
+							Name: "secrets",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									SecretName: secretName,
+								},
+							},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:            "oauth-proxy",
+							Image:           image,
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							Args:            args,
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "secrets",
+									MountPath: "/etc/proxy/secrets",
+									ReadOnly:  true,
+								},
+							},
+						},
+						{
+							Name:            "header-filter",
+							Image:           filterImage,
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							Command:         []string{HeaderFilterCommand},
+							Args: []string{
+								fmt.Sprintf("--addr=:%d", OAuthProxyPort),
+								fmt.Sprintf("--upstream=http://127.0.0.1:%d", oauthProxyLocalPort),
+								"--strip-headers=" + OAuthProxyUserHeader + "," + OAuthProxyEmailHeader,
+							},
+							Ports: []corev1.ContainerPort{
+								{
+									Name:          "http",
+									ContainerPort: OAuthProxyPort,
+									Protocol:      "TCP",
+								},
+							},
+							// Ready once the proxy answers through the filter
+							ReadinessProbe: &corev1.Probe{
+								Handler: corev1.Handler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Path: "/oauth/healthz",
+										Port: intstr.FromInt(OAuthProxyPort),
+									},
+								},
+								InitialDelaySeconds: 5,
+								TimeoutSeconds:      1,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// Set Workshop instance as the owner and controller
+	err := ctrl.SetControllerReference(workshop, proxy, scheme)
+	if err != nil {
+		log.Error(err, "Failed to set SetControllerReference")
+	}
+	return proxy
+}
@@ -318,3 +318,25 @@ func ArgoCDRules() []rbac.PolicyRule {
 		},
 	}
 }
+
+//PortalRules gets Rules
+func PortalRules(claimsConfigMapName string) []rbac.PolicyRule {
+	return []rbac.PolicyRule{
+		{
+			APIGroups: []string{
+				"",
+			},
+			Resources: []string{
+				"configmaps",
+			},
+			ResourceNames: []string{
+				claimsConfigMapName,
+			},
+			Verbs: []string{
+				"get",
+				"update",
+				"patch",
+			},
+		},
+	}
+}
@@ -45,3 +45,21 @@ func NewCrtSecret(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
 	}
 	return secret
 }
+
+// NewDockerConfigSecret create a Docker Config Secret
+func NewDockerConfigSecret(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, namespace string, labels map[string]string, dockerConfigJSON []byte) *corev1.Secret {
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: dockerConfigJSON,
+		},
+	}
+	return secret
+}
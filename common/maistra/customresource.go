@@ -4,15 +4,52 @@ import (
 	maistrav1 "github.com/maistra/istio-operator/pkg/apis/maistra/v1"
 	maistrav2 "github.com/maistra/istio-operator/pkg/apis/maistra/v2"
 	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/kubernetes"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
-// NewServiceMeshControlPlaneCR create a SMCP Custom Resource
+// Defaults of the control plane
+const (
+	DefaultVersion         = "v2.0"
+	DefaultTracingSampling = int32(10000)
+	MTLSModeStrict         = "strict"
+)
+
+// addonEnabled returns the enablement of an addon, enabled by default
+func addonEnabled(enabled *bool) bool {
+	return enabled == nil || *enabled
+}
+
+// KialiEnabled returns true if the control plane installs Kiali
+func KialiEnabled(workshop *workshopv1.Workshop) bool {
+	return addonEnabled(workshop.Spec.Infrastructure.ServiceMesh.ControlPlane.Addons.Kiali)
+}
+
+// JaegerEnabled returns true if the control plane installs Jaeger
+func JaegerEnabled(workshop *workshopv1.Workshop) bool {
+	return addonEnabled(workshop.Spec.Infrastructure.ServiceMesh.ControlPlane.Addons.Jaeger)
+}
+
+// NewServiceMeshControlPlaneCR create a SMCP Custom Resource, annotated with the checksum of its spec
 func NewServiceMeshControlPlaneCR(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
-	name string, namespace string) *maistrav2.ServiceMeshControlPlane {
+	name string, namespace string) (*maistrav2.ServiceMeshControlPlane, error) {
 
-	var sampling int32 = 10000
+	controlPlane := workshop.Spec.Infrastructure.ServiceMesh.ControlPlane
+
+	version := controlPlane.Version
+	if version == "" {
+		version = DefaultVersion
+	}
+
+	sampling := DefaultTracingSampling
+	if controlPlane.TracingSampling != nil {
+		sampling = *controlPlane.TracingSampling
+	}
+
+	kialiEnabled := KialiEnabled(workshop)
+	grafanaEnabled := addonEnabled(controlPlane.Addons.Grafana)
+	mtls := controlPlane.MTLSMode == MTLSModeStrict
 
 	smcp := &maistrav2.ServiceMeshControlPlane{
 		ObjectMeta: metav1.ObjectMeta{
@@ -20,9 +57,9 @@ func NewServiceMeshControlPlaneCR(workshop *workshopv1.Workshop, scheme *runtime
 			Namespace: namespace,
 		},
 		Spec: maistrav2.ControlPlaneSpec{
-			Version: "v2.0",
+			Version: version,
 			Tracing: &maistrav2.TracingConfig{
-				Type:     maistrav2.TracerTypeJaeger,
+				Type:     maistrav2.TracerTypeNone,
 				Sampling: &sampling,
 			},
 			Policy: &maistrav2.PolicyConfig{
@@ -31,20 +68,62 @@ func NewServiceMeshControlPlaneCR(workshop *workshopv1.Workshop, scheme *runtime
 			Telemetry: &maistrav2.TelemetryConfig{
 				Type: maistrav2.TelemetryTypeIstiod,
 			},
+			Security: &maistrav2.SecurityConfig{
+				ControlPlane: &maistrav2.ControlPlaneSecurityConfig{
+					MTLS: &mtls,
+				},
+				DataPlane: &maistrav2.DataPlaneSecurityConfig{
+					MTLS: &mtls,
+				},
+			},
 			Addons: &maistrav2.AddonsConfig{
-				Jaeger: &maistrav2.JaegerAddonConfig{
-					Install: &maistrav2.JaegerInstallConfig{
-						Storage: &maistrav2.JaegerStorageConfig{
-							Type: maistrav2.JaegerStorageTypeMemory,
+				Prometheus: &maistrav2.PrometheusAddonConfig{},
+				Kiali: &maistrav2.KialiAddonConfig{
+					Enablement: maistrav2.Enablement{Enabled: &kialiEnabled},
+				},
+				Grafana: &maistrav2.GrafanaAddonConfig{
+					Enablement: maistrav2.Enablement{Enabled: &grafanaEnabled},
+				},
+			},
+		},
+	}
+
+	if JaegerEnabled(workshop) {
+		smcp.Spec.Tracing.Type = maistrav2.TracerTypeJaeger
+		smcp.Spec.Addons.Jaeger = &maistrav2.JaegerAddonConfig{
+			Install: &maistrav2.JaegerInstallConfig{
+				Storage: &maistrav2.JaegerStorageConfig{
+					Type: maistrav2.JaegerStorageTypeMemory,
+				},
+			},
+		}
+	}
+
+	if controlPlane.IngressGatewayReplicas != nil {
+		replicas := *controlPlane.IngressGatewayReplicas
+		smcp.Spec.Gateways = &maistrav2.GatewaysConfig{
+			ClusterIngress: &maistrav2.ClusterIngressGatewayConfig{
+				IngressGatewayConfig: maistrav2.IngressGatewayConfig{
+					GatewayConfig: maistrav2.GatewayConfig{
+						Runtime: &maistrav2.ComponentRuntimeConfig{
+							Deployment: &maistrav2.DeploymentRuntimeConfig{
+								Replicas: &replicas,
+							},
 						},
 					},
 				},
-				Prometheus: &maistrav2.PrometheusAddonConfig{},
-				Kiali:      &maistrav2.KialiAddonConfig{},
 			},
-		},
+		}
+	}
+
+	checksum, err := kubernetes.Checksum(smcp.Spec)
+	if err != nil {
+		return nil, err
+	}
+	smcp.Annotations = map[string]string{
+		kubernetes.ChecksumAnnotation: checksum,
 	}
-	return smcp
+	return smcp, nil
 }
 
 // NewServiceMeshMemberRollCR create a SMMR Custom Resource
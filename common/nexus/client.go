@@ -0,0 +1,103 @@
+package nexus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client is a client of the Nexus REST API, authenticated as an administrator
+type Client struct {
+	baseURL    string
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+// APIError is returned when the Nexus API answers with an unexpected status code
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("nexus: %s %s returned %d: %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// IsNotFound returns true if the error is a Nexus API 404
+func IsNotFound(err error) bool {
+	apiErr, ok := err.(*APIError)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsUnauthorized returns true if the error is a Nexus API 401, the credentials being wrong
+func IsUnauthorized(err error) bool {
+	apiErr, ok := err.(*APIError)
+	return ok && apiErr.StatusCode == http.StatusUnauthorized
+}
+
+// NewClient returns a Client authenticated with a username and a password
+func NewClient(baseURL string, username string, password string) *Client {
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		username:   username,
+		password:   password,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+func (c *Client) do(method string, path string, contentType string, in []byte, out interface{}) error {
+	req, err := http.NewRequest(method, c.baseURL+"/service/rest"+path, bytes.NewReader(in))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.SetBasicAuth(c.username, c.password)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := ioutil.ReadAll(resp.Body)
+		return &APIError{Method: method, Path: path, StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(message))}
+	}
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+func (c *Client) doJSON(method string, path string, in interface{}, out interface{}) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+	return c.do(method, path, "application/json", body, out)
+}
+
+// CheckCredentials returns an error if the credentials of the client are not the ones of an administrator
+func (c *Client) CheckCredentials() error {
+	return c.doJSON(http.MethodGet, "/v1/security/users?userId="+url.QueryEscape(c.username), nil, nil)
+}
+
+// ChangePassword changes the password of a user
+func (c *Client) ChangePassword(username string, password string) error {
+	return c.do(http.MethodPut, "/v1/security/users/"+url.PathEscape(username)+"/change-password", "text/plain",
+		[]byte(password), nil)
+}
@@ -6,75 +6,139 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// Defaults of the server
+const (
+	DefaultImageName     = "docker.io/sonatype/nexus3"
+	DefaultImageTag      = "3.68.1"
+	DefaultVolumeSize    = "5Gi"
+	DefaultCPURequest    = 1
+	DefaultCPULimit      = 2
+	DefaultMemoryRequest = "2Gi"
+	DefaultMemoryLimit   = "2Gi"
+)
+
+// Annotations of the Nexus Custom Resource recording what was last applied to Nexus: the repositories and the
+// users of the Workshop, the ones removed from the Workshop being deleted, and the checksums of the passwords of the
+// users and of the warm-up
+const (
+	RepositoriesAnnotation   = "workshop.stakater.com/nexus-repositories"
+	UsersAnnotation          = "workshop.stakater.com/nexus-users"
+	UsersPasswordAnnotation  = "workshop.stakater.com/nexus-users-password"
+	WarmUpPasswordAnnotation = "workshop.stakater.com/nexus-warmup-password"
+)
+
+// DefaultRepositories are the repositories of the server unless set in the Workshop
+var DefaultRepositories = []workshopv1.NexusRepositorySpec{
+	{Name: "maven-central", Format: "maven2", Type: "proxy", RemoteURL: "https://repo1.maven.org/maven2/", LayoutPolicy: "permissive"},
+	{Name: "redhat-ga", Format: "maven2", Type: "proxy", RemoteURL: "https://maven.repository.redhat.com/ga/", LayoutPolicy: "permissive"},
+	{Name: "jboss", Format: "maven2", Type: "proxy", RemoteURL: "https://repository.jboss.org/nexus/content/groups/public", LayoutPolicy: "permissive"},
+	{Name: "releases", Format: "maven2", Type: "hosted", VersionPolicy: "release", WritePolicy: "allow_once"},
+	{Name: "maven-all-public", Format: "maven2", Type: "group", MemberRepos: []string{"maven-central", "redhat-ga", "jboss"}},
+	{Name: "docker", Format: "docker", Type: "hosted", HTTPPort: 5000, V1Enabled: true},
+	{Name: "npm", Format: "npm", Type: "proxy", RemoteURL: "https://registry.npmjs.org"},
+	{Name: "npm-all", Format: "npm", Type: "group", MemberRepos: []string{"npm"}},
+}
+
+// Repositories returns the repositories of the workshop, or the default ones
+func Repositories(workshop *workshopv1.Workshop) []workshopv1.NexusRepositorySpec {
+	if len(workshop.Spec.Infrastructure.Nexus.Repositories) > 0 {
+		return workshop.Spec.Infrastructure.Nexus.Repositories
+	}
+	return DefaultRepositories
+}
+
+// IsCustomResourceRepository returns true if the Ansible operator manages the repository, the other ones
+// being managed through the REST API
+func IsCustomResourceRepository(repository workshopv1.NexusRepositorySpec) bool {
+	switch repository.Format + "-" + repository.Type {
+	case "maven2-proxy", "maven2-hosted", "maven2-group", "docker-hosted", "npm-proxy", "npm-group":
+		return true
+	}
+	return false
+}
+
 // NewCustomResource create a Custom Resource
 func NewCustomResource(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
 	name string, namespace string, labels map[string]string) *Nexus {
 
+	server := workshop.Spec.Infrastructure.Nexus.Server
+
+	spec := NexusSpec{
+		NexusVolumeSize:        stringOrDefault(server.VolumeSize, DefaultVolumeSize),
+		NexusSSL:               true,
+		NexusImage:             stringOrDefault(server.Image.Name, DefaultImageName),
+		NexusImageTag:          stringOrDefault(server.Image.Tag, DefaultImageTag),
+		NexusCPURequest:        intOrDefault(server.CPURequest, DefaultCPURequest),
+		NexusCPULimit:          intOrDefault(server.CPULimit, DefaultCPULimit),
+		NexusMemoryRequest:     stringOrDefault(server.MemoryRequest, DefaultMemoryRequest),
+		NexusMemoryLimit:       stringOrDefault(server.MemoryLimit, DefaultMemoryLimit),
+		NexusReposMavenProxy:   []NexusReposMavenProxySpec{},
+		NexusReposMavenHosted:  []NexusReposMavenHostedSpec{},
+		NexusReposMavenGroup:   []NexusReposMavenGroupSpec{},
+		NexusReposDockerHosted: []NexusReposDockerHostedSpec{},
+		NexusReposNpmProxy:     []NexusReposNpmProxySpec{},
+		NexusReposNpmGroup:     []NexusReposNpmGroupSpec{},
+	}
+
+	for _, repository := range Repositories(workshop) {
+		switch repository.Format + "-" + repository.Type {
+		case "maven2-proxy":
+			spec.NexusReposMavenProxy = append(spec.NexusReposMavenProxy, NexusReposMavenProxySpec{
+				Name:         repository.Name,
+				RemoteURL:    repository.RemoteURL,
+				LayoutPolicy: stringOrDefault(repository.LayoutPolicy, "permissive"),
+			})
+		case "maven2-hosted":
+			spec.NexusReposMavenHosted = append(spec.NexusReposMavenHosted, NexusReposMavenHostedSpec{
+				Name:          repository.Name,
+				VersionPolicy: stringOrDefault(repository.VersionPolicy, "release"),
+				WritePolicy:   stringOrDefault(repository.WritePolicy, "allow_once"),
+			})
+		case "maven2-group":
+			spec.NexusReposMavenGroup = append(spec.NexusReposMavenGroup, NexusReposMavenGroupSpec{
+				Name:        repository.Name,
+				MemberRepos: repository.MemberRepos,
+			})
+		case "docker-hosted":
+			spec.NexusReposDockerHosted = append(spec.NexusReposDockerHosted, NexusReposDockerHostedSpec{
+				Name:      repository.Name,
+				HttpPort:  repository.HTTPPort,
+				V1Enabled: repository.V1Enabled,
+			})
+		case "npm-proxy":
+			spec.NexusReposNpmProxy = append(spec.NexusReposNpmProxy, NexusReposNpmProxySpec{
+				Name:      repository.Name,
+				RemoteURL: repository.RemoteURL,
+			})
+		case "npm-group":
+			spec.NexusReposNpmGroup = append(spec.NexusReposNpmGroup, NexusReposNpmGroupSpec{
+				Name:        repository.Name,
+				MemberRepos: repository.MemberRepos,
+			})
+		}
+	}
+
 	cr := &Nexus{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
 			Namespace: namespace,
 			Labels:    labels,
 		},
-		Spec: NexusSpec{
-			NexusVolumeSize: "5Gi",
-			NexusSSL:        true,
-			// TODO: it's pretty old image; should replace with latest version
-			NexusImageTag:      "3.18.1-01-ubi-3",
-			NexusCPURequest:    1,
-			NexusCPULimit:      2,
-			NexusMemoryRequest: "2Gi",
-			NexusMemoryLimit:   "2Gi",
-			NexusReposMavenProxy: []NexusReposMavenProxySpec{
-				{
-					Name:         "maven-central",
-					RemoteURL:    "https://repo1.maven.org/maven2/",
-					LayoutPolicy: "permissive",
-				},
-				{
-					Name:         "redhat-ga",
-					RemoteURL:    "https://maven.repository.redhat.com/ga/",
-					LayoutPolicy: "permissive",
-				},
-				{
-					Name:         "jboss",
-					RemoteURL:    "https://repository.jboss.org/nexus/content/groups/public",
-					LayoutPolicy: "permissive",
-				},
-			},
-			NexusReposMavenHosted: []NexusReposMavenHostedSpec{
-				{
-					Name:          "releases",
-					VersionPolicy: "release",
-					WritePolicy:   "allow_once",
-				},
-			},
-			NexusReposMavenGroup: []NexusReposMavenGroupSpec{
-				{
-					Name:        "maven-all-public",
-					MemberRepos: []string{"maven-central", "redhat-ga", "jboss"},
-				},
-			},
-			NexusReposDockerHosted: []NexusReposDockerHostedSpec{
-				{
-					Name:      "docker",
-					HttpPort:  5000,
-					V1Enabled: true,
-				},
-			},
-			NexusReposNpmProxy: []NexusReposNpmProxySpec{
-				{
-					Name:      "npm",
-					RemoteURL: "https://registry.npmjs.org",
-				},
-			},
-			NexusReposNpmGroup: []NexusReposNpmGroupSpec{
-				{
-					Name:        "npm-all",
-					MemberRepos: []string{"npm"},
-				},
-			},
-		},
+		Spec: spec,
 	}
 	return cr
 }
+
+func stringOrDefault(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func intOrDefault(value int, defaultValue int) int {
+	if value == 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -7,8 +7,34 @@ import "k8s.io/apimachinery/pkg/runtime"
 func (in *Nexus) DeepCopyInto(out *Nexus) {
 	out.TypeMeta = in.TypeMeta
 	out.ObjectMeta = in.ObjectMeta
-	out.Spec = NexusSpec{
-		NexusVolumeSize: in.Spec.NexusVolumeSize,
+	out.Spec = in.Spec
+	if in.Spec.NexusReposMavenProxy != nil {
+		out.Spec.NexusReposMavenProxy = make([]NexusReposMavenProxySpec, len(in.Spec.NexusReposMavenProxy))
+		copy(out.Spec.NexusReposMavenProxy, in.Spec.NexusReposMavenProxy)
+	}
+	if in.Spec.NexusReposMavenHosted != nil {
+		out.Spec.NexusReposMavenHosted = make([]NexusReposMavenHostedSpec, len(in.Spec.NexusReposMavenHosted))
+		copy(out.Spec.NexusReposMavenHosted, in.Spec.NexusReposMavenHosted)
+	}
+	if in.Spec.NexusReposMavenGroup != nil {
+		out.Spec.NexusReposMavenGroup = make([]NexusReposMavenGroupSpec, len(in.Spec.NexusReposMavenGroup))
+		for i, group := range in.Spec.NexusReposMavenGroup {
+			out.Spec.NexusReposMavenGroup[i] = NexusReposMavenGroupSpec{Name: group.Name, MemberRepos: copyStrings(group.MemberRepos)}
+		}
+	}
+	if in.Spec.NexusReposDockerHosted != nil {
+		out.Spec.NexusReposDockerHosted = make([]NexusReposDockerHostedSpec, len(in.Spec.NexusReposDockerHosted))
+		copy(out.Spec.NexusReposDockerHosted, in.Spec.NexusReposDockerHosted)
+	}
+	if in.Spec.NexusReposNpmProxy != nil {
+		out.Spec.NexusReposNpmProxy = make([]NexusReposNpmProxySpec, len(in.Spec.NexusReposNpmProxy))
+		copy(out.Spec.NexusReposNpmProxy, in.Spec.NexusReposNpmProxy)
+	}
+	if in.Spec.NexusReposNpmGroup != nil {
+		out.Spec.NexusReposNpmGroup = make([]NexusReposNpmGroupSpec, len(in.Spec.NexusReposNpmGroup))
+		for i, group := range in.Spec.NexusReposNpmGroup {
+			out.Spec.NexusReposNpmGroup[i] = NexusReposNpmGroupSpec{Name: group.Name, MemberRepos: copyStrings(group.MemberRepos)}
+		}
 	}
 }
 
@@ -35,3 +61,12 @@ func (in *NexusList) DeepCopyObject() runtime.Object {
 
 	return &out
 }
+
+func copyStrings(in []string) []string {
+	if in == nil {
+		return nil
+	}
+	out := make([]string, len(in))
+	copy(out, in)
+	return out
+}
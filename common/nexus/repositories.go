@@ -0,0 +1,154 @@
+package nexus
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+)
+
+// Repository is the configuration of a repository in the REST API
+type Repository struct {
+	Name          string                   `json:"name"`
+	Online        bool                     `json:"online"`
+	Storage       RepositoryStorage        `json:"storage"`
+	Proxy         *RepositoryProxy         `json:"proxy,omitempty"`
+	NegativeCache *RepositoryNegativeCache `json:"negativeCache,omitempty"`
+	HTTPClient    *RepositoryHTTPClient    `json:"httpClient,omitempty"`
+	Group         *RepositoryGroup         `json:"group,omitempty"`
+	Docker        *RepositoryDocker        `json:"docker,omitempty"`
+	DockerProxy   *RepositoryDockerProxy   `json:"dockerProxy,omitempty"`
+}
+
+// RepositoryStorage is the blob store of a repository
+type RepositoryStorage struct {
+	BlobStoreName               string `json:"blobStoreName"`
+	StrictContentTypeValidation bool   `json:"strictContentTypeValidation"`
+	WritePolicy                 string `json:"writePolicy,omitempty"`
+}
+
+// RepositoryProxy is the remote of a proxy repository
+type RepositoryProxy struct {
+	RemoteURL      string `json:"remoteUrl"`
+	ContentMaxAge  int    `json:"contentMaxAge"`
+	MetadataMaxAge int    `json:"metadataMaxAge"`
+}
+
+// RepositoryNegativeCache caches the artifacts missing in the remote of a proxy repository
+type RepositoryNegativeCache struct {
+	Enabled    bool `json:"enabled"`
+	TimeToLive int  `json:"timeToLive"`
+}
+
+// RepositoryHTTPClient is the client of the remote of a proxy repository
+type RepositoryHTTPClient struct {
+	Blocked   bool `json:"blocked"`
+	AutoBlock bool `json:"autoBlock"`
+}
+
+// RepositoryGroup lists the members of a group repository
+type RepositoryGroup struct {
+	MemberNames []string `json:"memberNames"`
+}
+
+// RepositoryDocker is the connector of a docker repository
+type RepositoryDocker struct {
+	V1Enabled      bool `json:"v1Enabled"`
+	ForceBasicAuth bool `json:"forceBasicAuth"`
+	HTTPPort       *int `json:"httpPort,omitempty"`
+}
+
+// RepositoryDockerProxy is the index of a docker proxy repository
+type RepositoryDockerProxy struct {
+	IndexType string `json:"indexType"`
+}
+
+// repositoryFormat returns the format of the repository in the paths of the REST API
+func repositoryFormat(format string) string {
+	if format == "maven2" {
+		return "maven"
+	}
+	return format
+}
+
+// NewRepository returns the configuration of the repository of the Workshop
+func NewRepository(spec workshopv1.NexusRepositorySpec) *Repository {
+	repository := &Repository{
+		Name:   spec.Name,
+		Online: true,
+		Storage: RepositoryStorage{
+			BlobStoreName:               "default",
+			StrictContentTypeValidation: true,
+		},
+	}
+
+	switch spec.Type {
+	case "hosted":
+		repository.Storage.WritePolicy = strings.ToUpper(stringOrDefault(spec.WritePolicy, "allow"))
+	case "proxy":
+		repository.Proxy = &RepositoryProxy{RemoteURL: spec.RemoteURL, ContentMaxAge: 1440, MetadataMaxAge: 1440}
+		repository.NegativeCache = &RepositoryNegativeCache{Enabled: true, TimeToLive: 1440}
+		repository.HTTPClient = &RepositoryHTTPClient{AutoBlock: true}
+	case "group":
+		repository.Group = &RepositoryGroup{MemberNames: spec.MemberRepos}
+		if repository.Group.MemberNames == nil {
+			repository.Group.MemberNames = []string{}
+		}
+	}
+
+	if spec.Format == "docker" {
+		repository.Docker = &RepositoryDocker{V1Enabled: spec.V1Enabled, ForceBasicAuth: true}
+		if spec.HTTPPort > 0 {
+			httpPort := spec.HTTPPort
+			repository.Docker.HTTPPort = &httpPort
+		}
+		if spec.Type == "proxy" {
+			repository.DockerProxy = &RepositoryDockerProxy{IndexType: "HUB"}
+		}
+	}
+	return repository
+}
+
+// repositoryChanged returns true if the settings of the Workshop differ in the found repository
+func repositoryChanged(found *Repository, repository *Repository) bool {
+	switch {
+	case repository.Storage.WritePolicy != "" && found.Storage.WritePolicy != repository.Storage.WritePolicy:
+		return true
+	case repository.Proxy != nil && (found.Proxy == nil || found.Proxy.RemoteURL != repository.Proxy.RemoteURL):
+		return true
+	case repository.Group != nil && (found.Group == nil || !reflect.DeepEqual(found.Group.MemberNames, repository.Group.MemberNames)):
+		return true
+	case repository.Docker != nil && !reflect.DeepEqual(found.Docker, repository.Docker):
+		return true
+	}
+	return false
+}
+
+// EnsureRepository creates the repository, or updates it when its settings differ, and returns true if it changed
+func (c *Client) EnsureRepository(spec workshopv1.NexusRepositorySpec) (bool, error) {
+	repository := NewRepository(spec)
+	path := "/v1/repositories/" + repositoryFormat(spec.Format) + "/" + spec.Type
+
+	found := &Repository{}
+	err := c.doJSON(http.MethodGet, path+"/"+url.PathEscape(spec.Name), nil, found)
+	if IsNotFound(err) {
+		return true, c.doJSON(http.MethodPost, path, repository, nil)
+	} else if err != nil {
+		return false, err
+	}
+	if !repositoryChanged(found, repository) {
+		return false, nil
+	}
+	return true, c.doJSON(http.MethodPut, path+"/"+url.PathEscape(spec.Name), repository, nil)
+}
+
+// EnsureRepositoryDeleted deletes the repository and its content if it exists, and returns true if it was deleted
+func (c *Client) EnsureRepositoryDeleted(name string) (bool, error) {
+	err := c.doJSON(http.MethodDelete, "/v1/repositories/"+url.PathEscape(name), nil, nil)
+	if IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,256 @@
+package nexus
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ContentSelector is a content selector in the REST API, matching the paths of a user
+type ContentSelector struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Expression  string `json:"expression"`
+}
+
+// Privilege is a repository content selector privilege in the REST API
+type Privilege struct {
+	Name            string   `json:"name"`
+	Description     string   `json:"description"`
+	Actions         []string `json:"actions"`
+	Format          string   `json:"format"`
+	Repository      string   `json:"repository"`
+	ContentSelector string   `json:"contentSelector"`
+}
+
+// Role is a role in the REST API
+type Role struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Privileges  []string `json:"privileges"`
+	Roles       []string `json:"roles"`
+}
+
+// User is a user of the default realm in the REST API
+type User struct {
+	UserID       string   `json:"userId"`
+	FirstName    string   `json:"firstName"`
+	LastName     string   `json:"lastName"`
+	EmailAddress string   `json:"emailAddress"`
+	Password     string   `json:"password,omitempty"`
+	Source       string   `json:"source,omitempty"`
+	Status       string   `json:"status"`
+	ReadOnly     bool     `json:"readOnly"`
+	Roles        []string `json:"roles"`
+}
+
+// Privileges every user holds, browsing and reading all the repositories
+var readPrivileges = []string{"nx-repository-view-*-*-browse", "nx-repository-view-*-*-read"}
+
+// deployActions are the actions of the users in their paths of the hosted repositories
+var deployActions = []string{"BROWSE", "READ", "EDIT", "ADD", "DELETE"}
+
+// sameNames returns true if both lists hold the same names, in any order and case
+func sameNames(names []string, otherNames []string) bool {
+	normalize := func(values []string) []string {
+		normalized := []string{}
+		for _, value := range values {
+			normalized = append(normalized, strings.ToLower(value))
+		}
+		sort.Strings(normalized)
+		return normalized
+	}
+	return reflect.DeepEqual(normalize(names), normalize(otherNames))
+}
+
+// ignoreNotFound returns nil for a Nexus API 404, and whether something was deleted
+func ignoreNotFound(err error) (bool, error) {
+	if IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// EnsureContentSelector creates the content selector, or updates its expression, and returns true if it changed
+func (c *Client) EnsureContentSelector(selector ContentSelector) (bool, error) {
+	path := "/v1/security/content-selectors/" + url.PathEscape(selector.Name)
+
+	found := &ContentSelector{}
+	err := c.doJSON(http.MethodGet, path, nil, found)
+	if IsNotFound(err) {
+		return true, c.doJSON(http.MethodPost, "/v1/security/content-selectors", selector, nil)
+	} else if err != nil {
+		return false, err
+	}
+	if found.Expression == selector.Expression {
+		return false, nil
+	}
+	return true, c.doJSON(http.MethodPut, path, selector, nil)
+}
+
+// EnsurePrivilege creates the repository content selector privilege, or updates it, and returns true if it changed
+func (c *Client) EnsurePrivilege(privilege Privilege) (bool, error) {
+	path := "/v1/security/privileges/repository-content-selector"
+
+	found := &Privilege{}
+	err := c.doJSON(http.MethodGet, "/v1/security/privileges/"+url.PathEscape(privilege.Name), nil, found)
+	if IsNotFound(err) {
+		return true, c.doJSON(http.MethodPost, path, privilege, nil)
+	} else if err != nil {
+		return false, err
+	}
+	if found.Repository == privilege.Repository && found.ContentSelector == privilege.ContentSelector &&
+		found.Format == privilege.Format && sameNames(found.Actions, privilege.Actions) {
+		return false, nil
+	}
+	return true, c.doJSON(http.MethodPut, path+"/"+url.PathEscape(privilege.Name), privilege, nil)
+}
+
+// EnsureRole creates the role, or updates its privileges and roles, and returns true if it changed
+func (c *Client) EnsureRole(role Role) (bool, error) {
+	path := "/v1/security/roles/" + url.PathEscape(role.ID)
+
+	found := &Role{}
+	err := c.doJSON(http.MethodGet, path, nil, found)
+	if IsNotFound(err) {
+		return true, c.doJSON(http.MethodPost, "/v1/security/roles", role, nil)
+	} else if err != nil {
+		return false, err
+	}
+	if sameNames(found.Privileges, role.Privileges) && sameNames(found.Roles, role.Roles) {
+		return false, nil
+	}
+	return true, c.doJSON(http.MethodPut, path, role, nil)
+}
+
+// GetUser returns the user of the default realm, the search of the REST API matching the ids by prefix
+func (c *Client) GetUser(userID string) (*User, error) {
+	users := []User{}
+	if err := c.doJSON(http.MethodGet, "/v1/security/users?source=default&userId="+url.QueryEscape(userID), nil, &users); err != nil {
+		return nil, err
+	}
+	for i := range users {
+		if users[i].UserID == userID {
+			return &users[i], nil
+		}
+	}
+	return nil, &APIError{Method: http.MethodGet, Path: "/v1/security/users/" + userID, StatusCode: http.StatusNotFound}
+}
+
+// EnsureUser creates the user, or updates its roles and, with changePassword, its password, and returns true if
+// it changed
+func (c *Client) EnsureUser(user User, changePassword bool) (bool, error) {
+	found, err := c.GetUser(user.UserID)
+	if IsNotFound(err) {
+		return true, c.doJSON(http.MethodPost, "/v1/security/users", user, nil)
+	} else if err != nil {
+		return false, err
+	}
+
+	changed := false
+	if !sameNames(found.Roles, user.Roles) {
+		found.Roles = user.Roles
+		if err := c.doJSON(http.MethodPut, "/v1/security/users/"+url.PathEscape(user.UserID), found, nil); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+	if changePassword {
+		if err := c.ChangePassword(user.UserID, user.Password); err != nil {
+			return changed, err
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// EnsureWorkshopUser creates or updates the account of the user, with a role reading all the repositories and
+// deploying to the paths of the user in the hosted ones: the userN Maven group id, the @userN npm scope and the
+// userN/ Docker namespace. The password of an existing account is only changed with changePassword.
+func (c *Client) EnsureWorkshopUser(username string, password string, changePassword bool, hosted []string) (bool, error) {
+	name := "workshop-" + username
+
+	changed, err := c.EnsureContentSelector(ContentSelector{
+		Name:        name,
+		Description: "Paths of " + username,
+		Expression:  `path =^ "/` + username + `/" or path =^ "/@` + username + `/" or path =^ "/v2/` + username + `/"`,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	privileges := []string{}
+	for _, repository := range hosted {
+		privilege := Privilege{
+			Name:            name + "-" + repository,
+			Description:     "Deploy of " + username + " to " + repository,
+			Actions:         deployActions,
+			Format:          "*",
+			Repository:      repository,
+			ContentSelector: name,
+		}
+		if privilegeChanged, err := c.EnsurePrivilege(privilege); err != nil {
+			return false, err
+		} else if privilegeChanged {
+			changed = true
+		}
+		privileges = append(privileges, privilege.Name)
+	}
+
+	if roleChanged, err := c.EnsureRole(Role{
+		ID:          name,
+		Name:        name,
+		Description: "Role of " + username,
+		Privileges:  append(privileges, readPrivileges...),
+		Roles:       []string{},
+	}); err != nil {
+		return false, err
+	} else if roleChanged {
+		changed = true
+	}
+
+	userChanged, err := c.EnsureUser(User{
+		UserID:       username,
+		FirstName:    username,
+		LastName:     username,
+		EmailAddress: username + "@none.com",
+		Password:     password,
+		Status:       "active",
+		Roles:        []string{name},
+	}, changePassword)
+	return changed || userChanged, err
+}
+
+// EnsureWorkshopUserDeleted deletes the account of the user with its role, privileges and content selector, and
+// returns true if the account was deleted
+func (c *Client) EnsureWorkshopUserDeleted(username string) (bool, error) {
+	name := "workshop-" + username
+
+	deleted, err := ignoreNotFound(c.doJSON(http.MethodDelete, "/v1/security/users/"+url.PathEscape(username), nil, nil))
+	if err != nil {
+		return false, err
+	}
+
+	role := &Role{}
+	if err := c.doJSON(http.MethodGet, "/v1/security/roles/"+url.PathEscape(name), nil, role); err == nil {
+		if _, err := ignoreNotFound(c.doJSON(http.MethodDelete, "/v1/security/roles/"+url.PathEscape(name), nil, nil)); err != nil {
+			return deleted, err
+		}
+		for _, privilege := range role.Privileges {
+			if !strings.HasPrefix(privilege, name+"-") {
+				continue
+			}
+			if _, err := ignoreNotFound(c.doJSON(http.MethodDelete, "/v1/security/privileges/"+url.PathEscape(privilege), nil, nil)); err != nil {
+				return deleted, err
+			}
+		}
+	} else if !IsNotFound(err) {
+		return deleted, err
+	}
+
+	_, err = ignoreNotFound(c.doJSON(http.MethodDelete, "/v1/security/content-selectors/"+url.PathEscape(name), nil, nil))
+	return deleted, err
+}
@@ -0,0 +1,130 @@
+package nexus
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+)
+
+// Keys of the client configuration written in the namespaces of the users
+const (
+	MavenSettingsKey = "settings.xml"
+	NpmrcKey         = ".npmrc"
+	UsernameKey      = "username"
+	PasswordKey      = "password"
+)
+
+// MavenServerID is the id of the server holding the credentials in the Maven settings
+const MavenServerID = "nexus"
+
+// HostedRepositoryNames returns the names of the hosted repositories the users deploy to
+func HostedRepositoryNames(workshop *workshopv1.Workshop) []string {
+	names := []string{}
+	for _, repository := range Repositories(workshop) {
+		if repository.Type == "hosted" {
+			names = append(names, repository.Name)
+		}
+	}
+	return names
+}
+
+// RepositoryName returns the name of the first repository of the format with one of the types, in their order
+func RepositoryName(workshop *workshopv1.Workshop, format string, types ...string) string {
+	for _, repositoryType := range types {
+		for _, repository := range Repositories(workshop) {
+			if repository.Format == format && repository.Type == repositoryType {
+				return repository.Name
+			}
+		}
+	}
+	return ""
+}
+
+// DockerRepository returns the hosted Docker repository the users push to, if any
+func DockerRepository(workshop *workshopv1.Workshop) (workshopv1.NexusRepositorySpec, bool) {
+	for _, repository := range Repositories(workshop) {
+		if repository.Format == "docker" && repository.Type == "hosted" {
+			return repository, true
+		}
+	}
+	return workshopv1.NexusRepositorySpec{}, false
+}
+
+// NewMavenSettings returns Maven settings mirroring everything to the group or proxy repository and deploying
+// to the hosted one, with the credentials of the user
+func NewMavenSettings(workshop *workshopv1.Workshop, serverURL string, username string, password string) string {
+	var settings strings.Builder
+
+	settings.WriteString("<settings>\n")
+	settings.WriteString("  <servers>\n")
+	settings.WriteString("    <server>\n")
+	settings.WriteString(fmt.Sprintf("      <id>%s</id>\n", MavenServerID))
+	settings.WriteString(fmt.Sprintf("      <username>%s</username>\n", html.EscapeString(username)))
+	settings.WriteString(fmt.Sprintf("      <password>%s</password>\n", html.EscapeString(password)))
+	settings.WriteString("    </server>\n")
+	settings.WriteString("  </servers>\n")
+	if mirror := RepositoryName(workshop, "maven2", "group", "proxy"); mirror != "" {
+		settings.WriteString("  <mirrors>\n")
+		settings.WriteString("    <mirror>\n")
+		settings.WriteString(fmt.Sprintf("      <id>%s</id>\n", MavenServerID))
+		settings.WriteString("      <mirrorOf>*</mirrorOf>\n")
+		settings.WriteString(fmt.Sprintf("      <url>%s/repository/%s/</url>\n", serverURL, mirror))
+		settings.WriteString("    </mirror>\n")
+		settings.WriteString("  </mirrors>\n")
+	}
+	if hosted := RepositoryName(workshop, "maven2", "hosted"); hosted != "" {
+		settings.WriteString("  <profiles>\n")
+		settings.WriteString("    <profile>\n")
+		settings.WriteString(fmt.Sprintf("      <id>%s</id>\n", MavenServerID))
+		settings.WriteString("      <properties>\n")
+		settings.WriteString(fmt.Sprintf("        <altDeploymentRepository>%s::default::%s/repository/%s/</altDeploymentRepository>\n",
+			MavenServerID, serverURL, hosted))
+		settings.WriteString("      </properties>\n")
+		settings.WriteString("    </profile>\n")
+		settings.WriteString("  </profiles>\n")
+		settings.WriteString("  <activeProfiles>\n")
+		settings.WriteString(fmt.Sprintf("    <activeProfile>%s</activeProfile>\n", MavenServerID))
+		settings.WriteString("  </activeProfiles>\n")
+	}
+	settings.WriteString("</settings>\n")
+
+	return settings.String()
+}
+
+// NewNpmrc returns an npm configuration installing from the group or proxy repository, and publishing the
+// packages in the scope of the user to the hosted one
+func NewNpmrc(workshop *workshopv1.Workshop, serverURL string, username string, password string) string {
+	var npmrc strings.Builder
+
+	host := strings.TrimPrefix(strings.TrimPrefix(serverURL, "https:"), "http:")
+	auth := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	if registry := RepositoryName(workshop, "npm", "group", "proxy"); registry != "" {
+		npmrc.WriteString(fmt.Sprintf("registry=%s/repository/%s/\n", serverURL, registry))
+		npmrc.WriteString(fmt.Sprintf("%s/repository/%s/:_auth=%s\n", host, registry, auth))
+	}
+	if hosted := RepositoryName(workshop, "npm", "hosted"); hosted != "" {
+		npmrc.WriteString(fmt.Sprintf("@%s:registry=%s/repository/%s/\n", username, serverURL, hosted))
+		npmrc.WriteString(fmt.Sprintf("%s/repository/%s/:_auth=%s\n", host, hosted, auth))
+	}
+	npmrc.WriteString("always-auth=true\n")
+
+	return npmrc.String()
+}
+
+// NewDockerConfigJSON returns the content of a kubernetes.io/dockerconfigjson Secret for the registry
+func NewDockerConfigJSON(registry string, username string, password string) ([]byte, error) {
+	auth := map[string]string{
+		"username": username,
+		"password": password,
+		"auth":     base64.StdEncoding.EncodeToString([]byte(username + ":" + password)),
+	}
+	return json.Marshal(map[string]interface{}{
+		"auths": map[string]interface{}{
+			registry: auth,
+		},
+	})
+}
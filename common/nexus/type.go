@@ -5,11 +5,13 @@ import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 type NexusSpec struct {
 	NexusVolumeSize        string                       `json:"nexusVolumeSize"`
 	NexusSSL               bool                         `json:"nexusSsl"`
+	NexusImage             string                       `json:"nexusImage,omitempty"`
 	NexusImageTag          string                       `json:"nexusImageTag"`
 	NexusCPURequest        int                          `json:"nexusCpuRequest"`
 	NexusCPULimit          int                          `json:"nexusCpuLimit"`
 	NexusMemoryRequest     string                       `json:"nexusMemoryRequest"`
 	NexusMemoryLimit       string                       `json:"nexusMemoryLimit"`
+	NexusAdminPassword     string                       `json:"nexusAdminPassword,omitempty"`
 	NexusReposMavenProxy   []NexusReposMavenProxySpec   `json:"nexus_repos_maven_proxy"`
 	NexusReposMavenHosted  []NexusReposMavenHostedSpec  `json:"nexus_repos_maven_hosted"`
 	NexusReposMavenGroup   []NexusReposMavenGroupSpec   `json:"nexus_repos_maven_group"`
@@ -0,0 +1,223 @@
+package nexus
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/common/log"
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// Defaults of the warm-up
+const (
+	DefaultWarmUpImageName   = "quay.io/devfile/universal-developer-image"
+	DefaultWarmUpImageTag    = "ubi8-38da5c2"
+	DefaultCacheVolumeSize   = "5Gi"
+	WarmUpChecksumAnnotation = "workshop.stakater.com/warmup-checksum"
+	WarmUpUsername           = "workshop-warmup"
+	warmUpCacheMountPath     = "/cache"
+)
+
+// warmUpScript resolves the artifacts through the group repositories of Nexus, which caches them in its proxies.
+// The Maven local repository and the npm packages are written to the cache claim when exporting, and uploaded
+// from it to the hosted repositories when importing.
+const warmUpScript = `
+set -eu
+WORK=/tmp/warmup
+LOCAL=$WORK
+if [ "$CACHE_EXPORT" = "true" ]; then
+    LOCAL=$CACHE_DIR
+fi
+mkdir -p "$WORK/npm-install" "$LOCAL/maven" "$LOCAL/npm"
+
+NPM_HOST=$(echo "$NEXUS_URL" | sed 's|^https\?:||')
+NPM_AUTH=$(printf '%s:%s' "$NEXUS_USERNAME" "$NEXUS_PASSWORD" | base64 | tr -d '\n')
+export NPM_CONFIG_USERCONFIG=$WORK/.npmrc
+cat > "$WORK/.npmrc" <<EOF
+registry=$NEXUS_URL/repository/$NPM_REGISTRY/
+$NPM_HOST/repository/$NPM_REGISTRY/:_auth=$NPM_AUTH
+$NPM_HOST/repository/$NPM_HOSTED/:_auth=$NPM_AUTH
+cache=$WORK/npm-cache
+always-auth=true
+EOF
+cat > "$WORK/settings.xml" <<EOF
+<settings>
+  <localRepository>$LOCAL/maven</localRepository>
+  <mirrors>
+    <mirror>
+      <id>nexus</id>
+      <mirrorOf>*</mirrorOf>
+      <url>$NEXUS_URL/repository/$MAVEN_GROUP/</url>
+    </mirror>
+  </mirrors>
+</settings>
+EOF
+
+export_npm() {
+    if [ "$CACHE_EXPORT" = "true" ] && [ -d "$1/node_modules" ]; then
+        find "$1/node_modules" -name package.json -path '*/node_modules/*' -not -path '*/node_modules/*/node_modules/*/*' \
+            -exec dirname {} \; | while read -r package; do
+            (cd "$LOCAL/npm" && npm pack "$package" > /dev/null) || true
+        done
+    fi
+}
+
+if [ "$CACHE_IMPORT" = "true" ]; then
+    echo "Importing the cache"
+    if [ -n "$MAVEN_HOSTED" ]; then
+        find "$CACHE_DIR/maven" -type f -not -name '*.lastUpdated' -not -name '_remote.repositories' \
+            -not -name 'resolver-status.properties' -not -name 'maven-metadata*' | while read -r file; do
+            curl -sf -o /dev/null -u "$NEXUS_USERNAME:$NEXUS_PASSWORD" --upload-file "$file" \
+                "$NEXUS_URL/repository/$MAVEN_HOSTED/${file#$CACHE_DIR/maven/}" || true
+        done
+    fi
+    if [ -n "$NPM_HOSTED" ]; then
+        for file in "$CACHE_DIR"/npm/*.tgz; do
+            if [ -f "$file" ]; then
+                npm publish "$file" --registry "$NEXUS_URL/repository/$NPM_HOSTED/" > /dev/null 2>&1 || true
+            fi
+        done
+    fi
+fi
+
+for artifact in $MAVEN_ARTIFACTS; do
+    echo "Caching $artifact"
+    mvn -B -q -s "$WORK/settings.xml" dependency:get -Dartifact="$artifact" -Dtransitive=true
+done
+
+for package in $NPM_PACKAGES; do
+    echo "Caching $package"
+    npm install --prefix "$WORK/npm-install" --no-save --no-package-lock --ignore-scripts "$package"
+done
+export_npm "$WORK/npm-install"
+
+if [ -n "$SOURCE_URL" ]; then
+    echo "Caching the dependencies of $SOURCE_URL/$SOURCE_PATH"
+    git clone --depth 1 ${SOURCE_BRANCH:+--branch "$SOURCE_BRANCH"} "$SOURCE_URL" "$WORK/source"
+    cd "$WORK/source/$SOURCE_PATH"
+    if [ -f pom.xml ]; then
+        mvn -B -q -s "$WORK/settings.xml" dependency:go-offline
+    fi
+    if [ -f package.json ]; then
+        npm install --no-package-lock --ignore-scripts
+        export_npm "$WORK/source/$SOURCE_PATH"
+    fi
+fi
+echo "Cache warmed up"
+`
+
+// WarmUpPrivileges returns the privileges of the account of the warm-up: reading all the repositories, and
+// uploading to the Maven and npm hosted ones when importing a cache
+func WarmUpPrivileges(workshop *workshopv1.Workshop) []string {
+	privileges := []string{"nx-repository-view-*-*-browse", "nx-repository-view-*-*-read"}
+	cache := workshop.Spec.Infrastructure.Nexus.WarmUp.Cache
+	if cache.ClaimName == "" || !cache.Import {
+		return privileges
+	}
+	for _, format := range []string{"maven2", "npm"} {
+		if hosted := RepositoryName(workshop, format, "hosted"); hosted != "" {
+			privileges = append(privileges,
+				"nx-repository-view-"+format+"-"+hosted+"-add",
+				"nx-repository-view-"+format+"-"+hosted+"-edit")
+		}
+	}
+	return privileges
+}
+
+// NewWarmUpJob creates the Job warming up the cache of the server at serverURL, the credentials being read from
+// the username and password keys of the secret
+func NewWarmUpJob(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, namespace string, labels map[string]string, image string, serverURL string, secretName string,
+	checksum string) *batchv1.Job {
+
+	spec := workshop.Spec.Infrastructure.Nexus.WarmUp
+
+	sourceURL := ""
+	if spec.SourcePath != "" {
+		sourceURL = workshop.Spec.Source.GitURL
+	}
+	cacheDir := ""
+	volumes := []corev1.Volume{}
+	volumeMounts := []corev1.VolumeMount{}
+	if spec.Cache.ClaimName != "" {
+		cacheDir = warmUpCacheMountPath
+		volumes = append(volumes, corev1.Volume{
+			Name: "cache",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: spec.Cache.ClaimName},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: "cache", MountPath: warmUpCacheMountPath})
+	}
+
+	secretKey := func(key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  key,
+			},
+		}
+	}
+	env := []corev1.EnvVar{
+		{Name: "NEXUS_URL", Value: serverURL},
+		{Name: "NEXUS_USERNAME", ValueFrom: secretKey(UsernameKey)},
+		{Name: "NEXUS_PASSWORD", ValueFrom: secretKey(PasswordKey)},
+		{Name: "MAVEN_GROUP", Value: RepositoryName(workshop, "maven2", "group", "proxy")},
+		{Name: "MAVEN_HOSTED", Value: RepositoryName(workshop, "maven2", "hosted")},
+		{Name: "NPM_REGISTRY", Value: RepositoryName(workshop, "npm", "group", "proxy")},
+		{Name: "NPM_HOSTED", Value: RepositoryName(workshop, "npm", "hosted")},
+		{Name: "MAVEN_ARTIFACTS", Value: strings.Join(spec.Maven, " ")},
+		{Name: "NPM_PACKAGES", Value: strings.Join(spec.Npm, " ")},
+		{Name: "SOURCE_URL", Value: sourceURL},
+		{Name: "SOURCE_BRANCH", Value: workshop.Spec.Source.GitBranch},
+		{Name: "SOURCE_PATH", Value: spec.SourcePath},
+		{Name: "CACHE_DIR", Value: cacheDir},
+		{Name: "CACHE_EXPORT", Value: strconv.FormatBool(cacheDir != "" && spec.Cache.Export)},
+		{Name: "CACHE_IMPORT", Value: strconv.FormatBool(cacheDir != "" && spec.Cache.Import)},
+	}
+
+	backoffLimit := int32(2)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+			Annotations: map[string]string{
+				WarmUpChecksumAnnotation: checksum,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Volumes:       volumes,
+					Containers: []corev1.Container{
+						{
+							Name:         name,
+							Image:        image,
+							Command:      []string{"/bin/bash", "-c", warmUpScript},
+							Env:          env,
+							VolumeMounts: volumeMounts,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// Set Workshop instance as the owner and controller
+	err := ctrl.SetControllerReference(workshop, job, scheme)
+	if err != nil {
+		log.Error(err, "Failed to set SetControllerReference")
+	}
+	return job
+}
@@ -0,0 +1,127 @@
+package portal
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// ErrNoUserAvailable is returned when every attendee account has been claimed
+var ErrNoUserAvailable = errors.New("no user available")
+
+// ClaimStore keeps the username/email assignments in a ConfigMap.
+// Keys are usernames and values are the email addresses that claimed them.
+type ClaimStore struct {
+	clientset kubernetes.Interface
+	namespace string
+	name      string
+}
+
+// NewClaimStore returns a ClaimStore backed by the given ConfigMap
+func NewClaimStore(clientset kubernetes.Interface, namespace string, name string) *ClaimStore {
+	return &ClaimStore{
+		clientset: clientset,
+		namespace: namespace,
+		name:      name,
+	}
+}
+
+// List returns all claims indexed by username
+func (s *ClaimStore) List() (map[string]string, error) {
+	configMap, err := s.get()
+	if err != nil {
+		return nil, err
+	}
+	claims := map[string]string{}
+	for username, email := range configMap.Data {
+		claims[username] = email
+	}
+	return claims, nil
+}
+
+// Lookup returns the username claimed by the given email, if any
+func (s *ClaimStore) Lookup(email string) (string, error) {
+	claims, err := s.List()
+	if err != nil {
+		return "", err
+	}
+	return findUsername(claims, email), nil
+}
+
+// Claim returns the username assigned to the email, assigning the first free one if needed
+func (s *ClaimStore) Claim(config *Config, email string) (string, error) {
+	var username string
+	err := s.update(func(claims map[string]string) (bool, error) {
+		if username = findUsername(claims, email); username != "" {
+			return false, nil
+		}
+		for id := 1; id <= config.Users; id++ {
+			candidate := config.Username(id)
+			if _, claimed := claims[candidate]; !claimed {
+				username = candidate
+				claims[username] = email
+				return true, nil
+			}
+		}
+		return false, ErrNoUserAvailable
+	})
+	return username, err
+}
+
+// Release removes the claim on the given username
+func (s *ClaimStore) Release(username string) error {
+	return s.update(func(claims map[string]string) (bool, error) {
+		if _, claimed := claims[username]; !claimed {
+			return false, nil
+		}
+		delete(claims, username)
+		return true, nil
+	})
+}
+
+// Assign gives the username to the email, releasing any username the email held before
+func (s *ClaimStore) Assign(username string, email string) error {
+	return s.update(func(claims map[string]string) (bool, error) {
+		if previous := findUsername(claims, email); previous != "" {
+			delete(claims, previous)
+		}
+		claims[username] = email
+		return true, nil
+	})
+}
+
+func (s *ClaimStore) get() (*corev1.ConfigMap, error) {
+	return s.clientset.CoreV1().ConfigMaps(s.namespace).Get(context.TODO(), s.name, metav1.GetOptions{})
+}
+
+func (s *ClaimStore) update(mutate func(claims map[string]string) (bool, error)) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, err := s.get()
+		if err != nil {
+			return err
+		}
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		changed, err := mutate(configMap.Data)
+		if err != nil || !changed {
+			return err
+		}
+		_, err = s.clientset.CoreV1().ConfigMaps(s.namespace).Update(context.TODO(), configMap, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+func findUsername(claims map[string]string, email string) string {
+	for username, claimedBy := range claims {
+		if strings.EqualFold(claimedBy, email) {
+			return username
+		}
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package portal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+const (
+	// UsernamePlaceholder is replaced by the attendee username (e.g. user1)
+	UsernamePlaceholder = "%USERNAME%"
+	// UserIDPlaceholder is replaced by the attendee number (e.g. 1)
+	UserIDPlaceholder = "%USER_ID%"
+)
+
+// Config is the portal configuration generated by the operator
+type Config struct {
+	Title       string `json:"title"`
+	UserPrefix  string `json:"userPrefix"`
+	Users       int    `json:"users"`
+	AccessToken string `json:"accessToken"`
+	Links       []Item `json:"links"`
+	Credentials []Item `json:"credentials"`
+}
+
+// Item is a named value shown to an attendee. Values may contain placeholders.
+type Item struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// LoadConfig reads the portal configuration from a JSON file
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Username returns the username of the given attendee number
+func (c *Config) Username(id int) string {
+	return fmt.Sprintf("%s%d", c.UserPrefix, id)
+}
+
+// UserID returns the attendee number of the given username
+func (c *Config) UserID(username string) (int, bool) {
+	if !strings.HasPrefix(username, c.UserPrefix) {
+		return 0, false
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(username, c.UserPrefix))
+	if err != nil || id < 1 || id > c.Users {
+		return 0, false
+	}
+	return id, true
+}
+
+// LinksFor returns the links of the given attendee with placeholders replaced
+func (c *Config) LinksFor(id int) []Item {
+	return c.expand(c.Links, id)
+}
+
+// CredentialsFor returns the credentials of the given attendee with placeholders replaced
+func (c *Config) CredentialsFor(id int) []Item {
+	return c.expand(c.Credentials, id)
+}
+
+func (c *Config) expand(items []Item, id int) []Item {
+	replacer := strings.NewReplacer(UsernamePlaceholder, c.Username(id), UserIDPlaceholder, strconv.Itoa(id))
+	result := make([]Item, 0, len(items))
+	for _, item := range items {
+		result = append(result, Item{
+			Name:  item.Name,
+			Value: replacer.Replace(item.Value),
+		})
+	}
+	return result
+}
@@ -0,0 +1,162 @@
+package portal
+
+import (
+	"os"
+
+	"github.com/prometheus/common/log"
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// DefaultImageName is the operator image, which also ships the portal binary
+	DefaultImageName = "docker.io/stakater/workshop-operator"
+	// OperatorImageEnv is the environment variable of the manager holding its own image
+	OperatorImageEnv = "OPERATOR_IMAGE"
+	// ConfigFileName is the key of the portal configuration in its Secret
+	ConfigFileName = "config.json"
+	// AdminPasswordKey is the key of the admin password in its Secret
+	AdminPasswordKey = "password"
+	// CookieSecretKey is the key of the key signing the cookies of the attendees in the admin Secret
+	CookieSecretKey = "cookie-secret"
+	// Port is the HTTP port of the portal
+	Port = 8080
+)
+
+// Version is the version of the operator, set at build time with -ldflags "-X .../common/portal.Version=<version>"
+var Version = "0.0.1"
+
+// Image returns the operator image running the portal, which also ships the header filter of the OAuth proxies.
+// It defaults to the image of the running operator, or to the image of its version.
+func Image(workshop *workshopv1.Workshop) string {
+	image := workshop.Spec.Infrastructure.Portal.Image
+	if image.Name != "" {
+		if image.Tag == "" {
+			return image.Name
+		}
+		return image.Name + ":" + image.Tag
+	}
+	if operatorImage := os.Getenv(OperatorImageEnv); operatorImage != "" {
+		return operatorImage
+	}
+	return DefaultImageName + ":v" + Version
+}
+
+// NewDeployment create a deployment
+func NewDeployment(workshop *workshopv1.Workshop, scheme *runtime.Scheme,
+	name string, namespace string, labels map[string]string, serviceAccountName string,
+	configSecretName string, adminSecretName string, claimsConfigMapName string) *appsv1.Deployment {
+
+	image := Image(workshop)
+
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RollingUpdateDeploymentStrategyType,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: serviceAccountName,
+					Volumes: []corev1.Volume{
+						{
+							Name: "config",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									SecretName: configSecretName,
+								},
+							},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:    name,
+							Image:   image,
+							Command: []string{"/portal"},
+							Args: []string{
+								"--config=/etc/portal/" + ConfigFileName,
+								"--claims=" + claimsConfigMapName,
+							},
+							Env: []corev1.EnvVar{
+								{
+									Name: "POD_NAMESPACE",
+									ValueFrom: &corev1.EnvVarSource{
+										FieldRef: &corev1.ObjectFieldSelector{
+											FieldPath: "metadata.namespace",
+										},
+									},
+								},
+								{
+									Name: "PORTAL_ADMIN_PASSWORD",
+									ValueFrom: &corev1.EnvVarSource{
+										SecretKeyRef: &corev1.SecretKeySelector{
+											Key: AdminPasswordKey,
+											LocalObjectReference: corev1.LocalObjectReference{
+												Name: adminSecretName,
+											},
+										},
+									},
+								},
+								{
+									Name: "PORTAL_COOKIE_SECRET",
+									ValueFrom: &corev1.EnvVarSource{
+										SecretKeyRef: &corev1.SecretKeySelector{
+											Key: CookieSecretKey,
+											LocalObjectReference: corev1.LocalObjectReference{
+												Name: adminSecretName,
+											},
+										},
+									},
+								},
+							},
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							Ports: []corev1.ContainerPort{
+								{
+									ContainerPort: Port,
+									Protocol:      "TCP",
+								},
+							},
+							ReadinessProbe: &corev1.Probe{
+								Handler: corev1.Handler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Path: "/healthz",
+										Port: intstr.FromInt(Port),
+									},
+								},
+								InitialDelaySeconds: 2,
+								TimeoutSeconds:      1,
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "config",
+									MountPath: "/etc/portal",
+									ReadOnly:  true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// Set Workshop instance as the owner and controller
+	err := ctrl.SetControllerReference(workshop, dep, scheme)
+	if err != nil {
+		log.Error(err, "Failed to set SetControllerReference")
+	}
+	return dep
+}
@@ -0,0 +1,267 @@
+package portal
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"html/template"
+	"net/http"
+	"net/mail"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/common/log"
+)
+
+const emailCookieName = "workshop-portal-email"
+
+// Server serves the attendee and admin pages of the portal
+type Server struct {
+	configPath    string
+	adminPassword string
+	cookieSecret  []byte
+	claims        *ClaimStore
+}
+
+type attendeePage struct {
+	Title       string
+	Error       string
+	Email       string
+	Username    string
+	Links       []Item
+	Credentials []Item
+}
+
+type adminPage struct {
+	Title  string
+	Error  string
+	Claims []adminClaim
+}
+
+type adminClaim struct {
+	Username string
+	Email    string
+}
+
+// NewServer returns a portal Server, the email cookies of the attendees being signed with the cookie secret
+func NewServer(configPath string, adminPassword string, cookieSecret string, claims *ClaimStore) *Server {
+	return &Server{
+		configPath:    configPath,
+		adminPassword: adminPassword,
+		cookieSecret:  []byte(cookieSecret),
+		claims:        claims,
+	}
+}
+
+// signEmail returns the value of the email cookie, the base64 email followed by its HMAC-SHA256
+func (s *Server) signEmail(email string) string {
+	value := base64.RawURLEncoding.EncodeToString([]byte(email))
+	return value + "." + s.signature(value)
+}
+
+// verifyEmail returns the email of a cookie value, or false if its signature is not valid
+func (s *Server) verifyEmail(cookieValue string) (string, bool) {
+	parts := strings.SplitN(cookieValue, ".", 2)
+	if len(parts) != 2 || !hmac.Equal([]byte(parts[1]), []byte(s.signature(parts[0]))) {
+		return "", false
+	}
+	email, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", false
+	}
+	return string(email), true
+}
+
+func (s *Server) signature(value string) string {
+	mac := hmac.New(sha256.New, s.cookieSecret)
+	mac.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Handler returns the HTTP handler of the portal
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleAttendee)
+	mux.HandleFunc("/admin", s.requireAdmin(s.handleAdmin))
+	mux.HandleFunc("/admin/release", s.requireAdmin(s.handleRelease))
+	mux.HandleFunc("/admin/reassign", s.requireAdmin(s.handleReassign))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return mux
+}
+
+func (s *Server) handleAttendee(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != "/" {
+		http.NotFound(w, req)
+		return
+	}
+	config, err := LoadConfig(s.configPath)
+	if err != nil {
+		log.Errorf("Failed to load portal configuration: %v", err)
+		http.Error(w, "Portal is not configured", http.StatusServiceUnavailable)
+		return
+	}
+	page := &attendeePage{Title: config.Title}
+
+	switch req.Method {
+	case http.MethodGet:
+		if cookie, err := req.Cookie(emailCookieName); err == nil {
+			email, ok := s.verifyEmail(cookie.Value)
+			if !ok {
+				break
+			}
+			page.Email = email
+			username, err := s.claims.Lookup(page.Email)
+			if err != nil {
+				log.Errorf("Failed to look up claim for %s: %v", page.Email, err)
+				page.Error = "Unable to look up your account, please try again"
+			} else if username != "" {
+				s.fillAttendee(config, page, username)
+			}
+		}
+	case http.MethodPost:
+		page.Email = req.FormValue("email")
+		if _, err := mail.ParseAddress(page.Email); err != nil {
+			page.Error = "Please enter a valid email address"
+			break
+		}
+		if config.AccessToken != "" &&
+			subtle.ConstantTimeCompare([]byte(req.FormValue("accessToken")), []byte(config.AccessToken)) != 1 {
+			page.Error = "The access token is not valid"
+			break
+		}
+		username, err := s.claims.Claim(config, page.Email)
+		if err == ErrNoUserAvailable {
+			page.Error = "All accounts of this workshop have been assigned, please ask an instructor"
+			break
+		} else if err != nil {
+			log.Errorf("Failed to claim a user for %s: %v", page.Email, err)
+			page.Error = "Unable to assign an account, please try again"
+			break
+		}
+		log.Infof("Assigned %s to %s", username, page.Email)
+		http.SetCookie(w, &http.Cookie{Name: emailCookieName, Value: s.signEmail(page.Email), Path: "/", HttpOnly: true,
+			Secure: true, SameSite: http.SameSiteLaxMode})
+		s.fillAttendee(config, page, username)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	render(w, attendeeTemplate, page)
+}
+
+func (s *Server) fillAttendee(config *Config, page *attendeePage, username string) {
+	id, ok := config.UserID(username)
+	if !ok {
+		page.Error = "Your account is no longer part of this workshop, please ask an instructor"
+		return
+	}
+	page.Username = username
+	page.Links = config.LinksFor(id)
+	page.Credentials = config.CredentialsFor(id)
+}
+
+func (s *Server) handleAdmin(w http.ResponseWriter, req *http.Request) {
+	s.renderAdmin(w, "")
+}
+
+func (s *Server) handleRelease(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	username := req.FormValue("username")
+	if err := s.claims.Release(username); err != nil {
+		log.Errorf("Failed to release %s: %v", username, err)
+		s.renderAdmin(w, "Unable to release "+username)
+		return
+	}
+	log.Infof("Released %s", username)
+	http.Redirect(w, req, "/admin", http.StatusSeeOther)
+}
+
+func (s *Server) handleReassign(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	config, err := LoadConfig(s.configPath)
+	if err != nil {
+		log.Errorf("Failed to load portal configuration: %v", err)
+		http.Error(w, "Portal is not configured", http.StatusServiceUnavailable)
+		return
+	}
+	username := req.FormValue("username")
+	email := req.FormValue("email")
+	if _, ok := config.UserID(username); !ok {
+		s.renderAdmin(w, username+" is not a user of this workshop")
+		return
+	}
+	if _, err := mail.ParseAddress(email); err != nil {
+		s.renderAdmin(w, email+" is not a valid email address")
+		return
+	}
+	if err := s.claims.Assign(username, email); err != nil {
+		log.Errorf("Failed to assign %s to %s: %v", username, email, err)
+		s.renderAdmin(w, "Unable to assign "+username)
+		return
+	}
+	log.Infof("Reassigned %s to %s", username, email)
+	http.Redirect(w, req, "/admin", http.StatusSeeOther)
+}
+
+func (s *Server) renderAdmin(w http.ResponseWriter, message string) {
+	config, err := LoadConfig(s.configPath)
+	if err != nil {
+		log.Errorf("Failed to load portal configuration: %v", err)
+		http.Error(w, "Portal is not configured", http.StatusServiceUnavailable)
+		return
+	}
+	claims, err := s.claims.List()
+	if err != nil {
+		log.Errorf("Failed to list claims: %v", err)
+		http.Error(w, "Unable to list claims", http.StatusInternalServerError)
+		return
+	}
+
+	page := &adminPage{Title: config.Title, Error: message}
+	for id := 1; id <= config.Users; id++ {
+		username := config.Username(id)
+		page.Claims = append(page.Claims, adminClaim{Username: username, Email: claims[username]})
+		delete(claims, username)
+	}
+	// Claims left over from a larger workshop are still listed so they can be released
+	leftovers := []string{}
+	for username := range claims {
+		leftovers = append(leftovers, username)
+	}
+	sort.Strings(leftovers)
+	for _, username := range leftovers {
+		page.Claims = append(page.Claims, adminClaim{Username: username, Email: claims[username]})
+	}
+
+	render(w, adminTemplate, page)
+}
+
+func (s *Server) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		username, password, ok := req.BasicAuth()
+		if !ok || username != "admin" ||
+			subtle.ConstantTimeCompare([]byte(password), []byte(s.adminPassword)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Basic realm="workshop-portal"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, req)
+	}
+}
+
+func render(w http.ResponseWriter, tmpl *template.Template, data interface{}) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Errorf("Failed to render %s: %v", tmpl.Name(), err)
+	}
+}
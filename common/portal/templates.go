@@ -0,0 +1,59 @@
+package portal
+
+import "html/template"
+
+const layout = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em auto; max-width: 50em; color: #151515; }
+table { border-collapse: collapse; width: 100%; }
+td, th { border-bottom: 1px solid #d2d2d2; padding: 0.4em; text-align: left; }
+.error { color: #c9190b; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+`
+
+var attendeeTemplate = template.Must(template.New("attendee").Parse(layout + `
+{{if .Username}}
+<p>Welcome <b>{{.Email}}</b>, you have been assigned <b>{{.Username}}</b>.</p>
+<h2>Credentials</h2>
+<table>
+{{range .Credentials}}<tr><th>{{.Name}}</th><td><code>{{.Value}}</code></td></tr>
+{{end}}
+</table>
+<h2>Links</h2>
+<table>
+{{range .Links}}<tr><th>{{.Name}}</th><td><a href="{{.Value}}" target="_blank">{{.Value}}</a></td></tr>
+{{end}}
+</table>
+{{else}}
+<form method="post" action="/">
+<p><label>Email <input type="email" name="email" value="{{.Email}}" required></label></p>
+<p><label>Access token <input type="password" name="accessToken"></label></p>
+<p><button type="submit">Get my account</button></p>
+</form>
+{{end}}
+</body>
+</html>
+`))
+
+var adminTemplate = template.Must(template.New("admin").Parse(layout + `
+<table>
+<tr><th>User</th><th>Email</th><th></th><th></th></tr>
+{{range .Claims}}<tr>
+<td>{{.Username}}</td>
+<td>{{if .Email}}{{.Email}}{{else}}<i>available</i>{{end}}</td>
+<td>{{if .Email}}<form method="post" action="/admin/release"><input type="hidden" name="username" value="{{.Username}}"><button type="submit">Release</button></form>{{end}}</td>
+<td><form method="post" action="/admin/reassign"><input type="hidden" name="username" value="{{.Username}}"><input type="email" name="email" placeholder="email" required><button type="submit">Assign</button></form></td>
+</tr>
+{{end}}
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,90 @@
+package source
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// TarballFetcher reads the files of a tar archive, gzipped or not, served over HTTP(S)
+type TarballFetcher struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewTarballFetcher returns a fetcher of the archive at url, downloaded with the token as a bearer token when set
+func NewTarballFetcher(url string, token string, rootCAs *x509.CertPool) *TarballFetcher {
+	return &TarballFetcher{url: url, token: token, client: newHTTPClient(rootCAs)}
+}
+
+// Fetch returns the content of the file at path, relative to the root of the archive or to its single top-level
+// directory, as in the archives of Git servers
+func (f *TarballFetcher) Fetch(filePath string) ([]byte, error) {
+	headers := map[string]string{}
+	if f.token != "" {
+		headers["Authorization"] = "Bearer " + f.token
+	}
+	archive, err := httpGet(f.client, f.url, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(bytes.NewReader(archive))
+	var tarReader *tar.Reader
+	if magic, err := reader.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReader.Close()
+		tarReader = tar.NewReader(gzipReader)
+	} else {
+		tarReader = tar.NewReader(reader)
+	}
+
+	filePath = path.Clean(filePath)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+		if name == filePath || (strings.Count(name, "/") == strings.Count(filePath, "/")+1 && strings.HasSuffix(name, "/"+filePath)) {
+			return ioutil.ReadAll(tarReader)
+		}
+	}
+	return nil, fmt.Errorf("source: %s not found in %s", filePath, f.url)
+}
+
+// ConfigMapFetcher reads the files from the data of a ConfigMap, keyed by their file name
+type ConfigMapFetcher struct {
+	data map[string]string
+}
+
+// NewConfigMapFetcher returns a fetcher of the data of a ConfigMap
+func NewConfigMapFetcher(data map[string]string) *ConfigMapFetcher {
+	return &ConfigMapFetcher{data: data}
+}
+
+// Fetch returns the value of the key named after the file name of path
+func (f *ConfigMapFetcher) Fetch(filePath string) ([]byte, error) {
+	content, ok := f.data[path.Base(filePath)]
+	if !ok {
+		return nil, fmt.Errorf("source: no %s key in the ConfigMap", path.Base(filePath))
+	}
+	return []byte(content), nil
+}
@@ -0,0 +1,157 @@
+package source
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+)
+
+// Supported providers
+const (
+	ProviderGitHub    = "github"
+	ProviderGitLab    = "gitlab"
+	ProviderGitea     = "gitea"
+	ProviderTarball   = "tarball"
+	ProviderConfigMap = "configmap"
+)
+
+// DefaultDevfilePath is the path of the devfile unless set in the Workshop
+const DefaultDevfilePath = "devfile.yaml"
+
+// Fetcher reads the files of the workshop source
+type Fetcher interface {
+	// Fetch returns the content of the file at path, relative to the root of the source
+	Fetch(path string) ([]byte, error)
+}
+
+// ProviderName returns the provider of the source, detected from its URL unless set, or an empty string when it
+// can not be detected
+func ProviderName(spec workshopv1.SourceSpec) string {
+	if spec.Provider != "" {
+		return spec.Provider
+	}
+	if spec.ConfigMapName != "" {
+		return ProviderConfigMap
+	}
+
+	sourceURL, err := url.Parse(spec.GitURL)
+	if err != nil {
+		return ""
+	}
+	switch {
+	case strings.HasSuffix(sourceURL.Path, ".tar.gz") || strings.HasSuffix(sourceURL.Path, ".tgz") ||
+		strings.HasSuffix(sourceURL.Path, ".tar"):
+		return ProviderTarball
+	case sourceURL.Host == "github.com":
+		return ProviderGitHub
+	case strings.Contains(sourceURL.Host, "gitlab"):
+		return ProviderGitLab
+	case strings.Contains(sourceURL.Host, "gitea"):
+		return ProviderGitea
+	}
+	return ""
+}
+
+// DevfilePath returns the path of the devfile in the source
+func DevfilePath(spec workshopv1.SourceSpec) string {
+	if spec.DevfilePath == "" {
+		return DefaultDevfilePath
+	}
+	return strings.TrimPrefix(spec.DevfilePath, "/")
+}
+
+// ParseRepository splits the URL of a Git repository into the URL of its server and the path of the repository
+func ParseRepository(gitURL string) (string, string, error) {
+	repositoryURL, err := url.Parse(gitURL)
+	if err != nil {
+		return "", "", err
+	}
+	if repositoryURL.Scheme == "" || repositoryURL.Host == "" {
+		return "", "", fmt.Errorf("source: %s is not the URL of a repository", gitURL)
+	}
+	repository := strings.TrimSuffix(strings.Trim(repositoryURL.Path, "/"), ".git")
+	if !strings.Contains(repository, "/") {
+		return "", "", fmt.Errorf("source: %s is not the URL of a repository", gitURL)
+	}
+	return repositoryURL.Scheme + "://" + repositoryURL.Host, repository, nil
+}
+
+// NewFetcher returns the fetcher of the Git repository or tarball of the source, authenticated with the token
+// when set and verifying the certificate of the server with rootCAs. ConfigMap sources are read with
+// NewConfigMapFetcher.
+func NewFetcher(spec workshopv1.SourceSpec, provider string, token string, rootCAs *x509.CertPool) (Fetcher, error) {
+	if provider == ProviderTarball {
+		return NewTarballFetcher(spec.GitURL, token, rootCAs), nil
+	}
+
+	serverURL, repository, err := ParseRepository(spec.GitURL)
+	if err != nil {
+		return nil, err
+	}
+	switch provider {
+	case ProviderGitHub:
+		return NewGitHubFetcher(serverURL, repository, spec.GitBranch, token, rootCAs), nil
+	case ProviderGitLab:
+		return NewGitLabFetcher(serverURL, repository, spec.GitBranch, token, rootCAs), nil
+	case ProviderGitea:
+		return NewGiteaFetcher(serverURL, repository, spec.GitBranch, token, rootCAs), nil
+	case "":
+		return nil, fmt.Errorf("source: the provider of %s can not be detected and must be set", spec.GitURL)
+	default:
+		return nil, fmt.Errorf("source: unknown provider %s", provider)
+	}
+}
+
+// newHTTPClient returns a client verifying the certificates of the servers with rootCAs, or with the system pool
+// when nil, as the tokens of the sources are sent with the requests
+func newHTTPClient(rootCAs *x509.CertPool) *http.Client {
+	return &http.Client{
+		Timeout: 60 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: rootCAs},
+		},
+	}
+}
+
+// httpGet returns the body of the URL, requested with the headers
+func httpGet(client *http.Client, fileURL string, headers map[string]string) ([]byte, error) {
+	request, err := http.NewRequest("GET", fileURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("source: GET %s returned %d", request.URL.Path, response.StatusCode)
+	}
+	return body, nil
+}
+
+// escapePath escapes the segments of a file path for a URL
+func escapePath(filePath string) string {
+	segments := strings.Split(path.Clean(filePath), "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,96 @@
+package source
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// GitHubFetcher reads the files of a GitHub or GitHub Enterprise Server repository through the contents API
+type GitHubFetcher struct {
+	apiURL     string
+	repository string
+	branch     string
+	token      string
+	client     *http.Client
+}
+
+// NewGitHubFetcher returns a fetcher of the repository on the server, at the branch or the default one, the
+// certificate of the server being verified with rootCAs
+func NewGitHubFetcher(serverURL string, repository string, branch string, token string, rootCAs *x509.CertPool) *GitHubFetcher {
+	apiURL := serverURL + "/api/v3"
+	if serverURL == "https://github.com" {
+		apiURL = "https://api.github.com"
+	}
+	return &GitHubFetcher{apiURL: apiURL, repository: repository, branch: branch, token: token, client: newHTTPClient(rootCAs)}
+}
+
+// Fetch returns the content of the file at path
+func (f *GitHubFetcher) Fetch(path string) ([]byte, error) {
+	fileURL := fmt.Sprintf("%s/repos/%s/contents/%s", f.apiURL, f.repository, escapePath(path))
+	if f.branch != "" {
+		fileURL += "?ref=" + url.QueryEscape(f.branch)
+	}
+	headers := map[string]string{"Accept": "application/vnd.github.v3.raw"}
+	if f.token != "" {
+		headers["Authorization"] = "token " + f.token
+	}
+	return httpGet(f.client, fileURL, headers)
+}
+
+// GitLabFetcher reads the files of a GitLab repository through the repository files API
+type GitLabFetcher struct {
+	serverURL  string
+	repository string
+	branch     string
+	token      string
+	client     *http.Client
+}
+
+// NewGitLabFetcher returns a fetcher of the repository, with its group path, at the branch or the default one
+func NewGitLabFetcher(serverURL string, repository string, branch string, token string, rootCAs *x509.CertPool) *GitLabFetcher {
+	return &GitLabFetcher{serverURL: serverURL, repository: repository, branch: branch, token: token, client: newHTTPClient(rootCAs)}
+}
+
+// Fetch returns the content of the file at path
+func (f *GitLabFetcher) Fetch(path string) ([]byte, error) {
+	branch := f.branch
+	if branch == "" {
+		branch = "HEAD"
+	}
+	fileURL := fmt.Sprintf("%s/api/v4/projects/%s/repository/files/%s/raw?ref=%s", f.serverURL,
+		url.PathEscape(f.repository), url.PathEscape(path), url.QueryEscape(branch))
+	headers := map[string]string{}
+	if f.token != "" {
+		headers["Private-Token"] = f.token
+	}
+	return httpGet(f.client, fileURL, headers)
+}
+
+// GiteaFetcher reads the files of a Gitea repository through the raw API
+type GiteaFetcher struct {
+	serverURL  string
+	repository string
+	branch     string
+	token      string
+	client     *http.Client
+}
+
+// NewGiteaFetcher returns a fetcher of the repository, at the branch or the default one
+func NewGiteaFetcher(serverURL string, repository string, branch string, token string, rootCAs *x509.CertPool) *GiteaFetcher {
+	return &GiteaFetcher{serverURL: serverURL, repository: repository, branch: branch, token: token, client: newHTTPClient(rootCAs)}
+}
+
+// Fetch returns the content of the file at path
+func (f *GiteaFetcher) Fetch(path string) ([]byte, error) {
+	fileURL := fmt.Sprintf("%s/api/v1/repos/%s/raw/%s", f.serverURL, f.repository, escapePath(path))
+	if f.branch != "" {
+		fileURL += "?ref=" + url.QueryEscape(f.branch)
+	}
+	headers := map[string]string{}
+	if f.token != "" {
+		headers["Authorization"] = "token " + f.token
+	}
+	return httpGet(f.client, fileURL, headers)
+}
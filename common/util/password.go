@@ -0,0 +1,22 @@
+package util
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+const passwordCharset = "abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// GeneratePassword returns a random alphanumeric password of the given length
+func GeneratePassword(length int) (string, error) {
+	password := make([]byte, length)
+	max := big.NewInt(int64(len(passwordCharset)))
+	for i := range password {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		password[i] = passwordCharset[n.Int64()]
+	}
+	return string(password), nil
+}
@@ -1,5 +1,7 @@
 package util
 
+import "sort"
+
 func StringInSlice(str string, list []string) bool {
 	for _, v := range list {
 		if v == str {
@@ -8,3 +10,13 @@ func StringInSlice(str string, list []string) bool {
 	}
 	return false
 }
+
+// SortedKeys returns the keys of the map in ascending order
+func SortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -112,7 +112,7 @@ func (r *WorkshopReconciler) addUpdateBookbag(workshop *workshopv1.Workshop, use
 	}
 
 	// Deploy/Update Bookbag
-	dep := bookbag.NewDeployment(workshop, r.Scheme, bookbagName, BOOKBAG_NAMESPACE_NAME, labels, userID, appsHostnameSuffix, openshiftConsoleURL)
+	dep := bookbag.NewDeployment(workshop, r.Scheme, bookbagName, BOOKBAG_NAMESPACE_NAME, labels, userID, appsHostnameSuffix, openshiftConsoleURL, gitServerURL(workshop, appsHostnameSuffix))
 	if err := r.Create(context.TODO(), dep); err != nil && !errors.IsAlreadyExists(err) {
 		return reconcile.Result{}, err
 	} else if err == nil {
@@ -164,21 +164,21 @@ func (r *WorkshopReconciler) deleteBookbag(workshop *workshopv1.Workshop, userID
 
 		route := kubernetes.NewRoute(workshop, r.Scheme, bookbagName, BOOKBAG_NAMESPACE_NAME, labels, bookbagName, BOOKBAG_PORT)
 		// Delete route
-		if err := r.Delete(context.TODO(), route); err != nil {
+		if err := r.Delete(context.TODO(), route); err != nil && !errors.IsNotFound(err) {
 			return reconcile.Result{}, err
 		}
 		log.Infof("Deleted %s Route", route.Name)
 
 		service := kubernetes.NewService(workshop, r.Scheme, bookbagName, BOOKBAG_NAMESPACE_NAME, labels, []string{"http"}, []int32{BOOKBAG_PORT})
 		// Delete Service
-		if err := r.Delete(context.TODO(), service); err != nil {
+		if err := r.Delete(context.TODO(), service); err != nil && !errors.IsNotFound(err) {
 			return reconcile.Result{}, err
 		}
 		log.Infof("Deleted %s Service", service.Name)
 
-		dep := bookbag.NewDeployment(workshop, r.Scheme, bookbagName, BOOKBAG_NAMESPACE_NAME, labels, strconv.Itoa(userID), appsHostnameSuffix, openshiftConsoleURL)
+		dep := bookbag.NewDeployment(workshop, r.Scheme, bookbagName, BOOKBAG_NAMESPACE_NAME, labels, strconv.Itoa(userID), appsHostnameSuffix, openshiftConsoleURL, gitServerURL(workshop, appsHostnameSuffix))
 		// Delete Deployment
-		if err := r.Delete(context.TODO(), dep); err != nil {
+		if err := r.Delete(context.TODO(), dep); err != nil && !errors.IsNotFound(err) {
 			return reconcile.Result{}, err
 		}
 		log.Infof("Deleted %s Deployment", dep.Name)
@@ -188,27 +188,27 @@ func (r *WorkshopReconciler) deleteBookbag(workshop *workshopv1.Workshop, userID
 		roleBinding := kubernetes.NewRoleBindingSA(workshop, r.Scheme, bookbagName, BOOKBAG_NAMESPACE_NAME, labels,
 			serviceAccount.Name, BOOKBAG_ROLE_BINDING_NAME, BOOKBAG_ROLE_KIND_NAME)
 		//Delete  Role Binding
-		if err := r.Delete(context.TODO(), roleBinding); err != nil {
+		if err := r.Delete(context.TODO(), roleBinding); err != nil && !errors.IsNotFound(err) {
 			return reconcile.Result{}, err
 		}
 		log.Infof("Deleted %s RoleBinding", roleBinding.Name)
 
 		// Delete  Service Account
-		if err := r.Delete(context.TODO(), serviceAccount); err != nil {
+		if err := r.Delete(context.TODO(), serviceAccount); err != nil && !errors.IsNotFound(err) {
 			return reconcile.Result{}, err
 		}
 		log.Infof("Deleted %s Service Account", serviceAccount.Name)
 
 		varConfigMap := kubernetes.NewConfigMap(workshop, r.Scheme, bookbagName+"-vars", BOOKBAG_NAMESPACE_NAME, labels, nil)
 		// Delete ConfigMap
-		if err := r.Delete(context.TODO(), varConfigMap); err != nil {
+		if err := r.Delete(context.TODO(), varConfigMap); err != nil && !errors.IsNotFound(err) {
 			return reconcile.Result{}, err
 		}
 		log.Infof("Deleted %s ConfigMap", varConfigMap.Name)
 
 		envConfigMap := kubernetes.NewConfigMap(workshop, r.Scheme, bookbagName+"-env", BOOKBAG_NAMESPACE_NAME, labels, bookbagConfigData)
 		// Delete ConfigMap
-		if err := r.Delete(context.TODO(), envConfigMap); err != nil {
+		if err := r.Delete(context.TODO(), envConfigMap); err != nil && !errors.IsNotFound(err) {
 			return reconcile.Result{}, err
 		}
 		log.Infof("Deleted %s ConfigMap", envConfigMap.Name)
@@ -223,7 +223,7 @@ func (r *WorkshopReconciler) deleteBookbag(workshop *workshopv1.Workshop, userID
 
 	namespace := kubernetes.NewNamespace(workshop, r.Scheme, BOOKBAG_NAMESPACE_NAME)
 	// delete namespace
-	if err := r.Delete(context.TODO(), namespace); err != nil {
+	if err := r.Delete(context.TODO(), namespace); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s namespace", namespace.Name)
@@ -82,7 +82,7 @@ func (r *WorkshopReconciler) deleteCertManager(workshop *workshopv1.Workshop, us
 	certmanagerresourceErr := r.Get(context.TODO(), types.NamespacedName{Name: customresource.Name, Namespace: namespace.Name}, certmanagerresourceFound)
 	if certmanagerresourceErr == nil {
 		// Delete cert-manager resource
-		if err := r.Delete(context.TODO(), customresource); err != nil {
+		if err := r.Delete(context.TODO(), customresource); err != nil && !errors.IsNotFound(err) {
 			return reconcile.Result{}, err
 		}
 		log.Infof("Deleted %s cert-manager resource", customresource.Name)
@@ -92,7 +92,7 @@ func (r *WorkshopReconciler) deleteCertManager(workshop *workshopv1.Workshop, us
 	certmanagerNameSpaceErr := r.Get(context.TODO(), types.NamespacedName{Name: namespace.Name}, certmanagerNameSpaceFound)
 	if certmanagerNameSpaceErr == nil {
 		// Delete cert-manager NameSpace
-		if err := r.Delete(context.TODO(), namespace); err != nil {
+		if err := r.Delete(context.TODO(), namespace); err != nil && !errors.IsNotFound(err) {
 			return reconcile.Result{}, err
 		}
 		log.Infof("Deleted %s cert-manager namespace", namespace.Name)
@@ -103,7 +103,7 @@ func (r *WorkshopReconciler) deleteCertManager(workshop *workshopv1.Workshop, us
 	certManagerSubscriptionErr := r.Get(context.TODO(), types.NamespacedName{Name: CertManagerSubscription.Name, Namespace: namespace.Name}, certManagerSubscriptionFund)
 	if certManagerSubscriptionErr == nil {
 		// Delete certManager Subscription
-		if err := r.Delete(context.TODO(), CertManagerSubscription); err != nil {
+		if err := r.Delete(context.TODO(), CertManagerSubscription); err != nil && !errors.IsNotFound(err) {
 			return reconcile.Result{}, err
 		}
 		log.Infof("Deleted %s cert-manager Subscription", CertManagerSubscription.Name)
@@ -1,12 +1,11 @@
 package controllers
 
 import (
-	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -15,13 +14,19 @@ import (
 
 	_ "k8s.io/api/rbac/v1"
 
+	che "github.com/eclipse/che-operator/pkg/apis/org/v1"
 	"github.com/prometheus/common/log"
 	workshopv1 "github.com/stakater/workshop-operator/api/v1"
 	"github.com/stakater/workshop-operator/common/codeready"
+	"github.com/stakater/workshop-operator/common/keycloak"
 	"github.com/stakater/workshop-operator/common/kubernetes"
+	"github.com/stakater/workshop-operator/common/source"
 	"github.com/stakater/workshop-operator/common/util"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/yaml"
 )
@@ -42,6 +47,10 @@ const (
 	CHE_CLUSTER_ROLE_BINDING_NAME       = "che"
 	CHE_SERVICEACCOUNT_NAME             = "che"
 	CHE_CODE_FLAVOR_NAME                = "codeready"
+	CODEREADY_ADMIN_SECRET_NAME         = "codeready-keycloak-admin"
+	INGRESS_CA_CONFIGMAP_NAME           = "default-ingress-cert"
+	INGRESS_CA_CONFIGMAP_NAMESPACE      = "openshift-config-managed"
+	INGRESS_CA_CONFIGMAP_KEY            = "ca-bundle.crt"
 )
 
 // Reconciling CodeReadyWorkspace
@@ -102,11 +111,28 @@ func (r *WorkshopReconciler) addCodeReadyWorkspace(workshop *workshopv1.Workshop
 		return reconcile.Result{Requeue: true}, nil
 	}
 
-	codeReadyWorkspacesCustomResource := codeready.NewCustomResource(workshop, r.Scheme, CHE_CUSTOM_RESOURCE_NAME, CODEREADY_NAMESPACE_NAME)
+	adminUsername, adminPassword, err := r.getCodeReadyAdminCredentials(workshop)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Create/Update Custom Resource
+	codeReadyWorkspacesCustomResource := codeready.NewCustomResource(workshop, r.Scheme, CHE_CUSTOM_RESOURCE_NAME, CODEREADY_NAMESPACE_NAME,
+		adminUsername, adminPassword)
 	if err := r.Create(context.TODO(), codeReadyWorkspacesCustomResource); err != nil && !errors.IsAlreadyExists(err) {
 		return reconcile.Result{}, err
 	} else if err == nil {
 		log.Infof("Created %s Custom Resource", codeReadyWorkspacesCustomResource.Name)
+	} else if errors.IsAlreadyExists(err) {
+		customResourceFound := &che.CheCluster{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: codeReadyWorkspacesCustomResource.Name, Namespace: CODEREADY_NAMESPACE_NAME}, customResourceFound); err != nil {
+			return reconcile.Result{}, err
+		} else if codeready.UpdateCustomResource(customResourceFound, codeReadyWorkspacesCustomResource) {
+			if err := r.Update(context.TODO(), customResourceFound); err != nil {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Updated %s Custom Resource", customResourceFound.Name)
+		}
 	}
 
 	// Wait for CodeReadyWorkspace to be running
@@ -115,17 +141,24 @@ func (r *WorkshopReconciler) addCodeReadyWorkspace(workshop *workshopv1.Workshop
 	}
 
 	// Initialize Workspaces from devfile
-	devfile, result, err := getDevFile(workshop)
+	devfile, result, err := r.getDevFile(workshop, appsHostnameSuffix)
 	if err != nil {
 		return result, err
 	}
 
 	// Users and Workspaces
+	keycloakClient, err := r.newKeycloakClient(appsHostnameSuffix, adminUsername, adminPassword)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	tlsConfig, err := r.ingressTLSConfig()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	openshiftUserPassword := workshop.Spec.User.Password
+	failed := 0
 	if !workshop.Spec.Infrastructure.CodeReadyWorkspace.OpenshiftOAuth {
-		masterAccessToken, result, err := getKeycloakAdminToken(workshop, CODEREADY_NAMESPACE_NAME, appsHostnameSuffix)
-		if err != nil {
-			return result, err
-		}
 
 		// Create Che Cluster Role
 		cheClusterRole :=
@@ -144,204 +177,92 @@ func (r *WorkshopReconciler) addCodeReadyWorkspace(workshop *workshopv1.Workshop
 			log.Infof("Created %s Cluster Role Binding", cheClusterRoleBinding.Name)
 		}
 
+		// Create/Update the users in bulk
+		keycloakUsers := []keycloak.User{}
 		for id := 1; id <= users; id++ {
-			username := fmt.Sprintf("user%d", id)
+			keycloakUsers = append(keycloakUsers, codeready.NewUser(fmt.Sprintf("user%d", id), openshiftUserPassword))
+		}
+		ensured, err := keycloakClient.EnsureUsers(CHE_CODE_FLAVOR_NAME, keycloakUsers)
+		if err != nil {
+			log.Errorf("Error when importing the users in CodeReady Workspaces: %v", err)
+			return reconcile.Result{}, err
+		}
+		if ensured.Added > 0 || ensured.Updated > 0 {
+			log.Infof("Created %d and updated %d users in CodeReady Workspaces", ensured.Added, ensured.Updated)
+		}
 
-			if result, err := createUser(workshop, username, CHE_CODE_FLAVOR_NAME, CODEREADY_NAMESPACE_NAME, appsHostnameSuffix, masterAccessToken); err != nil {
-				return result, err
-			}
+		for id := 1; id <= users; id++ {
+			username := fmt.Sprintf("user%d", id)
 
-			userAccessToken, result, err := getUserToken(workshop, username, CHE_CODE_FLAVOR_NAME, CODEREADY_NAMESPACE_NAME, appsHostnameSuffix)
+			userAccessToken, err := getCodeReadyUserToken(keycloakClient, username, ensured.IDs[username], openshiftUserPassword)
 			if err != nil {
-				return result, err
+				log.Errorf("Error when getting the token of %s from CodeReady Workspaces: %v", username, err)
+				failed++
+				continue
 			}
 
-			if result, err := initWorkspace(workshop, username, CHE_CODE_FLAVOR_NAME, CODEREADY_NAMESPACE_NAME, userAccessToken, devfile, appsHostnameSuffix); err != nil {
-				return result, err
+			if _, err := initWorkspace(workshop, username, CHE_CODE_FLAVOR_NAME, CODEREADY_NAMESPACE_NAME, userAccessToken, devfile, appsHostnameSuffix, tlsConfig); err != nil {
+				failed++
 			}
-
 		}
 	} else {
 		for id := 1; id <= users; id++ {
 			username := fmt.Sprintf("user%d", id)
 
-			userAccessToken, result, err := getOAuthUserToken(workshop, username, CHE_CODE_FLAVOR_NAME, CODEREADY_NAMESPACE_NAME, appsHostnameSuffix)
+			userAccessToken, _, err := getOAuthUserToken(workshop, username, CHE_CODE_FLAVOR_NAME, CODEREADY_NAMESPACE_NAME, appsHostnameSuffix, tlsConfig)
 			if err != nil {
-				return result, err
+				failed++
+				continue
 			}
 
-			if result, err := updateUserEmail(workshop, username, CHE_CODE_FLAVOR_NAME, CODEREADY_NAMESPACE_NAME, appsHostnameSuffix); err != nil {
-				return result, err
+			if err := updateCodeReadyUserEmail(keycloakClient, username); err != nil {
+				log.Errorf("Error when updating the email of %s in CodeReady Workspaces: %v", username, err)
+				failed++
+				continue
 			}
 
-			if result, err := initWorkspace(workshop, username, CHE_CODE_FLAVOR_NAME, CODEREADY_NAMESPACE_NAME, userAccessToken, devfile, appsHostnameSuffix); err != nil {
-				return result, err
+			if _, err := initWorkspace(workshop, username, CHE_CODE_FLAVOR_NAME, CODEREADY_NAMESPACE_NAME, userAccessToken, devfile, appsHostnameSuffix, tlsConfig); err != nil {
+				failed++
 			}
 		}
 	}
 
+	if failed > 0 {
+		return reconcile.Result{}, fmt.Errorf("failed to initialize the workspaces of %d users", failed)
+	}
+
 	//Success
 	return reconcile.Result{}, nil
 }
 
 // Get DevFile
-func getDevFile(workshop *workshopv1.Workshop) (string, reconcile.Result, error) {
+func (r *WorkshopReconciler) getDevFile(workshop *workshopv1.Workshop, appsHostnameSuffix string) (string, reconcile.Result, error) {
 
-	var (
-		httpResponse *http.Response
-		httpRequest  *http.Request
-		devfile      string
-		client       = &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-			},
-			// Do not follow Redirect
-			CheckRedirect: func(req *http.Request, via []*http.Request) error {
-				return http.ErrUseLastResponse
-			},
-		}
-	)
-
-	gitURL, err := url.Parse(workshop.Spec.Source.GitURL)
-	if err != nil {
-		return "", reconcile.Result{}, err
-	}
-	devfileRawURL := fmt.Sprintf("https://raw.githubusercontent.com%s/%s/devfile.yaml", gitURL.Path, workshop.Spec.Source.GitBranch)
-	httpRequest, err = http.NewRequest("GET", devfileRawURL, nil)
-	if err != nil {
-		log.Error(err, "Failed http GET Request")
-	}
-	httpResponse, err = client.Do(httpRequest)
+	fetcher, err := r.newSourceFetcher(workshop, appsHostnameSuffix)
 	if err != nil {
-		log.Errorf("Error when getting Devfile from %s", devfileRawURL)
+		log.Errorf("Error when reading the workshop source: %v", err)
 		return "", reconcile.Result{}, err
 	}
 
-	if httpResponse.StatusCode == http.StatusOK {
-		bodyBytes, err := ioutil.ReadAll(httpResponse.Body)
-		if err != nil {
-			log.Errorf("Error when reading %s", devfileRawURL)
-			return "", reconcile.Result{}, err
-		}
-
-		bodyJSON, err := yaml.YAMLToJSON(bodyBytes)
-		if err != nil {
-			log.Errorf("Error to converting %s to JSON", devfileRawURL)
-			return "", reconcile.Result{}, err
-		}
-		devfile = string(bodyJSON)
-	} else {
-		log.Errorf("Error (%v) when getting Devfile from %s", httpResponse.StatusCode, devfileRawURL)
-		return "", reconcile.Result{}, err
-	}
-
-	return devfile, reconcile.Result{}, nil
-}
-
-// Create user
-func createUser(workshop *workshopv1.Workshop, username string, codeflavor string,
-	namespace string, appsHostnameSuffix string, masterToken string) (reconcile.Result, error) {
-
-	var (
-		openshiftUserPassword = workshop.Spec.User.Password
-		body                  []byte
-		err                   error
-		httpResponse          *http.Response
-		httpRequest           *http.Request
-		keycloakCheUserURL    = "https://keycloak-" + namespace + "." + appsHostnameSuffix + "/auth/admin/realms/" + codeflavor + "/users"
-
-		client = &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-			},
-			// Do not follow Redirect
-			CheckRedirect: func(req *http.Request, via []*http.Request) error {
-				return http.ErrUseLastResponse
-			},
-		}
-	)
-
-	body, err = json.Marshal(codeready.NewUser(username, openshiftUserPassword))
+	devfilePath := source.DevfilePath(workshop.Spec.Source)
+	bodyBytes, err := fetcher.Fetch(devfilePath)
 	if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	httpRequest, err = http.NewRequest("POST", keycloakCheUserURL, bytes.NewBuffer(body))
-	if err != nil {
-		log.Error(err, "Failed http POST Request")
-	}
-	httpRequest.Header.Set("Authorization", "Bearer "+masterToken)
-	httpRequest.Header.Set("Content-Type", "application/json")
-
-	httpResponse, err = client.Do(httpRequest)
-	if err != nil {
-		return reconcile.Result{}, err
-	}
-	if httpResponse.StatusCode == http.StatusCreated {
-		log.Infof("Created %s in CodeReady Workspaces", username)
-	}
-
-	return reconcile.Result{}, nil
-}
-
-// Get user token
-func getUserToken(workshop *workshopv1.Workshop, username string, codeflavor string, namespace string, appsHostnameSuffix string) (string, reconcile.Result, error) {
-
-	var (
-		openshiftUserPassword = workshop.Spec.User.Password
-		err                   error
-		httpResponse          *http.Response
-		httpRequest           *http.Request
-		keycloakCheTokenURL   = "https://keycloak-" + namespace + "." + appsHostnameSuffix + "/auth/realms/" + codeflavor + "/protocol/openid-connect/token"
-
-		userToken util.Token
-		client    = &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-			},
-			// Do not follow Redirect
-			CheckRedirect: func(req *http.Request, via []*http.Request) error {
-				return http.ErrUseLastResponse
-			},
-		}
-	)
-
-	// Get User Access Token
-	data := url.Values{}
-	data.Set("username", username)
-	data.Set("password", openshiftUserPassword)
-	data.Set("client_id", codeflavor+"-public")
-	data.Set("grant_type", "password")
-
-	httpRequest, err = http.NewRequest("POST", keycloakCheTokenURL, strings.NewReader(data.Encode()))
-	if err != nil {
-		log.Error(err, "Failed http POST Request")
-	}
-	httpRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	httpResponse, err = client.Do(httpRequest)
-	if err != nil {
-		log.Errorf("Error to get the user access  token from %s keycloak (%v)", codeflavor, err)
+		log.Errorf("Error when getting Devfile %s: %v", devfilePath, err)
 		return "", reconcile.Result{}, err
 	}
 
-	defer httpResponse.Body.Close()
-
-	if httpResponse.StatusCode == http.StatusOK {
-		if err := json.NewDecoder(httpResponse.Body).Decode(&userToken); err != nil {
-			log.Errorf("Error to get the user access  token from %s keycloak (%v)", codeflavor, err)
-			return "", reconcile.Result{}, err
-		}
-	} else {
-		log.Errorf("Error to get the user access token from %s keycloak (%d)", codeflavor, httpResponse.StatusCode)
+	bodyJSON, err := yaml.YAMLToJSON(bodyBytes)
+	if err != nil {
+		log.Errorf("Error to converting %s to JSON", devfilePath)
 		return "", reconcile.Result{}, err
 	}
 
-	return userToken.AccessToken, reconcile.Result{}, nil
+	return string(bodyJSON), reconcile.Result{}, nil
 }
 
 // Get oauthUserToken
 func getOAuthUserToken(workshop *workshopv1.Workshop, username string,
-	codeflavor string, namespace string, appsHostnameSuffix string) (string, reconcile.Result, error) {
+	codeflavor string, namespace string, appsHostnameSuffix string, tlsConfig *tls.Config) (string, reconcile.Result, error) {
 	var (
 		openshiftUserPassword = workshop.Spec.User.Password
 		err                   error
@@ -353,7 +274,7 @@ func getOAuthUserToken(workshop *workshopv1.Workshop, username string,
 		userToken util.Token
 		client    = &http.Client{
 			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+				TLSClientConfig: tlsConfig,
 			},
 			// Do not follow Redirect
 			CheckRedirect: func(req *http.Request, via []*http.Request) error {
@@ -421,143 +342,117 @@ func getOAuthUserToken(workshop *workshopv1.Workshop, username string,
 	return userToken.AccessToken, reconcile.Result{}, nil
 }
 
-// Get KeyCloak Admin Token
-func getKeycloakAdminToken(workshop *workshopv1.Workshop, namespace string, appsHostnameSuffix string) (string, reconcile.Result, error) {
-	var (
-		err                 error
-		httpResponse        *http.Response
-		httpRequest         *http.Request
-		keycloakCheTokenURL = "https://keycloak-" + namespace + "." + appsHostnameSuffix + "/auth/realms/master/protocol/openid-connect/token"
-
-		masterToken util.Token
-		client      = &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-			},
-			// Do not follow Redirect
-			CheckRedirect: func(req *http.Request, via []*http.Request) error {
-				return http.ErrUseLastResponse
-			},
-		}
-	)
+// newKeycloakClient returns a client of the Keycloak of CodeReady Workspaces
+func (r *WorkshopReconciler) newKeycloakClient(appsHostnameSuffix string, adminUsername string, adminPassword string) (*keycloak.Client, error) {
+	keycloakURL := "https://keycloak-" + CODEREADY_NAMESPACE_NAME + "." + appsHostnameSuffix + "/auth"
 
-	// GET TOKEN
-	httpRequest, err = http.NewRequest("POST", keycloakCheTokenURL, strings.NewReader("username=admin&password=admin&grant_type=password&client_id=admin-cli"))
+	tlsConfig, err := r.ingressTLSConfig()
 	if err != nil {
-		log.Error(err, "Failed http POST Request")
-	}
-	httpRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	httpResponse, err = client.Do(httpRequest)
-	if err != nil {
-		return "", reconcile.Result{}, err
-	}
-	defer httpResponse.Body.Close()
-	if httpResponse.StatusCode == http.StatusOK {
-		if err := json.NewDecoder(httpResponse.Body).Decode(&masterToken); err != nil {
-			return "", reconcile.Result{}, err
-		}
+		return nil, err
 	}
 
-	return masterToken.AccessToken, reconcile.Result{}, nil
+	return keycloak.NewClient(keycloakURL, adminUsername, adminPassword, tlsConfig), nil
 }
 
-// Update User Email
-func updateUserEmail(workshop *workshopv1.Workshop, username string,
-	codeflavor string, namespace string, appsHostnameSuffix string) (reconcile.Result, error) {
-	var (
-		err                    error
-		httpResponse           *http.Response
-		httpRequest            *http.Request
-		keycloakMasterTokenURL = "https://keycloak-" + namespace + "." + appsHostnameSuffix + "/auth/realms/master/protocol/openid-connect/token"
-		keycloakUserURL        = "https://keycloak-" + namespace + "." + appsHostnameSuffix + "/auth/admin/realms/" + codeflavor + "/users"
-		masterToken            util.Token
-		client                 = &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-			},
-			// Do not follow Redirect
-			CheckRedirect: func(req *http.Request, via []*http.Request) error {
-				return http.ErrUseLastResponse
-			},
-		}
-		cheUser []struct {
-			ID    string `json:"id"`
-			Email string `json:"email"`
-		}
-	)
+// ingressTLSConfig returns the TLS configuration of the clients of the routes, trusting the CA of the ingress of the
+// cluster when it is published, and only the system pool otherwise
+func (r *WorkshopReconciler) ingressTLSConfig() (*tls.Config, error) {
+	rootCAs, err := r.ingressRootCAs()
+	if errors.IsNotFound(err) {
+		log.Warnf("%s ConfigMap not found, the certificates of the routes must be signed by a system CA", INGRESS_CA_CONFIGMAP_NAME)
+		rootCAs = nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &tls.Config{RootCAs: rootCAs}, nil
+}
 
-	// Get Keycloak Admin Token
-	httpRequest, err = http.NewRequest("POST", keycloakMasterTokenURL, strings.NewReader("username=admin&password=admin&grant_type=password&client_id=admin-cli"))
-	if err != nil {
-		log.Error(err, "Failed http POST Request")
+// ingressRootCAs returns the system certificate pool with the CA signing the certificates of the routes
+func (r *WorkshopReconciler) ingressRootCAs() (*x509.CertPool, error) {
+	ingressCAFound := &corev1.ConfigMap{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: INGRESS_CA_CONFIGMAP_NAME, Namespace: INGRESS_CA_CONFIGMAP_NAMESPACE}, ingressCAFound); err != nil {
+		return nil, err
 	}
-	httpRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	httpResponse, err = client.Do(httpRequest)
+	rootCAs, err := x509.SystemCertPool()
 	if err != nil {
-		log.Errorf("Error when getting the master token from %s keycloak (%v)", codeflavor, err)
-		return reconcile.Result{}, err
-	}
-	defer httpResponse.Body.Close()
-	if httpResponse.StatusCode == http.StatusOK {
-		if err := json.NewDecoder(httpResponse.Body).Decode(&masterToken); err != nil {
-			log.Errorf("Error when reading the master token: %v", err)
-			return reconcile.Result{}, err
-		}
-	} else {
-		log.Errorf("Error when getting the master token from %s keycloak (%d)", codeflavor, httpResponse.StatusCode)
-		return reconcile.Result{}, err
+		rootCAs = x509.NewCertPool()
 	}
+	rootCAs.AppendCertsFromPEM([]byte(ingressCAFound.Data[INGRESS_CA_CONFIGMAP_KEY]))
+	return rootCAs, nil
+}
 
-	// GET USER
-	httpRequest, err = http.NewRequest("GET", keycloakUserURL+"?username="+username, nil)
-	if err != nil {
-		log.Error(err, "Failed http GET Request")
+// getCodeReadyAdminCredentials returns the credentials of the Keycloak admin, from the Secret when set or from a
+// Secret with a generated password. The generated Secret takes the password of an existing CheCluster, Keycloak
+// keeping the admin it was installed with.
+func (r *WorkshopReconciler) getCodeReadyAdminCredentials(workshop *workshopv1.Workshop) (string, string, error) {
+	secretName := workshop.Spec.Infrastructure.CodeReadyWorkspace.Server.IdentityProviderAdminSecretName
+	if secretName == "" {
+		secretName = CODEREADY_ADMIN_SECRET_NAME
 	}
-	httpRequest.Header.Set("Authorization", "Bearer "+masterToken.AccessToken)
 
-	httpResponse, err = client.Do(httpRequest)
-	if err != nil {
-		log.Errorf("Error when getting %s user: %v", username, err)
-		return reconcile.Result{}, err
-	}
+	adminSecretFound := &corev1.Secret{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: workshop.Namespace}, adminSecretFound); err != nil && errors.IsNotFound(err) && secretName == CODEREADY_ADMIN_SECRET_NAME {
+		adminUsername := codeready.DefaultAdminUsername
+		adminPassword, err := util.GeneratePassword(20)
+		if err != nil {
+			return "", "", err
+		}
 
-	defer httpResponse.Body.Close()
-	if httpResponse.StatusCode == http.StatusOK {
-		if err := json.NewDecoder(httpResponse.Body).Decode(&cheUser); err != nil {
-			log.Errorf("Error to get the user info (%v)", err)
-			return reconcile.Result{}, err
+		customResourceFound := &che.CheCluster{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: CHE_CUSTOM_RESOURCE_NAME, Namespace: CODEREADY_NAMESPACE_NAME}, customResourceFound); err == nil &&
+			customResourceFound.Spec.Auth.IdentityProviderPassword != "" {
+			adminUsername = customResourceFound.Spec.Auth.IdentityProviderAdminUserName
+			adminPassword = customResourceFound.Spec.Auth.IdentityProviderPassword
+		} else if err != nil && !errors.IsNotFound(err) {
+			return "", "", err
 		}
 
-		if cheUser[0].Email == "" {
-			httpRequest, err = http.NewRequest("PUT", keycloakUserURL+"/"+cheUser[0].ID,
-				strings.NewReader(`{"email":"`+username+`@none.com"}`))
-			if err != nil {
-				log.Error(err, "Failed http PUT Request")
-			}
-			httpRequest.Header.Set("Content-Type", "application/json")
-			httpRequest.Header.Set("Authorization", "Bearer "+masterToken.AccessToken)
+		adminSecret := kubernetes.NewStringDataSecret(workshop, r.Scheme, CODEREADY_ADMIN_SECRET_NAME, workshop.Namespace, codeReadyLabels,
+			map[string]string{"username": adminUsername, "password": adminPassword})
+		if err := r.Create(context.TODO(), adminSecret); err != nil {
+			return "", "", err
+		}
+		log.Infof("Created %s Secret", adminSecret.Name)
+		return adminUsername, adminPassword, nil
+	} else if err != nil {
+		log.Errorf("Failed to find %s secret", secretName)
+		return "", "", err
+	}
+	return string(adminSecretFound.Data["username"]), string(adminSecretFound.Data["password"]), nil
+}
 
-			// remove httpResponse because it is unused
-			_, err = client.Do(httpRequest)
+// getCodeReadyUserToken returns an access token of the user, resetting its password when it was changed in the
+// Workshop since the user was imported
+func getCodeReadyUserToken(keycloakClient *keycloak.Client, username string, userID string, password string) (string, error) {
+	token, err := keycloakClient.UserToken(CHE_CODE_FLAVOR_NAME, CHE_CODE_FLAVOR_NAME+"-public", username, password)
+	if !keycloak.IsUnauthorized(err) || userID == "" {
+		return token, err
+	}
 
-			if err != nil {
-				log.Errorf("Error when update email address for %s: %v", username, err)
-				return reconcile.Result{}, err
-			}
-		}
-	} else {
-		log.Errorf("Error when getting %s user: %v", username, httpResponse.StatusCode)
-		return reconcile.Result{}, err
+	if err := keycloakClient.ResetPassword(CHE_CODE_FLAVOR_NAME, userID, password); err != nil {
+		return "", err
 	}
+	log.Infof("Reset the password of %s in CodeReady Workspaces", username)
+	return keycloakClient.UserToken(CHE_CODE_FLAVOR_NAME, CHE_CODE_FLAVOR_NAME+"-public", username, password)
+}
 
-	//Success
-	return reconcile.Result{}, nil
+// updateCodeReadyUserEmail sets the email of a user created from OpenShift, required by CodeReady Workspaces
+func updateCodeReadyUserEmail(keycloakClient *keycloak.Client, username string) error {
+	user, err := keycloakClient.GetUser(CHE_CODE_FLAVOR_NAME, username)
+	if err != nil {
+		return err
+	}
+	if user.Email != "" {
+		return nil
+	}
+	user.Email = username + "@none.com"
+	return keycloakClient.UpdateUser(CHE_CODE_FLAVOR_NAME, *user)
 }
 
 // Initialize workspace
 func initWorkspace(workshop *workshopv1.Workshop, username string,
 	codeflavor string, namespace string, userAccessToken string, devfile string,
-	appsHostnameSuffix string) (reconcile.Result, error) {
+	appsHostnameSuffix string, tlsConfig *tls.Config) (reconcile.Result, error) {
 
 	var (
 		err                 error
@@ -566,7 +461,7 @@ func initWorkspace(workshop *workshopv1.Workshop, username string,
 		devfileWorkspaceURL = "https://" + codeflavor + "-" + namespace + "." + appsHostnameSuffix + "/api/workspace/devfile?start-after-create=true&namespace=" + username
 		client              = &http.Client{
 			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+				TLSClientConfig: tlsConfig,
 			},
 			// Do not follow Redirect
 			CheckRedirect: func(req *http.Request, via []*http.Request) error {
@@ -599,15 +494,20 @@ func (r *WorkshopReconciler) deleteCodeReadyWorkspace(workshop *workshopv1.Works
 	channel := workshop.Spec.Infrastructure.CodeReadyWorkspace.OperatorHub.Channel
 	clusterServiceVersion := workshop.Spec.Infrastructure.CodeReadyWorkspace.OperatorHub.ClusterServiceVersion
 
+	workspaceNamespace := workshop.Spec.Infrastructure.CodeReadyWorkspace.Server.WorkspaceNamespace
+	if workspaceNamespace == "" {
+		workspaceNamespace = codeready.DefaultWorkspaceNamespace
+	}
+
 	if !workshop.Spec.Infrastructure.CodeReadyWorkspace.OpenshiftOAuth {
 
 		for id := 1; id <= users; id++ {
 			username := fmt.Sprintf("user%d", id)
 
-			userWorkspacesNamespaceName := username + "-" + "workspace"
+			userWorkspacesNamespaceName := strings.ReplaceAll(workspaceNamespace, "<username>", username)
 			userWorkspacesNamespace := kubernetes.NewNamespace(workshop, r.Scheme, userWorkspacesNamespaceName)
 			// Delete Project
-			if err := r.Delete(context.TODO(), userWorkspacesNamespace); err != nil {
+			if err := r.Delete(context.TODO(), userWorkspacesNamespace); err != nil && !errors.IsNotFound(err) {
 				log.Errorf("Failed to Delete %s Namespace", userWorkspacesNamespace.Name)
 
 				return reconcile.Result{}, err
@@ -618,23 +518,24 @@ func (r *WorkshopReconciler) deleteCodeReadyWorkspace(workshop *workshopv1.Works
 
 		cheClusterRole := kubernetes.NewClusterRole(workshop, r.Scheme, CHE_CLUSTER_ROLE_NAME, CODEREADY_NAMESPACE_NAME, codeReadyLabels, kubernetes.CheRules())
 		// Delete che Cluster Role
-		if err := r.Delete(context.TODO(), cheClusterRole); err != nil {
+		if err := r.Delete(context.TODO(), cheClusterRole); err != nil && !errors.IsNotFound(err) {
 			return reconcile.Result{}, err
 		}
 		log.Infof("Deleted %s Cluster Role ", cheClusterRole.Name)
 
 		cheClusterRoleBinding := kubernetes.NewClusterRoleBindingSA(workshop, r.Scheme, CHE_CLUSTER_ROLE_BINDING_NAME, CODEREADY_NAMESPACE_NAME, codeReadyLabels, CHE_SERVICEACCOUNT_NAME, cheClusterRole.Name, KIND_CLUSTER_ROLE)
 		// Delete che Cluster RoleBinding
-		if err := r.Delete(context.TODO(), cheClusterRoleBinding); err != nil {
+		if err := r.Delete(context.TODO(), cheClusterRoleBinding); err != nil && !errors.IsNotFound(err) {
 			return reconcile.Result{}, err
 		}
 		log.Infof("Deleted %s Cluster RoleBinding ", cheClusterRoleBinding.Name)
 
 	}
 
-	codeReadyWorkspacesCustomResource := codeready.NewCustomResource(workshop, r.Scheme, CHE_CUSTOM_RESOURCE_NAME, CODEREADY_NAMESPACE_NAME)
+	codeReadyWorkspacesCustomResource := codeready.NewCustomResource(workshop, r.Scheme, CHE_CUSTOM_RESOURCE_NAME, CODEREADY_NAMESPACE_NAME,
+		codeready.DefaultAdminUsername, "")
 	// Delete codeReadyWorkspaces CustomResource
-	if err := r.Delete(context.TODO(), codeReadyWorkspacesCustomResource); err != nil {
+	if err := r.Delete(context.TODO(), codeReadyWorkspacesCustomResource); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s  CustomResource", codeReadyWorkspacesCustomResource.Name)
@@ -642,21 +543,21 @@ func (r *WorkshopReconciler) deleteCodeReadyWorkspace(workshop *workshopv1.Works
 	codeReadyWorkspacesSubscription := kubernetes.NewRedHatSubscription(workshop, r.Scheme, CODEREADY_SUBSCRIPTION_NAME, CODEREADY_NAMESPACE_NAME,
 		CODEREADY_SUBSCRIPTION_PACKAGE_NAME, channel, clusterServiceVersion)
 	// Delete Subscription
-	if err := r.Delete(context.TODO(), codeReadyWorkspacesSubscription); err != nil {
+	if err := r.Delete(context.TODO(), codeReadyWorkspacesSubscription); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s Subscription", codeReadyWorkspacesSubscription.Name)
 
 	codeReadyWorkspacesOperatorGroup := kubernetes.NewOperatorGroup(workshop, r.Scheme, CODEREADY_OPERATORGROUP_NAME, CODEREADY_NAMESPACE_NAME)
 	// Delete OperatorGroup
-	if err := r.Delete(context.TODO(), codeReadyWorkspacesOperatorGroup); err != nil {
+	if err := r.Delete(context.TODO(), codeReadyWorkspacesOperatorGroup); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s OperatorGroup", codeReadyWorkspacesOperatorGroup.Name)
 
 	codeReadyWorkspacesNamespace := kubernetes.NewNamespace(workshop, r.Scheme, CODEREADY_NAMESPACE_NAME)
 	// Delete Project
-	if err := r.Delete(context.TODO(), codeReadyWorkspacesNamespace); err != nil {
+	if err := r.Delete(context.TODO(), codeReadyWorkspacesNamespace); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s Namespace", codeReadyWorkspacesNamespace.Name)
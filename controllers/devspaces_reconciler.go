@@ -0,0 +1,323 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/prometheus/common/log"
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/devspaces"
+	"github.com/stakater/workshop-operator/common/kubernetes"
+	"github.com/stakater/workshop-operator/common/source"
+	"github.com/stakater/workshop-operator/common/util"
+	rbac "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var devSpacesLabels = map[string]string{
+	"app.kubernetes.io/part-of": "devspaces",
+}
+
+const (
+	DEVSPACES_NAMESPACE_NAME                 = "openshift-devspaces"
+	DEVSPACES_SUBSCRIPTION_NAMESPACE_NAME    = "openshift-operators"
+	DEVSPACES_SUBSCRIPTION_NAME              = "devspaces"
+	DEVSPACES_SUBSCRIPTION_PACKAGE_NAME      = "devspaces"
+	DEVSPACES_OPERATOR_DEPLOYMENT_NAME       = "devspaces-operator"
+	DEVWORKSPACE_SUBSCRIPTION_NAME           = "devworkspace-operator"
+	DEVWORKSPACE_SUBSCRIPTION_PACKAGE_NAME   = "devworkspace-operator"
+	DEVWORKSPACE_OPERATOR_DEPLOYMENT_NAME    = "devworkspace-controller-manager"
+	DEVSPACES_CUSTOM_RESOURCE_NAME           = "devspaces"
+	DEVSPACES_CHE_PHASE_ACTIVE               = "Active"
+	DEVSPACES_WORKSPACE_NAME                 = "workshop"
+	DEVSPACES_USER_ROLE_BINDING_NAME         = "edit"
+	DEVSPACES_USERNAME_ANNOTATION            = "che.eclipse.org/username"
+	DEVSPACES_WORKSPACES_NAMESPACE_COMPONENT = "workspaces-namespace"
+)
+
+// Reconciling DevSpaces
+func (r *WorkshopReconciler) reconcileDevSpaces(workshop *workshopv1.Workshop, users int,
+	appsHostnameSuffix string) (reconcile.Result, error) {
+
+	enabled := workshop.Spec.Infrastructure.DevSpaces.Enabled
+
+	if enabled {
+		if result, err := r.addDevSpaces(workshop, users, appsHostnameSuffix); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+func (r *WorkshopReconciler) addDevSpaces(workshop *workshopv1.Workshop, users int,
+	appsHostnameSuffix string) (reconcile.Result, error) {
+
+	channel := workshop.Spec.Infrastructure.DevSpaces.OperatorHub.Channel
+	clusterServiceVersion := workshop.Spec.Infrastructure.DevSpaces.OperatorHub.ClusterServiceVersion
+	flavor := devspaces.FlavorName(workshop)
+
+	subscriptionName, packageName, deploymentName := DEVSPACES_SUBSCRIPTION_NAME, DEVSPACES_SUBSCRIPTION_PACKAGE_NAME, DEVSPACES_OPERATOR_DEPLOYMENT_NAME
+	if flavor == devspaces.FlavorDevWorkspace {
+		subscriptionName, packageName, deploymentName = DEVWORKSPACE_SUBSCRIPTION_NAME, DEVWORKSPACE_SUBSCRIPTION_PACKAGE_NAME, DEVWORKSPACE_OPERATOR_DEPLOYMENT_NAME
+	} else if flavor != devspaces.FlavorDevSpaces {
+		return reconcile.Result{}, fmt.Errorf("unknown Dev Spaces flavor %s", flavor)
+	}
+
+	// Create Subscription
+	subscription := kubernetes.NewRedHatSubscription(workshop, r.Scheme, subscriptionName, DEVSPACES_SUBSCRIPTION_NAMESPACE_NAME,
+		packageName, channel, clusterServiceVersion)
+	if err := r.Create(context.TODO(), subscription); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Subscription", subscription.Name)
+	}
+
+	// Approve the installation
+	if err := r.ApproveInstallPlan(clusterServiceVersion, subscriptionName, DEVSPACES_SUBSCRIPTION_NAMESPACE_NAME); err != nil {
+		log.Warnf("Waiting for Subscription to create InstallPlan for %s", subscriptionName)
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	// Wait for the Operator to be running
+	if !kubernetes.GetK8Client().GetDeploymentStatus(deploymentName, DEVSPACES_SUBSCRIPTION_NAMESPACE_NAME) {
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	if flavor == devspaces.FlavorDevSpaces {
+		if result, err := r.addDevSpacesCheCluster(workshop); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
+
+	devfile, err := r.getDevSpacesDevfile(workshop, appsHostnameSuffix)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	var (
+		userStatuses = []workshopv1.DevWorkspaceUserStatus{}
+		starting     = false
+	)
+	for id := 1; id <= users; id++ {
+		username := fmt.Sprintf("user%d", id)
+
+		status, err := r.addDevWorkspace(workshop, username, devfile)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if workshop.Spec.Infrastructure.DevSpaces.Started && status.Phase != devspaces.PhaseRunning && status.Phase != devspaces.PhaseFailed {
+			starting = true
+		}
+		userStatuses = append(userStatuses, status)
+	}
+
+	if !reflect.DeepEqual(workshop.Status.DevWorkspaces, userStatuses) {
+		if err := r.updateStatus(workshop, func(status *workshopv1.WorkshopStatus) {
+			status.DevWorkspaces = userStatuses
+		}); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Wait for the workspaces to be running, their progress not being watched
+	if starting {
+		return reconcile.Result{RequeueAfter: time.Second * 10}, nil
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// addDevSpacesCheCluster creates the CheCluster of Dev Spaces and waits for it to be active
+func (r *WorkshopReconciler) addDevSpacesCheCluster(workshop *workshopv1.Workshop) (reconcile.Result, error) {
+
+	// Create Project
+	namespace := kubernetes.NewNamespace(workshop, r.Scheme, DEVSPACES_NAMESPACE_NAME)
+	if err := r.Create(context.TODO(), namespace); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Project", namespace.Name)
+	}
+
+	// Create/Update Custom Resource
+	customResource := devspaces.NewCheCluster(workshop, r.Scheme, DEVSPACES_CUSTOM_RESOURCE_NAME, DEVSPACES_NAMESPACE_NAME, devSpacesLabels)
+	if err := r.Create(context.TODO(), customResource); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Custom Resource", customResource.Name)
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	customResourceFound := &devspaces.CheCluster{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: customResource.Name, Namespace: DEVSPACES_NAMESPACE_NAME}, customResourceFound); err != nil {
+		return reconcile.Result{}, err
+	}
+	if customResourceFound.Spec.DevEnvironments.DefaultNamespace.Template != customResource.Spec.DevEnvironments.DefaultNamespace.Template {
+		patch := client.MergeFrom(customResourceFound.DeepCopy())
+		customResourceFound.Spec.DevEnvironments.DefaultNamespace.Template = customResource.Spec.DevEnvironments.DefaultNamespace.Template
+		if err := r.Patch(context.TODO(), customResourceFound, patch); err != nil {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Updated %s Custom Resource", customResourceFound.Name)
+	}
+
+	// Wait for Dev Spaces to be active
+	if customResourceFound.Status.ChePhase != DEVSPACES_CHE_PHASE_ACTIVE {
+		log.Infof("Waiting for %s to be active", customResourceFound.Name)
+		return reconcile.Result{RequeueAfter: time.Second * 10}, nil
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// addDevWorkspace creates or updates the namespace and the DevWorkspace of the user and returns its state
+func (r *WorkshopReconciler) addDevWorkspace(workshop *workshopv1.Workshop, username string,
+	devfile []byte) (workshopv1.DevWorkspaceUserStatus, error) {
+
+	status := workshopv1.DevWorkspaceUserStatus{Username: username}
+	namespaceName := devspaces.WorkspaceNamespace(workshop, username)
+
+	// Create Project, labeled as the workspaces namespace of the user for Dev Spaces
+	namespace := kubernetes.NewNamespace(workshop, r.Scheme, namespaceName)
+	namespace.Labels = map[string]string{
+		"app.kubernetes.io/part-of":   "che.eclipse.org",
+		"app.kubernetes.io/component": DEVSPACES_WORKSPACES_NAMESPACE_COMPONENT,
+	}
+	namespace.Annotations = map[string]string{
+		DEVSPACES_USERNAME_ANNOTATION: username,
+	}
+	if err := r.Create(context.TODO(), namespace); err != nil && !errors.IsAlreadyExists(err) {
+		return status, err
+	} else if err == nil {
+		log.Infof("Created %s Project", namespace.Name)
+	}
+
+	// Create User Role Binding
+	users := []rbac.Subject{
+		{
+			Kind: rbac.UserKind,
+			Name: username,
+		},
+	}
+	userRoleBinding := kubernetes.NewRoleBindingUsers(workshop, r.Scheme, username+"-devspaces", namespaceName, devSpacesLabels,
+		users, DEVSPACES_USER_ROLE_BINDING_NAME, KIND_CLUSTER_ROLE)
+	if err := r.Create(context.TODO(), userRoleBinding); err != nil && !errors.IsAlreadyExists(err) {
+		return status, err
+	} else if err == nil {
+		log.Infof("Created %s Role Binding", userRoleBinding.Name)
+	}
+
+	// Create/Update DevWorkspace
+	devWorkspace, err := devspaces.NewDevWorkspace(workshop, r.Scheme, DEVSPACES_WORKSPACE_NAME, namespaceName, devSpacesLabels, devfile)
+	if err != nil {
+		return status, err
+	}
+	if err := r.Create(context.TODO(), devWorkspace); err != nil && !errors.IsAlreadyExists(err) {
+		return status, err
+	} else if err == nil {
+		log.Infof("Created %s DevWorkspace for %s", devWorkspace.Name, username)
+	}
+
+	devWorkspaceFound := &devspaces.DevWorkspace{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: devWorkspace.Name, Namespace: namespaceName}, devWorkspaceFound); err != nil {
+		return status, err
+	}
+	if devWorkspaceFound.Spec.Started != devWorkspace.Spec.Started ||
+		!sameTemplate(devWorkspaceFound.Spec.Template.Raw, devWorkspace.Spec.Template.Raw) {
+		patch := client.MergeFrom(devWorkspaceFound.DeepCopy())
+		devWorkspaceFound.Spec.Started = devWorkspace.Spec.Started
+		devWorkspaceFound.Spec.Template = devWorkspace.Spec.Template
+		if err := r.Patch(context.TODO(), devWorkspaceFound, patch); err != nil {
+			return status, err
+		}
+		log.Infof("Updated %s DevWorkspace for %s", devWorkspaceFound.Name, username)
+	}
+
+	status.Phase = devWorkspaceFound.Status.Phase
+	status.URL = devWorkspaceFound.Status.MainURL
+	status.Message = devWorkspaceFound.Status.Message
+	return status, nil
+}
+
+// getDevSpacesDevfile returns the devfile v2 of the workspaces from the workshop source
+func (r *WorkshopReconciler) getDevSpacesDevfile(workshop *workshopv1.Workshop, appsHostnameSuffix string) ([]byte, error) {
+
+	fetcher, err := r.newSourceFetcher(workshop, appsHostnameSuffix)
+	if err != nil {
+		log.Errorf("Error when reading the workshop source: %v", err)
+		return nil, err
+	}
+
+	devfilePath := source.DevfilePath(workshop.Spec.Source)
+	if workshop.Spec.Infrastructure.DevSpaces.DevfilePath != "" {
+		devfilePath = workshop.Spec.Infrastructure.DevSpaces.DevfilePath
+	}
+	devfile, err := fetcher.Fetch(devfilePath)
+	if err != nil {
+		log.Errorf("Error when getting Devfile %s: %v", devfilePath, err)
+		return nil, err
+	}
+	return devfile, nil
+}
+
+// sameTemplate returns true if the JSON documents are equal, whatever the order of their keys
+func sameTemplate(found []byte, expected []byte) bool {
+	var foundTemplate, expectedTemplate interface{}
+	if err := json.Unmarshal(found, &foundTemplate); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(expected, &expectedTemplate); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(foundTemplate, expectedTemplate)
+}
+
+// Delete DevSpaces
+func (r *WorkshopReconciler) deleteDevSpaces(workshop *workshopv1.Workshop, users int) (reconcile.Result, error) {
+
+	channel := workshop.Spec.Infrastructure.DevSpaces.OperatorHub.Channel
+	clusterServiceVersion := workshop.Spec.Infrastructure.DevSpaces.OperatorHub.ClusterServiceVersion
+
+	for id := 1; id <= users; id++ {
+		username := fmt.Sprintf("user%d", id)
+
+		userWorkspaceNamespace := kubernetes.NewNamespace(workshop, r.Scheme, devspaces.WorkspaceNamespace(workshop, username))
+		// Delete Project
+		if err := r.Delete(context.TODO(), userWorkspaceNamespace); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s Namespace", userWorkspaceNamespace.Name)
+	}
+
+	subscriptionName, packageName := DEVSPACES_SUBSCRIPTION_NAME, DEVSPACES_SUBSCRIPTION_PACKAGE_NAME
+	if devspaces.FlavorName(workshop) == devspaces.FlavorDevWorkspace {
+		subscriptionName, packageName = DEVWORKSPACE_SUBSCRIPTION_NAME, DEVWORKSPACE_SUBSCRIPTION_PACKAGE_NAME
+	} else {
+		customResource := devspaces.NewCheCluster(workshop, r.Scheme, DEVSPACES_CUSTOM_RESOURCE_NAME, DEVSPACES_NAMESPACE_NAME, devSpacesLabels)
+		// Delete Custom Resource
+		if err := r.Delete(context.TODO(), customResource); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s Custom Resource", customResource.Name)
+	}
+
+	subscription := kubernetes.NewRedHatSubscription(workshop, r.Scheme, subscriptionName, DEVSPACES_SUBSCRIPTION_NAMESPACE_NAME,
+		packageName, channel, clusterServiceVersion)
+	// Delete Subscription
+	if err := r.Delete(context.TODO(), subscription); err != nil && !errors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+	log.Infof("Deleted %s Subscription", subscription.Name)
+
+	//Success
+	return reconcile.Result{}, nil
+}
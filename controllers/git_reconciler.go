@@ -0,0 +1,220 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/common/log"
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/git"
+	"github.com/stakater/workshop-operator/common/gitea"
+	"github.com/stakater/workshop-operator/common/kubernetes"
+	"github.com/stakater/workshop-operator/common/portal"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	GITCREDENTIALSTOKENKEY = "token"
+)
+
+// Reconciling the users of an external Git provider, the users of the bundled Gitea are reconciled with it
+func (r *WorkshopReconciler) reconcileGit(workshop *workshopv1.Workshop, users int) (reconcile.Result, error) {
+
+	if git.ProviderName(workshop) == git.ProviderGitea {
+		return reconcile.Result{}, nil
+	}
+
+	token, err := r.getGitAdminToken(workshop)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	provider, err := git.NewExternalProvider(workshop, token)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return r.reconcileGitUsers(workshop, provider, users)
+}
+
+// getGitAdminToken returns the admin token of the external Git provider from its credentials secret
+func (r *WorkshopReconciler) getGitAdminToken(workshop *workshopv1.Workshop) (string, error) {
+	secretName := workshop.Spec.Infrastructure.Git.CredentialsSecretName
+	if secretName == "" {
+		return "", fmt.Errorf("the credentials secret of the %s provider is required", git.ProviderName(workshop))
+	}
+
+	credentialsSecretFound := &corev1.Secret{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: workshop.Namespace}, credentialsSecretFound); err != nil {
+		log.Errorf("Failed to find %s secret", secretName)
+		return "", err
+	}
+	return string(credentialsSecretFound.Data[GITCREDENTIALSTOKENKEY]), nil
+}
+
+// reconcileGitUsers creates or updates the workshop users, deletes the accounts it created above the user count
+// and reports the result for each user in the status
+func (r *WorkshopReconciler) reconcileGitUsers(workshop *workshopv1.Workshop, provider git.Provider, users int) (reconcile.Result, error) {
+
+	var (
+		openshiftUserPassword = workshop.Spec.User.Password
+		providerName          = git.ProviderName(workshop)
+		userStatuses          = []workshopv1.GitUserStatus{}
+		createdUsers          = map[string]bool{}
+		failed                = 0
+	)
+
+	for _, userStatus := range workshop.Status.GitUsers {
+		createdUsers[userStatus.Username] = userStatus.Created
+	}
+
+	for id := 1; id <= users; id++ {
+		username := fmt.Sprintf("user%d", id)
+		status, err := provider.EnsureUser(username, username+"@none.com", openshiftUserPassword)
+		if err == nil && status != git.UserUnchanged {
+			log.Infof("%s %s user in %s", status, username, providerName)
+		}
+
+		// Import the repositories for the user and wire them
+		cloneURLs := []string{}
+		if err == nil {
+			if cloneURLs, err = r.reconcileGitUserRepositories(workshop, provider, username); err != nil {
+				status = git.UserFailed
+			}
+		}
+
+		userStatus := newGitUserStatus(username, status, err)
+		userStatus.Created = createdUsers[username] || status == git.UserCreated
+		userStatus.CloneURLs = cloneURLs
+		userStatuses = append(userStatuses, userStatus)
+		if err != nil {
+			log.Errorf("Failed to reconcile %s user in %s: %s", username, providerName, err)
+			failed++
+		}
+	}
+
+	// Delete the accounts created above the user count, only when allowed on an external server as the
+	// server is shared
+	deleteUsers := providerName == git.ProviderGitea || workshop.Spec.Infrastructure.Git.DeleteUsers
+	for _, previousStatus := range workshop.Status.GitUsers {
+		var id int
+		username := previousStatus.Username
+		if _, err := fmt.Sscanf(username, "user%d", &id); err != nil || username != fmt.Sprintf("user%d", id) || id <= users {
+			continue
+		}
+		if !previousStatus.Created {
+			continue
+		}
+		if !deleteUsers {
+			userStatuses = append(userStatuses, previousStatus)
+			continue
+		}
+		if giteaProvider, ok := provider.(*git.GiteaProvider); ok && workshop.Spec.Infrastructure.Gitea.Organization.Enabled {
+			if _, err := giteaProvider.Client.EnsureTeamDeleted(giteaOrganizationName(workshop), username); err != nil {
+				log.Errorf("Failed to delete %s team in Gitea: %s", username, err)
+			}
+		}
+		status, err := provider.EnsureUserDeleted(username)
+		userStatus := newGitUserStatus(username, status, err)
+		if err != nil {
+			// Keep the account to retry its deletion
+			userStatus.Created = true
+			log.Errorf("Failed to delete %s user in %s: %s", username, providerName, err)
+			failed++
+		} else {
+			log.Infof("Deleted %s user in %s", username, providerName)
+		}
+		userStatuses = append(userStatuses, userStatus)
+	}
+
+	if err := r.updateStatus(workshop, func(status *workshopv1.WorkshopStatus) {
+		status.GitUsers = userStatuses
+	}); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if failed > 0 {
+		return reconcile.Result{}, fmt.Errorf("failed to reconcile %d %s users", failed, providerName)
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// reconcileGitUserRepositories imports the repositories for the user and registers their webhooks. On Gitea the
+// instructors get read access to them and the user is added to its team of the organization.
+func (r *WorkshopReconciler) reconcileGitUserRepositories(workshop *workshopv1.Workshop, provider git.Provider,
+	username string) ([]string, error) {
+
+	gitSpec := workshop.Spec.Infrastructure.Git
+	organization := workshop.Spec.Infrastructure.Gitea.Organization
+	giteaProvider, isGitea := provider.(*git.GiteaProvider)
+	cloneURLs := []string{}
+
+	for _, repository := range git.Repositories(workshop) {
+		cloneURL, err := provider.EnsureRepository(username, repository, gitSpec.Repositories.Private)
+		if err != nil {
+			return cloneURLs, fmt.Errorf("%s repository: %s", repository.Name, err)
+		}
+		cloneURLs = append(cloneURLs, cloneURL)
+
+		for _, webhook := range gitSpec.Webhooks {
+			hookURL := strings.ReplaceAll(webhook.URL, portal.UsernamePlaceholder, username)
+			if err := provider.EnsureWebhook(username, repository.Name, hookURL, webhook); err != nil {
+				return cloneURLs, fmt.Errorf("%s repository webhook %s: %s", repository.Name, hookURL, err)
+			}
+		}
+
+		if isGitea && organization.Enabled {
+			for _, instructor := range organization.Instructors {
+				if err := giteaProvider.Client.AddCollaborator(username, repository.Name, instructor, gitea.PermissionRead); err != nil {
+					return cloneURLs, fmt.Errorf("%s repository collaborator %s: %s", repository.Name, instructor, err)
+				}
+			}
+		}
+	}
+
+	if isGitea && organization.Enabled {
+		if _, err := giteaProvider.Client.EnsureTeam(giteaOrganizationName(workshop), username, "Team of "+username,
+			gitea.PermissionWrite, false, true, []string{username}); err != nil {
+			return cloneURLs, fmt.Errorf("%s team: %s", username, err)
+		}
+	}
+
+	return cloneURLs, nil
+}
+
+func newGitUserStatus(username string, status string, err error) workshopv1.GitUserStatus {
+	userStatus := workshopv1.GitUserStatus{Username: username, Status: status}
+	if err != nil {
+		userStatus.Message = err.Error()
+	}
+	return userStatus
+}
+
+// gitServerURL returns the URL of the Git server as seen by the users
+func gitServerURL(workshop *workshopv1.Workshop, appsHostnameSuffix string) string {
+	return git.ServerURL(workshop, fmt.Sprintf("https://%s-%s.%s", GITEADEPLOYMENTNAME, GITEANAMESPACENAME, appsHostnameSuffix))
+}
+
+// gitInternalURL returns the URL of the Git server as seen from the cluster, the SSO proxy being the only way to
+// reach the bundled Gitea when it trusts its user header
+func gitInternalURL(workshop *workshopv1.Workshop) string {
+	if workshop.Spec.Infrastructure.SSO.Enabled {
+		return git.ServerURL(workshop, fmt.Sprintf("http://%s.%s.svc:%d", GITEASSOPROXYNAME, GITEANAMESPACENAME, kubernetes.OAuthProxyPort))
+	}
+	return git.ServerURL(workshop, fmt.Sprintf("http://%s.%s.svc:%d", GITEADEPLOYMENTNAME, GITEANAMESPACENAME, gitea.HTTPPort))
+}
+
+// gitRepositoryURL returns the URL of a repository of the user on serverURL
+func gitRepositoryURL(workshop *workshopv1.Workshop, serverURL string, username string, name string) string {
+	return fmt.Sprintf("%s/%s/%s.git", strings.TrimSuffix(serverURL, "/"), git.RepositoryOwner(workshop, username), git.RepositoryName(workshop, username, name))
+}
+
+// gitRepositoriesPattern returns the URL pattern of the repositories of the user as seen from the cluster
+func gitRepositoriesPattern(workshop *workshopv1.Workshop, username string) string {
+	return fmt.Sprintf("%s/%s/%s", gitInternalURL(workshop), git.RepositoryOwner(workshop, username), git.RepositoryName(workshop, username, "*"))
+}
@@ -0,0 +1,407 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/prometheus/common/log"
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/gitea"
+	"github.com/stakater/workshop-operator/common/kubernetes"
+	"github.com/stakater/workshop-operator/common/util"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var giteaServerLabels = map[string]string{
+	"app":                       GITEADEPLOYMENTNAME,
+	"app.kubernetes.io/name":    GITEADEPLOYMENTNAME,
+	"app.kubernetes.io/part-of": "gitea",
+}
+
+var giteaPostgreSQLLabels = map[string]string{
+	"app":                       GITEAPOSTGRESQLNAME,
+	"app.kubernetes.io/name":    GITEAPOSTGRESQLNAME,
+	"app.kubernetes.io/part-of": "gitea",
+}
+
+const (
+	GITEAMODENATIVE               = "native"
+	GITEAPOSTGRESQLNAME           = "gitea-postgresql"
+	GITEASERVERSECRETSNAME        = "gitea-server-secrets"
+	GITEASERVERCONFIGSECRETNAME   = "gitea-server-config"
+	GITEASERVERDATACLAIMNAME      = "gitea-server-data"
+	GITEASSHSERVICENAME           = "gitea-ssh"
+	GITEASSOPROXYNAME             = "gitea-sso"
+	GITEADEFAULTSTORAGESIZE       = "4Gi"
+	GITEADEFAULTSSHPORT           = 2222
+	GITEADEFAULTIMAGENAME         = "quay.io/gpte-devops-automation/gitea"
+	GITEADEFAULTIMAGETAG          = "1.21.11"
+	GITEADEFAULTDATABASEIMAGENAME = "registry.access.redhat.com/rhel8/postgresql-12"
+	GITEADEFAULTDATABASEIMAGETAG  = "1-140"
+)
+
+// Add Gitea Server, deployed without the Ansible operator
+func (r *WorkshopReconciler) addGiteaServer(workshop *workshopv1.Workshop, namespace string, appsHostnameSuffix string) (reconcile.Result, error) {
+
+	serverSpec := workshop.Spec.Infrastructure.Gitea.Server
+
+	// Create Secrets, generated once
+	secrets := &corev1.Secret{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: GITEASERVERSECRETSNAME, Namespace: namespace}, secrets); err != nil && errors.IsNotFound(err) {
+		secretsData, err := newGiteaServerSecrets()
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		giteaSecrets := kubernetes.NewStringDataSecret(workshop, r.Scheme, GITEASERVERSECRETSNAME, namespace, giteaServerLabels, secretsData)
+		if err := r.Create(context.TODO(), giteaSecrets); err != nil {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Created %s Secret", giteaSecrets.Name)
+		return reconcile.Result{Requeue: true}, nil
+	} else if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Create Persistent Volume Claims
+	databaseClaim := r.newGiteaPersistentVolumeClaim(workshop, GITEAPOSTGRESQLNAME, namespace, giteaPostgreSQLLabels,
+		serverSpec.Database.StorageSize, serverSpec.Database.StorageClassName)
+	if result, err := r.addGiteaPersistentVolumeClaim(databaseClaim); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	dataClaim := r.newGiteaPersistentVolumeClaim(workshop, GITEASERVERDATACLAIMNAME, namespace, giteaServerLabels,
+		serverSpec.StorageSize, serverSpec.StorageClassName)
+	if result, err := r.addGiteaPersistentVolumeClaim(dataClaim); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	// Deploy/Update PostgreSQL
+	databaseImage := imageOrDefault(serverSpec.Database.Image, GITEADEFAULTDATABASEIMAGENAME, GITEADEFAULTDATABASEIMAGETAG)
+	databaseDeployment := gitea.NewPostgreSQLDeployment(workshop, r.Scheme, GITEAPOSTGRESQLNAME, namespace, giteaPostgreSQLLabels,
+		databaseImage, serverSpec.Database.Resources, GITEASERVERSECRETSNAME, databaseClaim.Name)
+	if result, err := r.createOrUpdateGiteaDeployment(databaseDeployment); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	databaseService := kubernetes.NewService(workshop, r.Scheme, GITEAPOSTGRESQLNAME, namespace, giteaPostgreSQLLabels,
+		[]string{"postgresql"}, []int32{gitea.PostgreSQLPort})
+	if err := r.Create(context.TODO(), databaseService); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Service", databaseService.Name)
+	}
+
+	// Create/Update app.ini
+	host := fmt.Sprintf("%s-%s.%s", GITEADEPLOYMENTNAME, namespace, appsHostnameSuffix)
+	sshPort := int32(0)
+	if serverSpec.SSH.Enabled {
+		sshPort = serverSpec.SSH.Port
+		if sshPort == 0 {
+			sshPort = GITEADEFAULTSSHPORT
+		}
+	}
+	trustedProxies := []string{}
+	if workshop.Spec.Infrastructure.SSO.Enabled {
+		cidrs, err := r.clusterNetworkCIDRs()
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		trustedProxies = cidrs
+	}
+	appIni := newGiteaAppIni(workshop, secrets, host, sshPort, trustedProxies).String()
+	configChecksum, err := kubernetes.Checksum(appIni)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	configData := map[string]string{"app.ini": appIni}
+	configSecret := kubernetes.NewStringDataSecret(workshop, r.Scheme, GITEASERVERCONFIGSECRETNAME, namespace, giteaServerLabels, configData)
+	if err := r.Create(context.TODO(), configSecret); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Secret", configSecret.Name)
+	} else if errors.IsAlreadyExists(err) {
+		configSecretFound := &corev1.Secret{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: configSecret.Name, Namespace: namespace}, configSecretFound); err != nil {
+			return reconcile.Result{}, err
+		} else if string(configSecretFound.Data["app.ini"]) != appIni {
+			configSecretFound.StringData = configData
+			if err := r.Update(context.TODO(), configSecretFound); err != nil {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Updated %s Secret", configSecretFound.Name)
+		}
+	}
+
+	// Deploy/Update Gitea
+	serverImage := imageOrDefault(serverSpec.Image, GITEADEFAULTIMAGENAME, GITEADEFAULTIMAGETAG)
+	serverDeployment := gitea.NewServerDeployment(workshop, r.Scheme, GITEADEPLOYMENTNAME, namespace, giteaServerLabels,
+		serverImage, serverSpec.Resources, configSecret.Name, configChecksum, dataClaim.Name, sshPort)
+	if result, err := r.createOrUpdateGiteaDeployment(serverDeployment); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	// Create Service
+	serverService := kubernetes.NewService(workshop, r.Scheme, GITEADEPLOYMENTNAME, namespace, giteaServerLabels,
+		[]string{"http"}, []int32{gitea.HTTPPort})
+	if err := r.Create(context.TODO(), serverService); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Service", serverService.Name)
+	}
+
+	// Deploy SSO Proxy, Git clients and the API keep authenticating with passwords and tokens
+	routeServiceName, routePort := serverService.Name, int32(gitea.HTTPPort)
+	if workshop.Spec.Infrastructure.SSO.Enabled {
+		upstream := fmt.Sprintf("http://%s.%s.svc:%d", serverService.Name, namespace, gitea.HTTPPort)
+		if result, err := r.addSSOProxy(workshop, GITEASSOPROXYNAME, namespace, upstream, host,
+			[]string{`^/api/`, `\.git(/|$)`, `/info/lfs`}, nil); util.IsRequeued(result, err) {
+			return result, err
+		}
+		routeServiceName, routePort = GITEASSOPROXYNAME, kubernetes.OAuthProxyPort
+
+		// Create/Update Network Policy, Gitea trusting the user header
+		openPorts := []int32{}
+		if sshPort > 0 {
+			openPorts = append(openPorts, sshPort)
+		}
+		if result, err := r.addSSOBackendNetworkPolicy(workshop, GITEASSOPROXYNAME, namespace, giteaServerLabels,
+			[]int32{gitea.HTTPPort}, nil, openPorts); util.IsRequeued(result, err) {
+			return result, err
+		}
+	} else if result, err := r.deleteSSOBackendNetworkPolicy(GITEASSOPROXYNAME, namespace); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	// Create/Update Route
+	serverRoute := kubernetes.NewSecuredRoute(workshop, r.Scheme, GITEADEPLOYMENTNAME, namespace, giteaServerLabels,
+		routeServiceName, routePort)
+	if err := r.Create(context.TODO(), serverRoute); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Route", serverRoute.Name)
+	} else if errors.IsAlreadyExists(err) {
+		serverRouteFound := &routev1.Route{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: serverRoute.Name, Namespace: namespace}, serverRouteFound); err != nil {
+			return reconcile.Result{}, err
+		} else if serverRouteFound.Spec.To.Name != routeServiceName {
+			serverRouteFound.Spec.To = serverRoute.Spec.To
+			serverRouteFound.Spec.Port = serverRoute.Spec.Port
+			if err := r.Update(context.TODO(), serverRouteFound); err != nil {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Updated %s Route", serverRouteFound.Name)
+		}
+	}
+
+	// Create/Update SSH Service
+	if serverSpec.SSH.Enabled {
+		sshService := kubernetes.NewService(workshop, r.Scheme, GITEASSHSERVICENAME, namespace, giteaServerLabels,
+			[]string{"ssh"}, []int32{sshPort})
+		sshService.Spec.Type = serverSpec.SSH.ServiceType
+		if err := r.Create(context.TODO(), sshService); err != nil && !errors.IsAlreadyExists(err) {
+			return reconcile.Result{}, err
+		} else if err == nil {
+			log.Infof("Created %s Service", sshService.Name)
+		} else if errors.IsAlreadyExists(err) {
+			sshServiceFound := &corev1.Service{}
+			if err := r.Get(context.TODO(), types.NamespacedName{Name: sshService.Name, Namespace: namespace}, sshServiceFound); err != nil {
+				return reconcile.Result{}, err
+			} else if (sshService.Spec.Type != "" && sshServiceFound.Spec.Type != sshService.Spec.Type) ||
+				sshServiceFound.Spec.Ports[0].Port != sshPort {
+				sshServiceFound.Spec.Type = sshService.Spec.Type
+				sshServiceFound.Spec.Ports = sshService.Spec.Ports
+				if err := r.Update(context.TODO(), sshServiceFound); err != nil {
+					return reconcile.Result{}, err
+				}
+				log.Infof("Updated %s Service", sshServiceFound.Name)
+			}
+		}
+	} else {
+		sshService := &corev1.Service{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: GITEASSHSERVICENAME, Namespace: namespace}, sshService); err == nil {
+			if err := r.Delete(context.TODO(), sshService); err != nil && !errors.IsNotFound(err) {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Deleted %s Service", sshService.Name)
+		}
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// createOrUpdateGiteaDeployment creates the deployment, or updates it when its image, resources or configuration changed
+func (r *WorkshopReconciler) createOrUpdateGiteaDeployment(dep *appsv1.Deployment) (reconcile.Result, error) {
+	if err := r.Create(context.TODO(), dep); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Deployment", dep.Name)
+	} else if errors.IsAlreadyExists(err) {
+		deploymentFound := &appsv1.Deployment{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: dep.Name, Namespace: dep.Namespace}, deploymentFound); err != nil {
+			return reconcile.Result{}, err
+		}
+		container := dep.Spec.Template.Spec.Containers[0]
+		containerFound := deploymentFound.Spec.Template.Spec.Containers[0]
+		if container.Image != containerFound.Image ||
+			!reflect.DeepEqual(container.Resources, containerFound.Resources) ||
+			!reflect.DeepEqual(container.Ports, containerFound.Ports) ||
+			!reflect.DeepEqual(dep.Spec.Template.Annotations, deploymentFound.Spec.Template.Annotations) {
+			deploymentFound.Spec = dep.Spec
+			if err := r.Update(context.TODO(), deploymentFound); err != nil {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Updated %s Deployment", dep.Name)
+		}
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// newGiteaServerSecrets generates the secrets of the server and the credentials of its database
+func newGiteaServerSecrets() (map[string]string, error) {
+	secrets := map[string]string{
+		"database-user": "gitea",
+		"database-name": "gitea",
+	}
+	for _, key := range []string{"secret-key", "internal-token", "jwt-secret", "lfs-jwt-secret"} {
+		secret, err := gitea.GenerateSecret()
+		if err != nil {
+			return nil, err
+		}
+		secrets[key] = secret
+	}
+	databasePassword, err := util.GeneratePassword(20)
+	if err != nil {
+		return nil, err
+	}
+	secrets["database-password"] = databasePassword
+	return secrets, nil
+}
+
+// newGiteaAppIni generates app.ini, then applies the settings of the spec over it
+func newGiteaAppIni(workshop *workshopv1.Workshop, secrets *corev1.Secret, host string, sshPort int32,
+	trustedProxies []string) gitea.AppIni {
+	appIni := gitea.AppIni{}
+	appIni.Set("", "APP_NAME", "Gitea")
+	appIni.Set("", "RUN_MODE", "prod")
+	appIni.Set("", "RUN_USER", "gitea")
+
+	appIni.Set("server", "ROOT_URL", "https://"+host+"/")
+	appIni.Set("server", "DOMAIN", host)
+	appIni.Set("server", "HTTP_PORT", strconv.Itoa(gitea.HTTPPort))
+	appIni.Set("server", "APP_DATA_PATH", gitea.DataDir)
+	appIni.Set("server", "LFS_START_SERVER", "true")
+	appIni.Set("server", "LFS_JWT_SECRET", string(secrets.Data["lfs-jwt-secret"]))
+	if sshPort > 0 {
+		appIni.Set("server", "DISABLE_SSH", "false")
+		appIni.Set("server", "START_SSH_SERVER", "true")
+		appIni.Set("server", "SSH_DOMAIN", host)
+		appIni.Set("server", "SSH_PORT", strconv.Itoa(int(sshPort)))
+		appIni.Set("server", "SSH_LISTEN_PORT", strconv.Itoa(int(sshPort)))
+	} else {
+		appIni.Set("server", "DISABLE_SSH", "true")
+	}
+
+	appIni.Set("database", "DB_TYPE", "postgres")
+	appIni.Set("database", "HOST", fmt.Sprintf("%s:%d", GITEAPOSTGRESQLNAME, gitea.PostgreSQLPort))
+	appIni.Set("database", "NAME", string(secrets.Data["database-name"]))
+	appIni.Set("database", "USER", string(secrets.Data["database-user"]))
+	appIni.Set("database", "PASSWD", string(secrets.Data["database-password"]))
+	appIni.Set("database", "SSL_MODE", "disable")
+
+	appIni.Set("repository", "ROOT", gitea.DataDir+"/repositories")
+	appIni.Set("lfs", "PATH", gitea.DataDir+"/lfs")
+	appIni.Set("log", "MODE", "console")
+	appIni.Set("log", "LEVEL", "Info")
+	appIni.Set("log", "ROOT_PATH", gitea.DataDir+"/log")
+
+	appIni.Set("security", "INSTALL_LOCK", "true")
+	appIni.Set("security", "SECRET_KEY", string(secrets.Data["secret-key"]))
+	appIni.Set("security", "INTERNAL_TOKEN", string(secrets.Data["internal-token"]))
+	appIni.Set("oauth2", "JWT_SECRET", string(secrets.Data["jwt-secret"]))
+
+	// Users are created by the operator
+	appIni.Set("service", "DISABLE_REGISTRATION", "true")
+
+	// Users are signed in by the SSO proxy, the only pod of the trusted pods network allowed to reach Gitea
+	if workshop.Spec.Infrastructure.SSO.Enabled {
+		appIni.Set("service", "ENABLE_REVERSE_PROXY_AUTHENTICATION", "true")
+		appIni.Set("security", "REVERSE_PROXY_AUTHENTICATION_USER", kubernetes.OAuthProxyUserHeader)
+		appIni.Set("security", "REVERSE_PROXY_LIMIT", "1")
+		appIni.Set("security", "REVERSE_PROXY_TRUSTED_PROXIES", strings.Join(trustedProxies, ","))
+	}
+
+	appIni.Merge(workshop.Spec.Infrastructure.Gitea.Server.Config)
+	return appIni
+}
+
+// newGiteaPersistentVolumeClaim returns a claim of the default size and class unless set
+func (r *WorkshopReconciler) newGiteaPersistentVolumeClaim(workshop *workshopv1.Workshop, name string, namespace string,
+	labels map[string]string, size string, storageClassName string) *corev1.PersistentVolumeClaim {
+	if size == "" {
+		size = GITEADEFAULTSTORAGESIZE
+	}
+	pvc := kubernetes.NewPersistentVolumeClaim(workshop, r.Scheme, name, namespace, labels, size)
+	if storageClassName != "" {
+		pvc.Spec.StorageClassName = &storageClassName
+	}
+	return pvc
+}
+
+// addGiteaPersistentVolumeClaim creates the claim, or expands it when its size was increased. Claims cannot shrink,
+// a smaller size is rejected.
+func (r *WorkshopReconciler) addGiteaPersistentVolumeClaim(pvc *corev1.PersistentVolumeClaim) (reconcile.Result, error) {
+
+	if err := r.Create(context.TODO(), pvc); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Persistent Volume Claim", pvc.Name)
+		return reconcile.Result{}, nil
+	}
+
+	pvcFound := &corev1.PersistentVolumeClaim{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: pvc.Name, Namespace: pvc.Namespace}, pvcFound); err != nil {
+		return reconcile.Result{}, err
+	}
+	size := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	sizeFound := pvcFound.Spec.Resources.Requests[corev1.ResourceStorage]
+	switch size.Cmp(sizeFound) {
+	case -1:
+		return reconcile.Result{}, fmt.Errorf("%s Persistent Volume Claim cannot shrink from %s to %s", pvc.Name,
+			sizeFound.String(), size.String())
+	case 1:
+		// Expanded by the storage class, if it allows volume expansion
+		patch := client.MergeFrom(pvcFound.DeepCopy())
+		pvcFound.Spec.Resources.Requests[corev1.ResourceStorage] = size
+		if err := r.Patch(context.TODO(), pvcFound, patch); err != nil {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Resized %s Persistent Volume Claim to %s", pvc.Name, size.String())
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// imageOrDefault returns the image of the spec, or the default one when its name is not set
+func imageOrDefault(image workshopv1.ImageSpec, defaultName string, defaultTag string) string {
+	if image.Name == "" {
+		return defaultName + ":" + defaultTag
+	}
+	if image.Tag == "" {
+		return image.Name + ":" + defaultTag
+	}
+	return image.Name + ":" + image.Tag
+}
@@ -2,21 +2,21 @@ package controllers
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
-	"net/http"
-	"net/url"
-	"strconv"
 	"strings"
 	"time"
 
-	routev1 "github.com/openshift/api/route/v1"
 	"github.com/prometheus/common/log"
 	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/git"
 	"github.com/stakater/workshop-operator/common/gitea"
 	"github.com/stakater/workshop-operator/common/kubernetes"
 	"github.com/stakater/workshop-operator/common/util"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -43,14 +43,20 @@ const (
 	GITEAROLEBINDINGNAME       = "gitea-operator"
 	GITEASERVICEACCOUNTNAME    = "gitea-operator"
 	GITEACLUSTERROLENAME       = "gitea-operator"
+	GITEAADMINSECRETNAME       = "gitea-admin"
+	GITEAADMINUSERNAME         = "workshop-admin"
+	GITEAADMINTOKENNAME        = "workshop-operator"
+	GITEABINARY                = gitea.Binary
+	GITEACONFIGFILE            = gitea.ConfigFile
+	GITEAINSTRUCTORSTEAMNAME   = "instructors"
 )
 
 // Reconciling Gitea
-func (r *WorkshopReconciler) reconcileGitea(workshop *workshopv1.Workshop, users int) (reconcile.Result, error) {
+func (r *WorkshopReconciler) reconcileGitea(workshop *workshopv1.Workshop, users int, appsHostnameSuffix string) (reconcile.Result, error) {
 	enabledGitea := workshop.Spec.Infrastructure.Gitea.Enabled
 
 	if enabledGitea {
-		if result, err := r.addGitea(workshop, users); util.IsRequeued(result, err) {
+		if result, err := r.addGitea(workshop, users, appsHostnameSuffix); util.IsRequeued(result, err) {
 			return result, err
 		}
 	}
@@ -60,10 +66,7 @@ func (r *WorkshopReconciler) reconcileGitea(workshop *workshopv1.Workshop, users
 }
 
 // Add Gitea
-func (r *WorkshopReconciler) addGitea(workshop *workshopv1.Workshop, users int) (reconcile.Result, error) {
-
-	imageName := workshop.Spec.Infrastructure.Gitea.Image.Name
-	imageTag := workshop.Spec.Infrastructure.Gitea.Image.Tag
+func (r *WorkshopReconciler) addGitea(workshop *workshopv1.Workshop, users int, appsHostnameSuffix string) (reconcile.Result, error) {
 
 	// Create Project
 	giteaNamespace := kubernetes.NewNamespace(workshop, r.Scheme, GITEANAMESPACENAME)
@@ -73,6 +76,60 @@ func (r *WorkshopReconciler) addGitea(workshop *workshopv1.Workshop, users int)
 		log.Infof("Created %s Project", giteaNamespace.Name)
 	}
 
+	if workshop.Spec.Infrastructure.Gitea.Mode == GITEAMODENATIVE {
+		if result, err := r.deleteGiteaOperatorServer(workshop, giteaNamespace.Name); util.IsRequeued(result, err) {
+			return result, err
+		}
+		if result, err := r.addGiteaServer(workshop, giteaNamespace.Name, appsHostnameSuffix); util.IsRequeued(result, err) {
+			return result, err
+		}
+	} else {
+		if workshop.Spec.Infrastructure.SSO.Enabled {
+			return reconcile.Result{}, fmt.Errorf("gitea: single sign-on requires spec.infrastructure.gitea.mode %s", GITEAMODENATIVE)
+		}
+		if result, err := r.addGiteaOperator(workshop, giteaNamespace.Name); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
+
+	// Wait for server to be running
+	if !kubernetes.GetK8Client().GetDeploymentStatus(GITEADEPLOYMENTNAME, giteaNamespace.Name) {
+		return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 1}, nil
+	}
+
+	// Get an admin client against the gitea-server service
+	giteaClient, result, err := r.getGiteaAdminClient(workshop, giteaNamespace.Name)
+	if util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	// Users live on an external Git provider
+	if git.ProviderName(workshop) != git.ProviderGitea {
+		return reconcile.Result{}, nil
+	}
+
+	// Create workshop organization in gitea
+	if workshop.Spec.Infrastructure.Gitea.Organization.Enabled {
+		if result, err := r.reconcileGiteaOrganization(workshop, giteaClient); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
+
+	// Create workshop users in gitea
+	if result, err := r.reconcileGitUsers(workshop, git.NewGiteaProvider(giteaClient), users); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// Add Gitea Ansible Operator and its Custom Resource
+func (r *WorkshopReconciler) addGiteaOperator(workshop *workshopv1.Workshop, namespace string) (reconcile.Result, error) {
+
+	imageName := workshop.Spec.Infrastructure.Gitea.Image.Name
+	imageTag := workshop.Spec.Infrastructure.Gitea.Image.Tag
+
 	// Create CRD
 	giteaCustomResourceDefinition := kubernetes.NewCustomResourceDefinition(workshop, r.Scheme, GITEACRDNAME, GITEACRDGROUPNAME, GITEACRDKINDNAME, GITEACRDLISTKINDNAME, GITEACRDPLURALNAME, GITEACRDSINGULARNAME, GITEACRDVERSIONAME, nil, nil)
 	if err := r.Create(context.TODO(), giteaCustomResourceDefinition); err != nil && !errors.IsAlreadyExists(err) {
@@ -82,7 +139,7 @@ func (r *WorkshopReconciler) addGitea(workshop *workshopv1.Workshop, users int)
 	}
 
 	// Create Service Account
-	giteaServiceAccount := kubernetes.NewServiceAccount(workshop, r.Scheme, GITEASERVICEACCOUNTNAME, giteaNamespace.Name, gitealabels)
+	giteaServiceAccount := kubernetes.NewServiceAccount(workshop, r.Scheme, GITEASERVICEACCOUNTNAME, namespace, gitealabels)
 	if err := r.Create(context.TODO(), giteaServiceAccount); err != nil && !errors.IsAlreadyExists(err) {
 		return reconcile.Result{}, err
 	} else if err == nil {
@@ -90,7 +147,7 @@ func (r *WorkshopReconciler) addGitea(workshop *workshopv1.Workshop, users int)
 	}
 
 	// Create Cluster Role
-	giteaClusterRole := kubernetes.NewClusterRole(workshop, r.Scheme, GITEACLUSTERROLENAME, giteaNamespace.Name, gitealabels, kubernetes.GiteaRules())
+	giteaClusterRole := kubernetes.NewClusterRole(workshop, r.Scheme, GITEACLUSTERROLENAME, namespace, gitealabels, kubernetes.GiteaRules())
 	if err := r.Create(context.TODO(), giteaClusterRole); err != nil && !errors.IsAlreadyExists(err) {
 		return reconcile.Result{}, err
 	} else if err == nil {
@@ -98,14 +155,14 @@ func (r *WorkshopReconciler) addGitea(workshop *workshopv1.Workshop, users int)
 	}
 
 	// Create Cluster Role Binding
-	giteaClusterRoleBinding := kubernetes.NewClusterRoleBindingSA(workshop, r.Scheme, GITEAROLEBINDINGNAME, giteaNamespace.Name, gitealabels, GITEASERVICEACCOUNTNAME, GITEAROLEBINDINGNAME, CLUSTERROLEKINDNAME)
+	giteaClusterRoleBinding := kubernetes.NewClusterRoleBindingSA(workshop, r.Scheme, GITEAROLEBINDINGNAME, namespace, gitealabels, GITEASERVICEACCOUNTNAME, GITEAROLEBINDINGNAME, CLUSTERROLEKINDNAME)
 	if err := r.Create(context.TODO(), giteaClusterRoleBinding); err != nil && !errors.IsAlreadyExists(err) {
 		return reconcile.Result{}, err
 	} else if err == nil {
 		log.Infof("Created %s Cluster Role Binding", giteaClusterRoleBinding.Name)
 	}
 
-	giteaOperator := kubernetes.NewAnsibleOperatorDeployment(workshop, r.Scheme, GITEAANSIBLEDEPLOYMENTNAME, giteaNamespace.Name, gitealabels, imageName+":"+imageTag, GITEASERVICEACCOUNTNAME)
+	giteaOperator := kubernetes.NewAnsibleOperatorDeployment(workshop, r.Scheme, GITEAANSIBLEDEPLOYMENTNAME, namespace, gitealabels, imageName+":"+imageTag, GITEASERVICEACCOUNTNAME)
 
 	// Create Operator
 	if err := r.Create(context.TODO(), giteaOperator); err != nil && !errors.IsAlreadyExists(err) {
@@ -115,145 +172,279 @@ func (r *WorkshopReconciler) addGitea(workshop *workshopv1.Workshop, users int)
 	}
 
 	// Create Custom Resource
-	giteaCustomResource := gitea.NewCustomResource(workshop, r.Scheme, GITEACRNAME, giteaNamespace.Name, gitealabels)
+	giteaCustomResource := gitea.NewCustomResource(workshop, r.Scheme, GITEACRNAME, namespace, gitealabels)
 	if err := r.Create(context.TODO(), giteaCustomResource); err != nil && !errors.IsAlreadyExists(err) {
 		return reconcile.Result{}, err
 	} else if err == nil {
 		log.Infof("Created %s Custom Resource", giteaCustomResource.Name)
 	}
 
-	// Wait for server to be running
-	if !kubernetes.GetK8Client().GetDeploymentStatus(GITEADEPLOYMENTNAME, giteaNamespace.Name) {
-		return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 1}, nil
-	}
+	//Success
+	return reconcile.Result{}, nil
+}
 
-	// Extract app route suffix from openshift-console
-	giteaRouteFound := &routev1.Route{}
-	if err := r.Get(context.TODO(), types.NamespacedName{Name: GITEADEPLOYMENTNAME, Namespace: giteaNamespace.Name}, giteaRouteFound); err != nil {
-		log.Errorf("Failed to find %s route", "gitea-server")
-		return reconcile.Result{}, err
-	}
+// getGiteaAdminClient returns a Gitea client authenticated with the token of the operator admin,
+// bootstrapping the admin user and its token when needed
+func (r *WorkshopReconciler) getGiteaAdminClient(workshop *workshopv1.Workshop, namespace string) (*gitea.Client, reconcile.Result, error) {
 
-	giteaURL := "https://" + giteaRouteFound.Spec.Host
+	giteaServiceFound := &corev1.Service{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: GITEADEPLOYMENTNAME, Namespace: namespace}, giteaServiceFound); err != nil {
+		log.Errorf("Failed to find %s service", GITEADEPLOYMENTNAME)
+		return nil, reconcile.Result{}, err
+	}
+	if len(giteaServiceFound.Spec.Ports) == 0 {
+		return nil, reconcile.Result{}, fmt.Errorf("%s service has no port", GITEADEPLOYMENTNAME)
+	}
+	giteaURL := fmt.Sprintf("http://%s.%s.svc:%d", giteaServiceFound.Name, namespace, giteaServiceFound.Spec.Ports[0].Port)
 
-	// Create workshop users in gitea
-	for id := 1; id <= users; id++ {
-		username := fmt.Sprintf("user%d", id)
-		if result, err := createGitUser(workshop, username, giteaURL); err != nil {
-			return result, err
+	// Create Admin Secret with a generated password, unless provided
+	adminSecretName := workshop.Spec.Infrastructure.Gitea.Server.AdminSecretName
+	if adminSecretName == "" {
+		adminSecretName = GITEAADMINSECRETNAME
+	}
+	giteaAdminSecretFound := &corev1.Secret{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: adminSecretName, Namespace: namespace}, giteaAdminSecretFound); err != nil && errors.IsNotFound(err) && adminSecretName == GITEAADMINSECRETNAME {
+		adminPassword, err := util.GeneratePassword(20)
+		if err != nil {
+			return nil, reconcile.Result{}, err
+		}
+		giteaAdminSecret := kubernetes.NewStringDataSecret(workshop, r.Scheme, GITEAADMINSECRETNAME, namespace, gitealabels,
+			map[string]string{"username": GITEAADMINUSERNAME, "password": adminPassword})
+		if err := r.Create(context.TODO(), giteaAdminSecret); err != nil {
+			return nil, reconcile.Result{}, err
 		}
+		log.Infof("Created %s Secret", giteaAdminSecret.Name)
+		return nil, reconcile.Result{Requeue: true}, nil
+	} else if err != nil {
+		return nil, reconcile.Result{}, err
 	}
 
-	//Success
-	return reconcile.Result{}, nil
-}
-
-// Create GitUser
-func createGitUser(workshop *workshopv1.Workshop, username string, giteaURL string) (reconcile.Result, error) {
-
-	var (
-		openshiftUserPassword = workshop.Spec.User.Password
-		err                   error
-		httpResponse          *http.Response
-		httpRequest           *http.Request
-		requestURL            = giteaURL + "/user/sign_up"
-		body                  = url.Values{}
-		client                = &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-			},
-			// Do not follow Redirect
-			CheckRedirect: func(req *http.Request, via []*http.Request) error {
-				return http.ErrUseLastResponse
-			},
+	adminUsername := string(giteaAdminSecretFound.Data["username"])
+	adminPassword := string(giteaAdminSecretFound.Data["password"])
+	if adminUsername == "" || adminPassword == "" {
+		return nil, reconcile.Result{}, fmt.Errorf("the %s secret requires a username and a password", adminSecretName)
+	}
+	adminToken := string(giteaAdminSecretFound.Data["token"])
+
+	if adminToken != "" {
+		giteaClient := gitea.NewClient(giteaURL, adminToken)
+		if _, err := giteaClient.CurrentUser(); err == nil {
+			return giteaClient, reconcile.Result{}, nil
+		} else if !gitea.IsUnauthorized(err) {
+			return nil, reconcile.Result{}, err
 		}
-	)
+		log.Infof("Gitea admin token of %s is no longer valid", adminUsername)
+	}
 
-	body.Set("user_name", username)
-	body.Set("email", username+"@none.com")
-	body.Set("password", openshiftUserPassword)
-	body.Set("retype", openshiftUserPassword)
+	// Bootstrap the admin user from the gitea-server pod
+	podName, err := kubernetes.GetK8Client().GetDeploymentPod(GITEADEPLOYMENTNAME, namespace, "app")
+	if err != nil || podName == "" {
+		return nil, reconcile.Result{Requeue: true, RequeueAfter: time.Second * 5}, err
+	}
+	_, stderr, err := kubernetes.GetK8Client().ExecInPod(podName, namespace, []string{
+		GITEABINARY, "admin", "user", "create", "--config", GITEACONFIGFILE, "--admin",
+		"--username", adminUsername, "--password", adminPassword, "--email", adminUsername + "@none.com",
+		"--must-change-password=false",
+	})
+	if err != nil && strings.Contains(stderr, "already exists") {
+		_, stderr, err = kubernetes.GetK8Client().ExecInPod(podName, namespace, []string{
+			GITEABINARY, "admin", "user", "change-password", "--config", GITEACONFIGFILE,
+			"--username", adminUsername, "--password", adminPassword,
+		})
+	}
+	if err != nil {
+		log.Errorf("Failed to bootstrap %s Gitea admin: %s", adminUsername, stderr)
+		return nil, reconcile.Result{}, err
+	}
 
-	httpRequest, err = http.NewRequest("POST", requestURL, strings.NewReader(body.Encode()))
+	token, err := gitea.NewBasicAuthClient(giteaURL, adminUsername, adminPassword).
+		CreateAccessToken(fmt.Sprintf("%s-%d", GITEAADMINTOKENNAME, time.Now().Unix()))
 	if err != nil {
-		log.Error(err, "Failed http POST Request  ")
+		return nil, reconcile.Result{}, err
+	}
+
+	if giteaAdminSecretFound.Data == nil {
+		giteaAdminSecretFound.Data = map[string][]byte{}
 	}
-	httpRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	httpRequest.Header.Set("Accept", "application/json")
-	httpRequest.Header.Set("Content-Length", strconv.Itoa(len(body.Encode())))
+	giteaAdminSecretFound.Data["token"] = []byte(token.Sha1)
+	if err := r.Update(context.TODO(), giteaAdminSecretFound); err != nil {
+		return nil, reconcile.Result{}, err
+	}
+	log.Infof("Bootstrapped %s Gitea admin token", adminUsername)
+
+	return gitea.NewClient(giteaURL, token.Sha1), reconcile.Result{}, nil
+}
 
-	httpResponse, err = client.Do(httpRequest)
+// reconcileGiteaOrganization creates the organization of the workshop and its instructors team
+func (r *WorkshopReconciler) reconcileGiteaOrganization(workshop *workshopv1.Workshop, giteaClient *gitea.Client) (reconcile.Result, error) {
+	organization := workshop.Spec.Infrastructure.Gitea.Organization
+
+	// Create Instructors
+	for _, instructor := range organization.Instructors {
+		_, status, err := giteaClient.EnsureUser(gitea.CreateUserOption{
+			Username: instructor,
+			Email:    instructor + "@none.com",
+			Password: workshop.Spec.User.Password,
+		})
+		if err != nil {
+			return reconcile.Result{}, err
+		} else if status == gitea.UserCreated {
+			log.Infof("Created %s instructor in Gitea", instructor)
+		}
+	}
+
+	// Create Organization
+	org, err := giteaClient.EnsureOrg(gitea.CreateOrgOption{
+		UserName:   giteaOrganizationName(workshop),
+		FullName:   workshop.Name,
+		Visibility: "public",
+	})
 	if err != nil {
 		return reconcile.Result{}, err
 	}
-	if httpResponse.StatusCode == http.StatusCreated {
-		log.Infof("Created %s user in Gitea", username)
-	}
 
-	defer httpResponse.Body.Close()
+	// Create Instructors Team
+	if _, err := giteaClient.EnsureTeam(org.UserName, GITEAINSTRUCTORSTEAMNAME, "Instructors of the workshop",
+		gitea.PermissionRead, true, false, organization.Instructors); err != nil {
+		return reconcile.Result{}, err
+	}
 
 	//Success
 	return reconcile.Result{}, nil
 }
 
+// giteaOrganizationName returns the name of the organization of the workshop
+func giteaOrganizationName(workshop *workshopv1.Workshop) string {
+	if workshop.Spec.Infrastructure.Gitea.Organization.Name != "" {
+		return workshop.Spec.Infrastructure.Gitea.Organization.Name
+	}
+	return workshop.Name
+}
+
 // Delete Gitea
 func (r *WorkshopReconciler) deleteGitea(workshop *workshopv1.Workshop) (reconcile.Result, error) {
 
 	log.Info("Deleting gitea")
 
+	if workshop.Spec.Infrastructure.Gitea.Mode == GITEAMODENATIVE {
+		// Delete OAuth Client
+		if result, err := r.deleteSSOProxy(workshop, GITEASSOPROXYNAME, GITEANAMESPACENAME); util.IsRequeued(result, err) {
+			return result, err
+		}
+
+		giteaNamespace := kubernetes.NewNamespace(workshop, r.Scheme, GITEANAMESPACENAME)
+		// Delete Project, with everything the native mode deployed in it
+		if err := r.Delete(context.TODO(), giteaNamespace); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s gitea Project ", GITEANAMESPACENAME)
+		log.Info("Gitea deleted succesfully")
+
+		//Success
+		return reconcile.Result{}, nil
+	}
+
+	if result, err := r.deleteGiteaOperator(workshop); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	giteaNamespace := kubernetes.NewNamespace(workshop, r.Scheme, GITEANAMESPACENAME)
+	// Delete Project
+	if err := r.Delete(context.TODO(), giteaNamespace); err != nil && !errors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+	log.Infof("Deleted %s gitea Project ", GITEANAMESPACENAME)
+	log.Info("Gitea deleted succesfully")
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// Delete the Gitea server of the operator mode, deployed by the Ansible operator with another selector than the
+// native gitea-server Deployment, before switching to the native mode
+func (r *WorkshopReconciler) deleteGiteaOperatorServer(workshop *workshopv1.Workshop, namespace string) (reconcile.Result, error) {
+	deploymentFound := &appsv1.Deployment{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: GITEADEPLOYMENTNAME, Namespace: namespace}, deploymentFound); err != nil && errors.IsNotFound(err) {
+		return reconcile.Result{}, nil
+	} else if err != nil {
+		return reconcile.Result{}, err
+	} else if metav1.IsControlledBy(deploymentFound, workshop) {
+		return reconcile.Result{}, nil
+	}
+
+	log.Infof("Switching gitea to the %s mode", GITEAMODENATIVE)
+	if result, err := r.deleteGiteaOperator(workshop); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	// Delete the Deployment and Service of the Custom Resource without waiting for their garbage collection
+	if err := r.Delete(context.TODO(), deploymentFound); err != nil && !errors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+	log.Infof("Deleted %s Deployment", deploymentFound.Name)
+
+	serviceFound := &corev1.Service{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: GITEADEPLOYMENTNAME, Namespace: namespace}, serviceFound); err != nil && !errors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	} else if err == nil && !metav1.IsControlledBy(serviceFound, workshop) {
+		if err := r.Delete(context.TODO(), serviceFound); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s Service", serviceFound.Name)
+	}
+
+	// Wait for the Deployment to be gone
+	return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 5}, nil
+}
+
+// Delete the Gitea Ansible Operator and its Custom Resource
+func (r *WorkshopReconciler) deleteGiteaOperator(workshop *workshopv1.Workshop) (reconcile.Result, error) {
+
 	imageName := workshop.Spec.Infrastructure.Gitea.Image.Name
 	imageTag := workshop.Spec.Infrastructure.Gitea.Image.Tag
 
 	giteaCustomResource := gitea.NewCustomResource(workshop, r.Scheme, GITEACRNAME, GITEANAMESPACENAME, gitealabels)
 	// Delete Custom Resource
-	if err := r.Delete(context.TODO(), giteaCustomResource); err != nil {
+	if err := r.Delete(context.TODO(), giteaCustomResource); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s gitea Custom Resource", giteaCustomResource.Name)
 
 	giteaOperator := kubernetes.NewAnsibleOperatorDeployment(workshop, r.Scheme, GITEAANSIBLEDEPLOYMENTNAME, GITEANAMESPACENAME, gitealabels, imageName+":"+imageTag, GITEASERVICEACCOUNTNAME)
 	// Delete Operator
-	if err := r.Delete(context.TODO(), giteaOperator); err != nil {
+	if err := r.Delete(context.TODO(), giteaOperator); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s gitea Operator", giteaOperator.Name)
 
 	giteaClusterRoleBinding := kubernetes.NewClusterRoleBindingSA(workshop, r.Scheme, GITEAROLEBINDINGNAME, GITEANAMESPACENAME, gitealabels, GITEASERVICEACCOUNTNAME, GITEACLUSTERROLENAME, CLUSTERROLEKINDNAME)
 	// Delete Cluster Role Binding
-	if err := r.Delete(context.TODO(), giteaClusterRoleBinding); err != nil {
+	if err := r.Delete(context.TODO(), giteaClusterRoleBinding); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s gitea Cluster  Role Binding", giteaClusterRoleBinding.Name)
 
 	giteaClusterRole := kubernetes.NewClusterRole(workshop, r.Scheme, GITEACLUSTERROLENAME, GITEANAMESPACENAME, gitealabels, kubernetes.GiteaRules())
 	// Delete Cluster Role
-	if err := r.Delete(context.TODO(), giteaClusterRole); err != nil {
+	if err := r.Delete(context.TODO(), giteaClusterRole); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s gitea Cluster Role", giteaClusterRole.Name)
 
 	giteaServiceAccount := kubernetes.NewServiceAccount(workshop, r.Scheme, GITEASERVICEACCOUNTNAME, GITEANAMESPACENAME, gitealabels)
 	// Delete Service Account
-	if err := r.Delete(context.TODO(), giteaServiceAccount); err != nil {
+	if err := r.Delete(context.TODO(), giteaServiceAccount); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s gitea Service Account", giteaServiceAccount.Name)
 
 	giteaCustomResourceDefinition := kubernetes.NewCustomResourceDefinition(workshop, r.Scheme, GITEACRDNAME, GITEACRDGROUPNAME, GITEACRDKINDNAME, GITEACRDLISTKINDNAME, GITEACRDPLURALNAME, GITEACRDSINGULARNAME, GITEACRDVERSIONAME, nil, nil)
 	// Delete CRD
-	if err := r.Delete(context.TODO(), giteaCustomResourceDefinition); err != nil {
+	if err := r.Delete(context.TODO(), giteaCustomResourceDefinition); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s gitea Custom Resource Definition", giteaCustomResourceDefinition.Name)
 
-	giteaNamespace := kubernetes.NewNamespace(workshop, r.Scheme, GITEANAMESPACENAME)
-	// Delete Project
-	if err := r.Delete(context.TODO(), giteaNamespace); err != nil {
-		return reconcile.Result{}, err
-	}
-	log.Infof("Deleted %s gitea Project ", GITEANAMESPACENAME)
-	log.Info("Gitea deleted succesfully")
-
 	//Success
 	return reconcile.Result{}, nil
 }
@@ -0,0 +1,294 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	argocdoperatorv1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argocdv1 "github.com/argoproj/argo-cd/pkg/apis/application/v1alpha1"
+	"github.com/prometheus/common/log"
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/argocd"
+	"github.com/stakater/workshop-operator/common/kubernetes"
+	"github.com/stakater/workshop-operator/common/util"
+	corev1 "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	ARGOCD_MODE_PER_USER        = "perUser"
+	ARGOCD_CLUSTER_SECRET_NAME  = ARGOCD_CUSTOMRESOURCE_NAME + "-cluster"
+	ARGOCD_CONTROLLER_SA_SUFFIX = "-argocd-application-controller"
+)
+
+// isGitOpsPerUser returns true if every user gets its own Argo CD
+func isGitOpsPerUser(workshop *workshopv1.Workshop) bool {
+	return workshop.Spec.Infrastructure.GitOps.Mode == ARGOCD_MODE_PER_USER
+}
+
+// gitOpsNamespace returns the namespace of the Argo CD managing the staging project of a user
+func gitOpsNamespace(workshop *workshopv1.Workshop, projectName string) string {
+	if isGitOpsPerUser(workshop) {
+		return projectName
+	}
+	return ARGOCD_NAMESPACE_NAME
+}
+
+// gitOpsNamespaces returns the namespaces managed by the Argo CD of a user: its staging project and the ones of
+// its Applications
+func gitOpsNamespaces(workshop *workshopv1.Workshop, username string, projectName string) []string {
+	namespaces := []string{projectName}
+	for _, applicationSpec := range gitOpsApplications(workshop, username, projectName) {
+		if !util.StringInSlice(applicationSpec.Namespace, namespaces) {
+			namespaces = append(namespaces, applicationSpec.Namespace)
+		}
+	}
+	if !isGitOpsPerUser(workshop) {
+		for _, applicationSetSpec := range workshop.Spec.Infrastructure.GitOps.ApplicationSets {
+			namespace := gitOpsApplicationSetNamespace(applicationSetSpec, username, projectName)
+			if !util.StringInSlice(namespace, namespaces) {
+				namespaces = append(namespaces, namespace)
+			}
+		}
+	}
+	return namespaces
+}
+
+// Add an Argo CD in the staging project of every user, managing only the namespaces of the user
+func (r *WorkshopReconciler) addGitOpsPerUser(workshop *workshopv1.Workshop, users int) (reconcile.Result, error) {
+
+	labels := map[string]string{
+		"app.kubernetes.io/part-of": "argocd",
+	}
+
+	for id := 1; id <= users; id++ {
+		username := fmt.Sprintf("user%d", id)
+		projectName := fmt.Sprintf("%s%d", workshop.Spec.Infrastructure.Project.StagingName, id)
+
+		if result, err := r.addGitOpsUserInstance(workshop, username, projectName, labels); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
+
+	// Wait for the Argo CD Servers to be running, once all of them are created
+	for id := 1; id <= users; id++ {
+		username := fmt.Sprintf("user%d", id)
+		projectName := fmt.Sprintf("%s%d", workshop.Spec.Infrastructure.Project.StagingName, id)
+
+		if workshop.Spec.Infrastructure.SSO.Enabled && !kubernetes.GetK8Client().GetDeploymentStatus(ARGOCD_DEX_DEPLOYMENT_NAME, projectName) {
+			return reconcile.Result{Requeue: true}, nil
+		}
+		if !kubernetes.GetK8Client().GetDeploymentStatus(ARGOCD_DEPLOYMENT_NAME, projectName) {
+			return reconcile.Result{Requeue: true}, nil
+		}
+
+		namespaces := gitOpsNamespaces(workshop, username, projectName)
+		labels["app.kubernetes.io/name"] = "argocd-default-cluster-config"
+		if result, err := r.manageArgocdDefaultClusterConfigSecret(workshop, projectName, labels, strings.Join(namespaces, ",")); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
+
+	if len(workshop.Spec.Infrastructure.GitOps.ApplicationSets) > 0 {
+		log.Warnf("ApplicationSets are only supported by the shared Argo CD")
+	}
+
+	if result, err := r.addGitOpsRepositoryCredentials(workshop, users); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	if result, err := r.addGitOpsApplications(workshop, users); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// Add the Argo CD of a user, the user being its only admin and logging in as themselves
+func (r *WorkshopReconciler) addGitOpsUserInstance(workshop *workshopv1.Workshop, username string, projectName string,
+	labels map[string]string) (reconcile.Result, error) {
+
+	// Create the admin password, generated once for every instance and never shown to the users, the operator
+	// setting it in the argocd-secret of the instance
+	labels["app.kubernetes.io/name"] = "argocd-cluster"
+	clusterSecretFound := &corev1.Secret{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: ARGOCD_CLUSTER_SECRET_NAME, Namespace: projectName}, clusterSecretFound); err != nil && errors.IsNotFound(err) {
+		adminPassword, err := util.GeneratePassword(32)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		clusterSecret := kubernetes.NewStringDataSecret(workshop, r.Scheme, ARGOCD_CLUSTER_SECRET_NAME, projectName, labels,
+			map[string]string{"admin.password": adminPassword})
+		if err := r.Create(context.TODO(), clusterSecret); err != nil {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Created %s Secret in %s namespace", clusterSecret.Name, projectName)
+	} else if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Create/Update the local account of the user, logging in with the workshop password unless SSO is enabled
+	accounts := []string{}
+	if !workshop.Spec.Infrastructure.SSO.Enabled {
+		accounts = append(accounts, username)
+	}
+	if result, err := r.manageArgocdAccounts(workshop, projectName, labels, accounts); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	// Create/Update the ArgoCD, the user logging in through OpenShift when SSO is enabled
+	argocdPolicy := `g, ` + username + `, role:admin
+`
+	labels["app.kubernetes.io/name"] = "argocd-cr"
+	argoCDCustomResource := argocd.NewArgoCDCustomResource(workshop, r.Scheme, ARGOCD_CUSTOMRESOURCE_NAME, projectName, labels, argocdPolicy)
+	if err := r.Create(context.TODO(), argoCDCustomResource); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Custom Resource in %s namespace", argoCDCustomResource.Name, projectName)
+	} else if errors.IsAlreadyExists(err) {
+		customResourceFound := &argocdoperatorv1.ArgoCD{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: argoCDCustomResource.Name, Namespace: projectName}, customResourceFound); err != nil {
+			return reconcile.Result{}, err
+		} else if !reflect.DeepEqual(&argocdPolicy, customResourceFound.Spec.RBAC.Policy) ||
+			argoCDCustomResource.Spec.Dex.OpenShiftOAuth != customResourceFound.Spec.Dex.OpenShiftOAuth {
+			patch := client.MergeFrom(customResourceFound.DeepCopy())
+			customResourceFound.Spec.RBAC.Policy = &argocdPolicy
+			customResourceFound.Spec.Dex.OpenShiftOAuth = argoCDCustomResource.Spec.Dex.OpenShiftOAuth
+			if err := r.Patch(context.TODO(), customResourceFound, patch); err != nil {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Updated %s Custom Resource in %s namespace", customResourceFound.Name, projectName)
+		}
+	}
+
+	// Create/Update the AppProject of the Applications of the user
+	labels["app.kubernetes.io/name"] = "appproject-cr"
+	appProjectCustomResource := argocd.NewAppProjectCustomResource(workshop, r.Scheme, projectName, projectName, labels,
+		gitOpsSourceRepos(workshop, username, projectName))
+	appProjectCustomResource.Spec.Destinations = nil
+	for _, namespace := range gitOpsNamespaces(workshop, username, projectName) {
+		appProjectCustomResource.Spec.Destinations = append(appProjectCustomResource.Spec.Destinations, argocdv1.ApplicationDestination{
+			Namespace: namespace,
+			Server:    "https://kubernetes.default.svc",
+		})
+	}
+	if err := r.Create(context.TODO(), appProjectCustomResource); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Custom Resource in %s namespace", appProjectCustomResource.Name, projectName)
+	} else if errors.IsAlreadyExists(err) {
+		customResourceFound := &argocdv1.AppProject{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: appProjectCustomResource.Name, Namespace: projectName}, customResourceFound); err != nil {
+			return reconcile.Result{}, err
+		} else if !reflect.DeepEqual(appProjectCustomResource.Spec, customResourceFound.Spec) {
+			customResourceFound.Spec = appProjectCustomResource.Spec
+			if err := r.Update(context.TODO(), customResourceFound); err != nil {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Updated %s Custom Resource in %s namespace", customResourceFound.Name, projectName)
+		}
+	}
+
+	// Allow the application controller of the instance to manage the namespaces of the user
+	subject := rbac.Subject{
+		Kind:     rbac.UserKind,
+		Name:     fmt.Sprintf("system:serviceaccount:%s:%s%s", projectName, ARGOCD_CUSTOMRESOURCE_NAME, ARGOCD_CONTROLLER_SA_SUFFIX),
+		APIGroup: "rbac.authorization.k8s.io",
+	}
+	for _, namespace := range gitOpsNamespaces(workshop, username, projectName) {
+		if result, err := r.addArgocdManagerRole(workshop, namespace, labels, []rbac.Subject{subject}); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// delete the Argo CD of every user
+func (r *WorkshopReconciler) deleteGitOpsPerUser(workshop *workshopv1.Workshop, users int) (reconcile.Result, error) {
+
+	for id := 1; id <= users; id++ {
+		username := fmt.Sprintf("user%d", id)
+		projectName := fmt.Sprintf("%s%d", workshop.Spec.Infrastructure.Project.StagingName, id)
+
+		for _, applicationSpec := range gitOpsApplications(workshop, username, projectName) {
+			application := &argocdv1.Application{}
+			application.Name = gitOpsApplicationName(username, applicationSpec.Name)
+			application.Namespace = projectName
+			// Delete Application
+			if err := r.Delete(context.TODO(), application); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Deleted %s Application", application.Name)
+		}
+
+		for _, namespace := range gitOpsNamespaces(workshop, username, projectName) {
+			roleBinding := &rbac.RoleBinding{}
+			roleBinding.Name = ARGOCD_ROLE_BINDING_NAME
+			roleBinding.Namespace = namespace
+			// Delete roleBinding
+			if err := r.Delete(context.TODO(), roleBinding); err != nil && !errors.IsNotFound(err) {
+				return reconcile.Result{}, err
+			}
+
+			role := &rbac.Role{}
+			role.Name = ARGOCD_ROLE_NAME
+			role.Namespace = namespace
+			// Delete role
+			if err := r.Delete(context.TODO(), role); err != nil && !errors.IsNotFound(err) {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Deleted %s Role and Role Binding in %s namespace", role.Name, namespace)
+		}
+
+		appProject := &argocdv1.AppProject{}
+		appProject.Name = projectName
+		appProject.Namespace = projectName
+		// Delete appProject Custom Resource
+		if err := r.Delete(context.TODO(), appProject); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s appProject Custom Resource", appProject.Name)
+
+		argoCD := &argocdoperatorv1.ArgoCD{}
+		argoCD.Name = ARGOCD_CUSTOMRESOURCE_NAME
+		argoCD.Namespace = projectName
+		// Delete argoCD Custom Resource
+		if err := r.Delete(context.TODO(), argoCD); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s Custom Resource in %s namespace", argoCD.Name, projectName)
+
+		configMap := &corev1.ConfigMap{}
+		configMap.Name = ARGOCD_CONFIGMAP_NAME
+		configMap.Namespace = projectName
+		// Delete ConfigMap
+		if err := r.Delete(context.TODO(), configMap); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s ConfigMap in %s namespace", configMap.Name, projectName)
+
+		for _, secretName := range []string{ARGOCD_CLUSTER_SECRET_NAME, ARGOCD_CONFIG_SECRET_NAME, ARGOCD_SECRET_NAME, gitOpsRepoCredsName(username)} {
+			secret := &corev1.Secret{}
+			secret.Name = secretName
+			secret.Namespace = projectName
+			// Delete Secret
+			if err := r.Delete(context.TODO(), secret); err != nil && !errors.IsNotFound(err) {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Deleted %s Secret in %s namespace", secret.Name, projectName)
+		}
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
@@ -2,8 +2,12 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"reflect"
+	"regexp"
+	"strings"
+
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	argocdoperatorv1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
@@ -11,13 +15,17 @@ import (
 	"github.com/prometheus/common/log"
 	workshopv1 "github.com/stakater/workshop-operator/api/v1"
 	"github.com/stakater/workshop-operator/common/argocd"
+	"github.com/stakater/workshop-operator/common/git"
 	"github.com/stakater/workshop-operator/common/kubernetes"
+	"github.com/stakater/workshop-operator/common/portal"
 	"golang.org/x/crypto/bcrypt"
 	corev1 "k8s.io/api/core/v1"
 	rbac "k8s.io/api/rbac/v1"
 
 	"github.com/stakater/workshop-operator/common/util"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -35,9 +43,16 @@ const (
 	ARGOCD_CONFIGMAP_NAME            = "argocd-cm"
 	ARGOCD_CUSTOMRESOURCE_NAME       = "argocd"
 	ARGOCD_DEPLOYMENT_NAME           = "argocd-server"
+	ARGOCD_DEX_DEPLOYMENT_NAME       = "argocd-dex-server"
 	ARGOCD_CONFIG_SECRET_NAME        = "argocd-default-cluster-config"
+
+	ARGOCD_PASSWORD_CHECKSUM_ANNOTATION = "workshop.stakater.com/password-checksum"
 )
 
+// argocdAccountKeyRegexp matches the keys of the accounts of the users, such as accounts.user1 or
+// accounts.user1.password
+var argocdAccountKeyRegexp = regexp.MustCompile(`^accounts\.(user[0-9]+)(\..+)?$`)
+
 // Reconciling GitOps
 func (r *WorkshopReconciler) reconcileGitOps(workshop *workshopv1.Workshop, users int,
 	appsHostnameSuffix string, openshiftConsoleURL string) (reconcile.Result, error) {
@@ -85,6 +100,14 @@ func (r *WorkshopReconciler) addGitOps(workshop *workshopv1.Workshop, users int,
 		return reconcile.Result{Requeue: true}, nil
 	}
 
+	if isGitOpsPerUser(workshop) {
+		// Delete the shared Argo CD left by the shared mode
+		if result, err := r.deleteArgocdNamespace(workshop); util.IsRequeued(result, err) {
+			return result, err
+		}
+		return r.addGitOpsPerUser(workshop, users)
+	}
+
 	// Create a Project
 	namespace := kubernetes.NewNamespace(workshop, r.Scheme, ARGOCD_NAMESPACE_NAME)
 	if err := r.Create(context.TODO(), namespace); err != nil && !errors.IsAlreadyExists(err) {
@@ -93,17 +116,9 @@ func (r *WorkshopReconciler) addGitOps(workshop *workshopv1.Workshop, users int,
 		log.Infof("Created %s  Project", namespace.Name)
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(workshop.Spec.User.Password), bcrypt.DefaultCost)
-	if err != nil {
-		log.Errorf("Error when Bcrypt encrypt password for Argo CD: %v", err)
-		return reconcile.Result{}, err
-	}
-	bcryptPassword := string(hashedPassword)
-
 	argocdPolicy := ""
 	namespaceList := ""
-	secretData := map[string]string{}
-	configMapData := map[string]string{}
+	accounts := []string{}
 
 	for id := 1; id <= users; id++ {
 		username := fmt.Sprintf("user%d", id)
@@ -118,17 +133,27 @@ func (r *WorkshopReconciler) addGitOps(workshop *workshopv1.Workshop, users int,
 		userPolicy := `p, ` + userRole + `, applications, *, ` + projectName + `/*, allow
 p, ` + userRole + `, clusters, get, https://kubernetes.default.svc, allow
 p, ` + userRole + `, projects, *,` + projectName + `, allow
-p, ` + userRole + `, repositories, *, http://gitea-server.gitea.svc:3000/` + username + `/*, allow
+p, ` + userRole + `, repositories, *, ` + gitRepositoriesPattern(workshop, username) + `, allow
 g, ` + username + `, ` + userRole + `
 `
 		argocdPolicy = fmt.Sprintf("%s%s", argocdPolicy, userPolicy)
 
-		secretData[fmt.Sprintf("accounts.%s.password", username)] = bcryptPassword
-
-		configMapData[fmt.Sprintf("accounts.%s", username)] = "login"
+		if !workshop.Spec.Infrastructure.SSO.Enabled {
+			accounts = append(accounts, username)
+		}
 
 		labels["app.kubernetes.io/name"] = "appproject-cr"
-		appProjectCustomResource := argocd.NewAppProjectCustomResource(workshop, r.Scheme, projectName, ARGOCD_NAMESPACE_NAME, labels, argocdPolicy)
+		appProjectCustomResource := argocd.NewAppProjectCustomResource(workshop, r.Scheme, projectName, ARGOCD_NAMESPACE_NAME, labels,
+			gitOpsSourceRepos(workshop, username, projectName))
+		// Allow the Applications of the user to deploy to their namespaces
+		for _, destinationNamespace := range gitOpsNamespaces(workshop, username, projectName) {
+			if destinationNamespace != projectName {
+				appProjectCustomResource.Spec.Destinations = append(appProjectCustomResource.Spec.Destinations, argocdv1.ApplicationDestination{
+					Namespace: destinationNamespace,
+					Server:    "https://kubernetes.default.svc",
+				})
+			}
+		}
 		if err := r.Create(context.TODO(), appProjectCustomResource); err != nil && !errors.IsAlreadyExists(err) {
 			return reconcile.Result{}, err
 		} else if err == nil {
@@ -157,118 +182,535 @@ g, ` + username + `, ` + userRole + `
 
 		subjects = append(subjects, argocdSubject)
 
-		role := kubernetes.NewRole(workshop, r.Scheme,
-			ARGOCD_ROLE_NAME, projectName, labels, kubernetes.ArgoCDRules())
-		if err := r.Create(context.TODO(), role); err != nil && !errors.IsAlreadyExists(err) {
+		if result, err := r.addArgocdManagerRole(workshop, projectName, labels, subjects); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
+
+	if result, err := r.manageArgocdAccounts(workshop, namespace.Name, labels, accounts); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	labels["app.kubernetes.io/name"] = "argocd-cr"
+	argoCDCustomResource := argocd.NewArgoCDCustomResource(workshop, r.Scheme, ARGOCD_CUSTOMRESOURCE_NAME, ARGOCD_NAMESPACE_NAME, labels, argocdPolicy)
+	if err := r.Create(context.TODO(), argoCDCustomResource); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s  Custom Resource", argoCDCustomResource.Name)
+	} else if errors.IsAlreadyExists(err) {
+		customResourceFound := &argocdoperatorv1.ArgoCD{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: argoCDCustomResource.Name, Namespace: namespace.Name}, customResourceFound); err != nil {
 			return reconcile.Result{}, err
 		} else if err == nil {
-			log.Infof("Created %s  Role in %s namespace", role.Name, projectName)
+			if !reflect.DeepEqual(&argocdPolicy, customResourceFound.Spec.RBAC.Policy) ||
+				argoCDCustomResource.Spec.Dex.OpenShiftOAuth != customResourceFound.Spec.Dex.OpenShiftOAuth {
+				// Patch rather than update, the vendored ArgoCD type missing fields such as applicationSet
+				patch := client.MergeFrom(customResourceFound.DeepCopy())
+				customResourceFound.Spec.RBAC.Policy = &argocdPolicy
+				customResourceFound.Spec.Dex.OpenShiftOAuth = argoCDCustomResource.Spec.Dex.OpenShiftOAuth
+				if err := r.Patch(context.TODO(), customResourceFound, patch); err != nil {
+					return reconcile.Result{}, err
+				}
+				log.Infof("Updated %s  Custom Resource", customResourceFound.Name)
+			}
 		}
+	}
 
-		roleBinding := kubernetes.NewRoleBindingUsers(workshop, r.Scheme, ARGOCD_ROLE_BINDING_NAME, projectName, labels, subjects, role.Name, ARGOCD_ROLE_KIND_NAME)
-		if err := r.Create(context.TODO(), roleBinding); err != nil && !errors.IsAlreadyExists(err) {
+	// Wait for ArgoCD Dex Server to be running
+	if workshop.Spec.Infrastructure.SSO.Enabled && !kubernetes.GetK8Client().GetDeploymentStatus(ARGOCD_DEX_DEPLOYMENT_NAME, namespace.Name) {
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	// Wait for ArgoCD Server to be running
+	if !kubernetes.GetK8Client().GetDeploymentStatus(ARGOCD_DEPLOYMENT_NAME, namespace.Name) {
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	labels["app.kubernetes.io/name"] = "argocd-default-cluster-config"
+
+	if result, err := r.manageArgocdDefaultClusterConfigSecret(workshop, namespace.Name, labels, namespaceList); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	if result, err := r.addGitOpsRepositoryCredentials(workshop, users); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	if result, err := r.addGitOpsApplications(workshop, users); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	if result, err := r.addGitOpsApplicationSets(workshop, users); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// gitOpsApplications returns the Application specs of the user, templated with its username and defaulted to its
+// staging project
+func gitOpsApplications(workshop *workshopv1.Workshop, username string, projectName string) []workshopv1.GitOpsApplicationSpec {
+	replacer := strings.NewReplacer(portal.UsernamePlaceholder, username)
+
+	applications := []workshopv1.GitOpsApplicationSpec{}
+	for _, applicationSpec := range workshop.Spec.Infrastructure.GitOps.Applications {
+		applicationSpec.RepoURL = replacer.Replace(applicationSpec.RepoURL)
+		applicationSpec.Path = replacer.Replace(applicationSpec.Path)
+		applicationSpec.TargetRevision = replacer.Replace(applicationSpec.TargetRevision)
+		applicationSpec.Namespace = replacer.Replace(applicationSpec.Namespace)
+		if applicationSpec.Namespace == "" {
+			applicationSpec.Namespace = projectName
+		}
+		applications = append(applications, applicationSpec)
+	}
+	return applications
+}
+
+// gitOpsApplicationName returns the name of the Application of the user, all the users sharing the argocd namespace
+func gitOpsApplicationName(username string, name string) string {
+	return username + "-" + name
+}
+
+// gitOpsApplicationRepoURL returns the URL of the repository of the user the Application is synced from, as seen
+// from the cluster
+func gitOpsApplicationRepoURL(workshop *workshopv1.Workshop, username string, applicationSpec workshopv1.GitOpsApplicationSpec) (string, error) {
+	if applicationSpec.RepoURL != "" {
+		return applicationSpec.RepoURL, nil
+	}
+
+	repository := applicationSpec.Repository
+	if repository == "" {
+		repositories := git.Repositories(workshop)
+		if len(repositories) == 0 {
+			return "", fmt.Errorf("no repository is imported for the users, set the repository or the repoURL of the %s Application", applicationSpec.Name)
+		}
+		repository = repositories[0].Name
+	}
+	return gitRepositoryURL(workshop, gitInternalURL(workshop), username, repository), nil
+}
+
+// gitOpsSourceRepos returns the repositories the AppProject of the user can sync from: the ones of the user and
+// those its Applications and ApplicationSets are configured with, so that the user cannot read the repositories of
+// the others through the credential templates shared in the argocd namespace
+func gitOpsSourceRepos(workshop *workshopv1.Workshop, username string, projectName string) []string {
+	sourceRepos := []string{gitRepositoriesPattern(workshop, username)}
+	for _, applicationSpec := range gitOpsApplications(workshop, username, projectName) {
+		// Applications without repository are reported when they are created
+		if repoURL, err := gitOpsApplicationRepoURL(workshop, username, applicationSpec); err == nil && !util.StringInSlice(repoURL, sourceRepos) {
+			sourceRepos = append(sourceRepos, repoURL)
+		}
+	}
+	for _, applicationSetSpec := range workshop.Spec.Infrastructure.GitOps.ApplicationSets {
+		repoURL := applicationSetSpec.RepoURL
+		if repoURL == "" {
+			repoURL = workshop.Spec.Source.GitURL
+		}
+		repoURL = strings.ReplaceAll(repoURL, portal.UsernamePlaceholder, username)
+		if !util.StringInSlice(repoURL, sourceRepos) {
+			sourceRepos = append(sourceRepos, repoURL)
+		}
+	}
+	return sourceRepos
+}
+
+// gitOpsApplicationSetNamespace returns the namespace of the Applications of an ApplicationSet for the user
+func gitOpsApplicationSetNamespace(applicationSetSpec workshopv1.GitOpsApplicationSetSpec, username string, projectName string) string {
+	if applicationSetSpec.Namespace == "" {
+		return projectName
+	}
+	return strings.ReplaceAll(applicationSetSpec.Namespace, portal.UsernamePlaceholder, username)
+}
+
+// Add the ApplicationSets deploying a shared application for every user, only their list generators changing
+// with the users
+func (r *WorkshopReconciler) addGitOpsApplicationSets(workshop *workshopv1.Workshop, users int) (reconcile.Result, error) {
+
+	labels := map[string]string{
+		"app.kubernetes.io/part-of": "argocd",
+		"app.kubernetes.io/name":    "applicationset-cr",
+	}
+	applicationLabels := map[string]string{
+		"app.kubernetes.io/part-of": "argocd",
+		"app.kubernetes.io/name":    "applicationset-application",
+	}
+
+	applicationSets := workshop.Spec.Infrastructure.GitOps.ApplicationSets
+	if len(applicationSets) > 0 {
+		if result, err := r.enableArgocdApplicationSet(ARGOCD_NAMESPACE_NAME); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
+
+	applicationSetNames := map[string]bool{}
+	for _, applicationSetSpec := range applicationSets {
+		elements := []map[string]string{}
+		for id := 1; id <= users; id++ {
+			username := fmt.Sprintf("user%d", id)
+			projectName := fmt.Sprintf("%s%d", workshop.Spec.Infrastructure.Project.StagingName, id)
+			elements = append(elements, map[string]string{
+				"username":  username,
+				"project":   projectName,
+				"namespace": gitOpsApplicationSetNamespace(applicationSetSpec, username, projectName),
+			})
+		}
+
+		repoURL := applicationSetSpec.RepoURL
+		if repoURL == "" {
+			repoURL = workshop.Spec.Source.GitURL
+		}
+		repoURL = strings.ReplaceAll(repoURL, portal.UsernamePlaceholder, "{{username}}")
+		if applicationSetSpec.TargetRevision == "" {
+			applicationSetSpec.TargetRevision = workshop.Spec.Source.GitBranch
+		}
+
+		applicationSet := argocd.NewApplicationSetCustomResource(workshop, r.Scheme, applicationSetSpec.Name, ARGOCD_NAMESPACE_NAME,
+			labels, applicationLabels, repoURL, applicationSetSpec, elements)
+		if err := r.Create(context.TODO(), applicationSet); err != nil && !errors.IsAlreadyExists(err) {
 			return reconcile.Result{}, err
 		} else if err == nil {
-			log.Infof("Created %s  Role Binding in %s namespace", roleBinding.Name, projectName)
+			log.Infof("Created %s ApplicationSet", applicationSet.Name)
 		} else if errors.IsAlreadyExists(err) {
-			found := &rbac.RoleBinding{}
-			if err := r.Get(context.TODO(), types.NamespacedName{Name: roleBinding.Name, Namespace: projectName}, found); err != nil {
+			applicationSetFound := &argocd.ApplicationSet{}
+			if err := r.Get(context.TODO(), types.NamespacedName{Name: applicationSet.Name, Namespace: ARGOCD_NAMESPACE_NAME}, applicationSetFound); err != nil {
 				return reconcile.Result{}, err
-			} else if err == nil {
-				if !reflect.DeepEqual(subjects, found.Subjects) {
-					found.Subjects = subjects
-					if err := r.Update(context.TODO(), found); err != nil {
-						return reconcile.Result{}, err
-					}
-					log.Infof("Updated %s  Role Binding in %s namespace", found.Name, projectName)
+			} else if !reflect.DeepEqual(applicationSet.Spec, applicationSetFound.Spec) {
+				patch := client.MergeFrom(applicationSetFound.DeepCopy())
+				applicationSetFound.Spec = applicationSet.Spec
+				if err := r.Patch(context.TODO(), applicationSetFound, patch); err != nil {
+					return reconcile.Result{}, err
 				}
+				log.Infof("Updated %s ApplicationSet", applicationSetFound.Name)
 			}
 		}
+		applicationSetNames[applicationSet.Name] = true
 	}
 
-	labels["app.kubernetes.io/name"] = "argocd-secret"
-	secret := kubernetes.NewStringDataSecret(workshop, r.Scheme, ARGOCD_SECRET_NAME, ARGOCD_NAMESPACE_NAME, labels, secretData)
-	if err := r.Create(context.TODO(), secret); err != nil && !errors.IsAlreadyExists(err) {
+	// Delete the ApplicationSets removed from the spec, with their Applications
+	applicationSetList := &argocd.ApplicationSetList{}
+	if err := r.List(context.TODO(), applicationSetList, client.InNamespace(ARGOCD_NAMESPACE_NAME), client.MatchingLabels(labels)); err != nil {
+		if meta.IsNoMatchError(err) && len(applicationSets) == 0 {
+			return reconcile.Result{}, nil
+		}
 		return reconcile.Result{}, err
-	} else if err == nil {
-		log.Infof("Created %s  Secret", secret.Name)
-		// } else if errors.IsAlreadyExists(err) {
-		// 	secretFound := &corev1.Secret{}
-		// 	if err := r.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: namespace.Name}, secretFound); err != nil {
-		// 		return reconcile.Result{}, err
-		// 	} else if err == nil {
-		// 		if !util.IsIntersectMap(secretData, secretFound.StringData) {
-		// 			secretFound.StringData = secretData
-		// 			if err := r.Update(context.TODO(), secretFound); err != nil {
-		// 				return reconcile.Result{}, err
-		// 			}
-		// 			log.Infof("Updated %s Secret", secretFound.Name)
-		// 		}
-		// 	}
+	}
+	for i := range applicationSetList.Items {
+		applicationSet := &applicationSetList.Items[i]
+		if applicationSetNames[applicationSet.Name] {
+			continue
+		}
+		if err := r.Delete(context.TODO(), applicationSet); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s ApplicationSet", applicationSet.Name)
 	}
 
-	labels["app.kubernetes.io/name"] = "argocd-cm"
-	configmap := kubernetes.NewConfigMap(workshop, r.Scheme, ARGOCD_CONFIGMAP_NAME, ARGOCD_NAMESPACE_NAME, labels, configMapData)
-	if err := r.Create(context.TODO(), configmap); err != nil && !errors.IsAlreadyExists(err) {
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// enableArgocdApplicationSet deploys the ApplicationSet controller of the ArgoCD of the namespace. The field is
+// missing from the vendored ArgoCD type, the custom resource being read and patched unstructured.
+func (r *WorkshopReconciler) enableArgocdApplicationSet(namespace string) (reconcile.Result, error) {
+	argoCD := &unstructured.Unstructured{}
+	argoCD.SetGroupVersionKind(argocdoperatorv1.SchemeGroupVersion.WithKind("ArgoCD"))
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: ARGOCD_CUSTOMRESOURCE_NAME, Namespace: namespace}, argoCD); err != nil {
 		return reconcile.Result{}, err
-	} else if err == nil {
-		log.Infof("Created %s  ConfigMap", configmap.Name)
-	} else if errors.IsAlreadyExists(err) {
-		configmapFound := &corev1.ConfigMap{}
-		if err := r.Get(context.TODO(), types.NamespacedName{Name: configmap.Name, Namespace: namespace.Name}, configmapFound); err != nil {
-			return reconcile.Result{}, err
-		} else if err == nil {
-			if !util.IsIntersectMap(configMapData, configmapFound.Data) {
-				configmapFound.Data = configMapData
-				if err := r.Update(context.TODO(), configmapFound); err != nil {
+	}
+
+	if _, found, _ := unstructured.NestedMap(argoCD.Object, "spec", "applicationSet"); found {
+		return reconcile.Result{}, nil
+	}
+	patch := client.RawPatch(types.MergePatchType, []byte(`{"spec":{"applicationSet":{}}}`))
+	if err := r.Patch(context.TODO(), argoCD, patch); err != nil {
+		return reconcile.Result{}, err
+	}
+	log.Infof("Enabled the ApplicationSet controller of %s Custom Resource", argoCD.GetName())
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// Add the Applications of the users and summarize their sync and health status
+func (r *WorkshopReconciler) addGitOpsApplications(workshop *workshopv1.Workshop, users int) (reconcile.Result, error) {
+
+	labels := map[string]string{
+		"app.kubernetes.io/part-of": "argocd",
+		"app.kubernetes.io/name":    "application-cr",
+	}
+
+	applicationNames := map[types.NamespacedName]bool{}
+	namespaces := []string{ARGOCD_NAMESPACE_NAME}
+	userStatuses := []workshopv1.GitOpsApplicationUserStatus{}
+	if len(workshop.Spec.Infrastructure.GitOps.Applications) > 0 {
+		for id := 1; id <= users; id++ {
+			username := fmt.Sprintf("user%d", id)
+			projectName := fmt.Sprintf("%s%d", workshop.Spec.Infrastructure.Project.StagingName, id)
+			namespace := gitOpsNamespace(workshop, projectName)
+			if !util.StringInSlice(namespace, namespaces) {
+				namespaces = append(namespaces, namespace)
+			}
+
+			userStatus := workshopv1.GitOpsApplicationUserStatus{Username: username}
+			for _, applicationSpec := range gitOpsApplications(workshop, username, projectName) {
+				repoURL, err := gitOpsApplicationRepoURL(workshop, username, applicationSpec)
+				if err != nil {
 					return reconcile.Result{}, err
 				}
-				log.Infof("Updated %s  ConfigMap", configmapFound.Name)
+
+				name := gitOpsApplicationName(username, applicationSpec.Name)
+				applicationStatus, err := r.addGitOpsApplication(workshop, labels, namespace, projectName, repoURL,
+					name, applicationSpec)
+				if err != nil {
+					return reconcile.Result{}, err
+				}
+				applicationNames[types.NamespacedName{Name: name, Namespace: namespace}] = true
+
+				userStatus.Applications = append(userStatus.Applications, applicationStatus)
+				userStatus.Total++
+				if applicationStatus.Sync == string(argocdv1.SyncStatusCodeSynced) {
+					userStatus.Synced++
+				}
+				if applicationStatus.Health == argocdv1.HealthStatusHealthy {
+					userStatus.Healthy++
+				}
 			}
+			userStatuses = append(userStatuses, userStatus)
 		}
 	}
 
-	labels["app.kubernetes.io/name"] = "argocd-cr"
-	argoCDCustomResource := argocd.NewArgoCDCustomResource(workshop, r.Scheme, ARGOCD_CUSTOMRESOURCE_NAME, ARGOCD_NAMESPACE_NAME, labels, argocdPolicy)
-	if err := r.Create(context.TODO(), argoCDCustomResource); err != nil && !errors.IsAlreadyExists(err) {
+	// Delete the Applications removed from the spec
+	for _, namespace := range namespaces {
+		applicationList := &argocdv1.ApplicationList{}
+		if err := r.List(context.TODO(), applicationList, client.InNamespace(namespace), client.MatchingLabels(labels)); err != nil {
+			return reconcile.Result{}, err
+		}
+		for i := range applicationList.Items {
+			application := &applicationList.Items[i]
+			if applicationNames[types.NamespacedName{Name: application.Name, Namespace: namespace}] {
+				continue
+			}
+			if err := r.Delete(context.TODO(), application); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Deleted %s Application", application.Name)
+		}
+	}
+
+	if len(userStatuses) == 0 {
+		userStatuses = nil
+	}
+	if !reflect.DeepEqual(workshop.Status.GitOpsApplications, userStatuses) {
+		if err := r.updateStatus(workshop, func(status *workshopv1.WorkshopStatus) {
+			status.GitOpsApplications = userStatuses
+		}); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// Create or update an Application of a user and return its status
+func (r *WorkshopReconciler) addGitOpsApplication(workshop *workshopv1.Workshop, labels map[string]string, namespace string,
+	projectName string, repoURL string, name string, applicationSpec workshopv1.GitOpsApplicationSpec) (workshopv1.GitOpsApplicationStatus, error) {
+
+	application := argocd.NewApplicationCustomResource(workshop, r.Scheme, name, namespace, labels,
+		projectName, repoURL, applicationSpec)
+
+	applicationFound := &argocdv1.Application{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, applicationFound); err != nil && errors.IsNotFound(err) {
+		if err := r.Create(context.TODO(), application); err != nil {
+			return workshopv1.GitOpsApplicationStatus{}, err
+		}
+		log.Infof("Created %s Application", application.Name)
+		applicationFound = application
+	} else if err != nil {
+		return workshopv1.GitOpsApplicationStatus{}, err
+	} else if !reflect.DeepEqual(application.Spec, applicationFound.Spec) {
+		patch := client.MergeFrom(applicationFound.DeepCopy())
+		applicationFound.Spec = application.Spec
+		if err := r.Patch(context.TODO(), applicationFound, patch); err != nil {
+			return workshopv1.GitOpsApplicationStatus{}, err
+		}
+		log.Infof("Updated %s Application", applicationFound.Name)
+	}
+
+	status := workshopv1.GitOpsApplicationStatus{
+		Name:    applicationFound.Name,
+		Sync:    string(applicationFound.Status.Sync.Status),
+		Health:  applicationFound.Status.Health.Status,
+		Message: applicationFound.Status.Health.Message,
+	}
+	if len(applicationFound.Status.Conditions) > 0 {
+		status.Message = applicationFound.Status.Conditions[0].Message
+	}
+	return status, nil
+}
+
+// addArgocdManagerRole allows the subjects, the application controllers of Argo CD, to manage the namespace
+func (r *WorkshopReconciler) addArgocdManagerRole(workshop *workshopv1.Workshop, namespace string,
+	labels map[string]string, subjects []rbac.Subject) (reconcile.Result, error) {
+
+	role := kubernetes.NewRole(workshop, r.Scheme,
+		ARGOCD_ROLE_NAME, namespace, labels, kubernetes.ArgoCDRules())
+	if err := r.Create(context.TODO(), role); err != nil && !errors.IsAlreadyExists(err) {
 		return reconcile.Result{}, err
 	} else if err == nil {
-		log.Infof("Created %s  Custom Resource", argoCDCustomResource.Name)
+		log.Infof("Created %s  Role in %s namespace", role.Name, namespace)
+	}
+
+	roleBinding := kubernetes.NewRoleBindingUsers(workshop, r.Scheme, ARGOCD_ROLE_BINDING_NAME, namespace, labels, subjects, role.Name, ARGOCD_ROLE_KIND_NAME)
+	if err := r.Create(context.TODO(), roleBinding); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s  Role Binding in %s namespace", roleBinding.Name, namespace)
 	} else if errors.IsAlreadyExists(err) {
-		customResourceFound := &argocdoperatorv1.ArgoCD{}
-		if err := r.Get(context.TODO(), types.NamespacedName{Name: argoCDCustomResource.Name, Namespace: namespace.Name}, customResourceFound); err != nil {
+		found := &rbac.RoleBinding{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: roleBinding.Name, Namespace: namespace}, found); err != nil {
 			return reconcile.Result{}, err
 		} else if err == nil {
-			if !reflect.DeepEqual(&argocdPolicy, customResourceFound.Spec.RBAC.Policy) {
-				customResourceFound.Spec.RBAC.Policy = &argocdPolicy
-				if err := r.Update(context.TODO(), customResourceFound); err != nil {
+			if !reflect.DeepEqual(subjects, found.Subjects) {
+				found.Subjects = subjects
+				if err := r.Update(context.TODO(), found); err != nil {
 					return reconcile.Result{}, err
 				}
-				log.Infof("Updated %s  Custom Resource", customResourceFound.Name)
+				log.Infof("Updated %s  Role Binding in %s namespace", found.Name, namespace)
 			}
 		}
 	}
 
-	// Wait for ArgoCD Dex Server to be running
-	// if !kubernetes.GetK8Client().GetDeploymentStatus("argocd-dex-server", namespace.Name) {
-	// 	return reconcile.Result{Requeue: true}, nil
-	// }
+	//Success
+	return reconcile.Result{}, nil
+}
 
-	// Wait for ArgoCD Server to be running
-	if !kubernetes.GetK8Client().GetDeploymentStatus(ARGOCD_DEPLOYMENT_NAME, namespace.Name) {
-		return reconcile.Result{Requeue: true}, nil
+// manageArgocdAccounts merges the local accounts of the users into argocd-secret and argocd-cm, keeping the keys
+// managed by Argo CD, and removes the accounts of the users gone. The bcrypt hash of the password is only
+// regenerated when the password changes, the checksum of the password it was generated from being annotated.
+func (r *WorkshopReconciler) manageArgocdAccounts(workshop *workshopv1.Workshop, namespaceName string,
+	labels map[string]string, accounts []string) (reconcile.Result, error) {
+
+	passwordChecksum := fmt.Sprintf("%x", sha256.Sum256([]byte(workshop.Spec.User.Password)))
+	bcryptPassword := ""
+	hashPassword := func() (string, error) {
+		if bcryptPassword == "" {
+			hashedPassword, err := bcrypt.GenerateFromPassword([]byte(workshop.Spec.User.Password), bcrypt.DefaultCost)
+			if err != nil {
+				log.Errorf("Error when Bcrypt encrypt password for Argo CD: %v", err)
+				return "", err
+			}
+			bcryptPassword = string(hashedPassword)
+		}
+		return bcryptPassword, nil
 	}
 
-	labels["app.kubernetes.io/name"] = "argocd-default-cluster-config"
+	isAccount := map[string]bool{}
+	for _, username := range accounts {
+		isAccount[username] = true
+	}
 
-	if result, err := r.manageArgocdDefaultClusterConfigSecret(workshop, namespace.Name, labels, namespaceList); util.IsRequeued(result, err) {
-		return result, err
+	// Create/Update Secret
+	labels["app.kubernetes.io/name"] = "argocd-secret"
+	secretFound := &corev1.Secret{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: ARGOCD_SECRET_NAME, Namespace: namespaceName}, secretFound); err != nil && errors.IsNotFound(err) {
+		secretData := map[string]string{}
+		for _, username := range accounts {
+			hash, err := hashPassword()
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			secretData[fmt.Sprintf("accounts.%s.password", username)] = hash
+		}
+		secret := kubernetes.NewStringDataSecret(workshop, r.Scheme, ARGOCD_SECRET_NAME, namespaceName, labels, secretData)
+		secret.Annotations = map[string]string{ARGOCD_PASSWORD_CHECKSUM_ANNOTATION: passwordChecksum}
+		if err := r.Create(context.TODO(), secret); err != nil {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Created %s  Secret", secret.Name)
+	} else if err != nil {
+		return reconcile.Result{}, err
+	} else {
+		passwordChanged := secretFound.Annotations[ARGOCD_PASSWORD_CHECKSUM_ANNOTATION] != passwordChecksum
+		secretData := map[string][]byte{}
+		for key, value := range secretFound.Data {
+			if username := argocdAccountName(key); username == "" || isAccount[username] {
+				secretData[key] = value
+			}
+		}
+		for _, username := range accounts {
+			key := fmt.Sprintf("accounts.%s.password", username)
+			if len(secretData[key]) > 0 && !passwordChanged {
+				continue
+			}
+			hash, err := hashPassword()
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			secretData[key] = []byte(hash)
+		}
+
+		if passwordChanged || (len(secretData) > 0 || len(secretFound.Data) > 0) && !reflect.DeepEqual(secretData, secretFound.Data) {
+			if secretFound.Annotations == nil {
+				secretFound.Annotations = map[string]string{}
+			}
+			secretFound.Annotations[ARGOCD_PASSWORD_CHECKSUM_ANNOTATION] = passwordChecksum
+			secretFound.Data = secretData
+			if err := r.Update(context.TODO(), secretFound); err != nil {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Updated %s  Secret", secretFound.Name)
+		}
+	}
+
+	// Create/Update ConfigMap
+	labels["app.kubernetes.io/name"] = "argocd-cm"
+	configMapFound := &corev1.ConfigMap{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: ARGOCD_CONFIGMAP_NAME, Namespace: namespaceName}, configMapFound); err != nil && errors.IsNotFound(err) {
+		configMapData := map[string]string{}
+		for _, username := range accounts {
+			configMapData[fmt.Sprintf("accounts.%s", username)] = "login"
+		}
+		configmap := kubernetes.NewConfigMap(workshop, r.Scheme, ARGOCD_CONFIGMAP_NAME, namespaceName, labels, configMapData)
+		if err := r.Create(context.TODO(), configmap); err != nil {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Created %s  ConfigMap", configmap.Name)
+	} else if err != nil {
+		return reconcile.Result{}, err
+	} else {
+		configMapData := map[string]string{}
+		for key, value := range configMapFound.Data {
+			if username := argocdAccountName(key); username == "" || isAccount[username] {
+				configMapData[key] = value
+			}
+		}
+		for _, username := range accounts {
+			configMapData[fmt.Sprintf("accounts.%s", username)] = "login"
+		}
+
+		if (len(configMapData) > 0 || len(configMapFound.Data) > 0) && !reflect.DeepEqual(configMapData, configMapFound.Data) {
+			configMapFound.Data = configMapData
+			if err := r.Update(context.TODO(), configMapFound); err != nil {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Updated %s  ConfigMap", configMapFound.Name)
+		}
 	}
 
 	//Success
 	return reconcile.Result{}, nil
 }
 
+// argocdAccountName returns the user of an accounts.userN key of argocd-secret or argocd-cm, or "" for the keys
+// of the other accounts and settings
+func argocdAccountName(key string) string {
+	if match := argocdAccountKeyRegexp.FindStringSubmatch(key); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
 func (r *WorkshopReconciler) manageArgocdDefaultClusterConfigSecret(workshop *workshopv1.Workshop, namespaceName string,
 	labels map[string]string, namespaceList string) (reconcile.Result, error) {
 
@@ -311,6 +753,30 @@ func (r *WorkshopReconciler) deleteGitOps(workshop *workshopv1.Workshop, users i
 	labels := map[string]string{
 		"app.kubernetes.io/part-of": "argocd",
 	}
+
+	if isGitOpsPerUser(workshop) {
+		if result, err := r.deleteGitOpsPerUser(workshop, users); util.IsRequeued(result, err) {
+			return result, err
+		}
+
+		subscription := kubernetes.NewRedHatSubscription(workshop, r.Scheme, GITOPS_SUBSCRIPTION_NAME, GITOPS_OPERATOR_NAMESPACE_NAME,
+			GITOPS_SUBSCRIPTION_PACKAGE_NAME, channel, clusterServiceVersion)
+		gitopsCSV := subscription.Spec.StartingCSV
+		// Delete subscription
+		if err := r.Delete(context.TODO(), subscription); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s  Subscription", subscription.Name)
+
+		operatorCSV := kubernetes.NewRedHatClusterServiceVersion(workshop, r.Scheme, gitopsCSV, GITOPS_OPERATOR_NAMESPACE_NAME)
+		if err := r.Delete(context.TODO(), operatorCSV); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s  ClusterServiceVersion", operatorCSV.Name)
+
+		//Success
+		return reconcile.Result{}, nil
+	}
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(workshop.Spec.User.Password), bcrypt.DefaultCost)
 	if err != nil {
 		log.Errorf("Error when Bcrypt encrypt password for Argo CD: %v", err)
@@ -327,10 +793,21 @@ func (r *WorkshopReconciler) deleteGitOps(workshop *workshopv1.Workshop, users i
 		return result, err
 	}
 
+	for _, applicationSetSpec := range workshop.Spec.Infrastructure.GitOps.ApplicationSets {
+		applicationSet := &argocd.ApplicationSet{}
+		applicationSet.Name = applicationSetSpec.Name
+		applicationSet.Namespace = ARGOCD_NAMESPACE_NAME
+		// Delete ApplicationSet
+		if err := r.Delete(context.TODO(), applicationSet); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s ApplicationSet", applicationSet.Name)
+	}
+
 	labels["app.kubernetes.io/name"] = "argocd-cr"
 	argoCDCustomResource := argocd.NewArgoCDCustomResource(workshop, r.Scheme, ARGOCD_CUSTOMRESOURCE_NAME, ARGOCD_NAMESPACE_NAME, labels, argocdPolicy)
 	// Delete argoCD Custom Resource
-	if err := r.Delete(context.TODO(), argoCDCustomResource); err != nil {
+	if err := r.Delete(context.TODO(), argoCDCustomResource); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s  Custom Resource", argoCDCustomResource.Name)
@@ -338,7 +815,7 @@ func (r *WorkshopReconciler) deleteGitOps(workshop *workshopv1.Workshop, users i
 	labels["app.kubernetes.io/name"] = "argocd-cm"
 	configmap := kubernetes.NewConfigMap(workshop, r.Scheme, ARGOCD_CONFIGMAP_NAME, ARGOCD_NAMESPACE_NAME, labels, configMapData)
 	// Delete Configmap
-	if err := r.Delete(context.TODO(), configmap); err != nil {
+	if err := r.Delete(context.TODO(), configmap); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s  Configmap", configmap.Name)
@@ -346,7 +823,7 @@ func (r *WorkshopReconciler) deleteGitOps(workshop *workshopv1.Workshop, users i
 	labels["app.kubernetes.io/name"] = "argocd-secret"
 	secret := kubernetes.NewStringDataSecret(workshop, r.Scheme, ARGOCD_SECRET_NAME, ARGOCD_NAMESPACE_NAME, labels, secretData)
 	// Delete Secret
-	if err := r.Delete(context.TODO(), secret); err != nil {
+	if err := r.Delete(context.TODO(), secret); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s  Secret", secret.Name)
@@ -364,7 +841,7 @@ func (r *WorkshopReconciler) deleteGitOps(workshop *workshopv1.Workshop, users i
 		userPolicy := `p, ` + userRole + `, applications, *, ` + projectName + `/*, allow
 p, ` + userRole + `, clusters, get, https://kubernetes.default.svc, allow
 p, ` + userRole + `, projects, *,` + projectName + `, allow
-p, ` + userRole + `, repositories, *, http://gitea-server.gitea.svc:3000/` + username + `/*, allow
+p, ` + userRole + `, repositories, *, ` + gitRepositoriesPattern(workshop, username) + `, allow
 g, ` + username + `, ` + userRole + `
 `
 		argocdPolicy = fmt.Sprintf("%s%s", argocdPolicy, userPolicy)
@@ -386,21 +863,32 @@ g, ` + username + `, ` + userRole + `
 
 		roleBinding := kubernetes.NewRoleBindingUsers(workshop, r.Scheme, ARGOCD_ROLE_BINDING_NAME, projectName, labels, subjects, role.Name, ARGOCD_ROLE_KIND_NAME)
 		// Delete roleBinding
-		if err := r.Delete(context.TODO(), roleBinding); err != nil {
+		if err := r.Delete(context.TODO(), roleBinding); err != nil && !errors.IsNotFound(err) {
 			return reconcile.Result{}, err
 		}
 		log.Infof("Deleted %s  Role Binding  in %s namespace", roleBinding.Name, projectName)
 
 		// Delete role
-		if err := r.Delete(context.TODO(), role); err != nil {
+		if err := r.Delete(context.TODO(), role); err != nil && !errors.IsNotFound(err) {
 			return reconcile.Result{}, err
 		}
 		log.Infof("Deleted %s  role in %s namespace ", role.Name, projectName)
 
+		for _, applicationSpec := range gitOpsApplications(workshop, username, projectName) {
+			application := &argocdv1.Application{}
+			application.Name = gitOpsApplicationName(username, applicationSpec.Name)
+			application.Namespace = ARGOCD_NAMESPACE_NAME
+			// Delete Application
+			if err := r.Delete(context.TODO(), application); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Deleted %s Application", application.Name)
+		}
+
 		labels["app.kubernetes.io/name"] = "appproject-cr"
-		appProjectCustomResource := argocd.NewAppProjectCustomResource(workshop, r.Scheme, projectName, ARGOCD_NAMESPACE_NAME, labels, argocdPolicy)
+		appProjectCustomResource := argocd.NewAppProjectCustomResource(workshop, r.Scheme, projectName, ARGOCD_NAMESPACE_NAME, labels, nil)
 		// Delete appProject Custom Resource
-		if err := r.Delete(context.TODO(), appProjectCustomResource); err != nil {
+		if err := r.Delete(context.TODO(), appProjectCustomResource); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
 			return reconcile.Result{}, err
 		}
 		log.Infof("Deleted %s  appProject Custom Resource ", appProjectCustomResource.Name)
@@ -410,41 +898,56 @@ g, ` + username + `, ` + userRole + `
 		GITOPS_SUBSCRIPTION_PACKAGE_NAME, channel, clusterServiceVersion)
 	gitopsCSV := subscription.Spec.StartingCSV
 	// Delete subscription
-	if err := r.Delete(context.TODO(), subscription); err != nil {
+	if err := r.Delete(context.TODO(), subscription); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s  Subscription", subscription.Name)
 
 	operatorCSV := kubernetes.NewRedHatClusterServiceVersion(workshop, r.Scheme, gitopsCSV, GITOPS_OPERATOR_NAMESPACE_NAME)
-	if err := r.Delete(context.TODO(), operatorCSV); err != nil {
+	if err := r.Delete(context.TODO(), operatorCSV); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s  ClusterServiceVersion", operatorCSV.Name)
 
-	namespace := kubernetes.NewNamespace(workshop, r.Scheme, ARGOCD_NAMESPACE_NAME)
-	// Delete a Project
-	if err := r.Delete(context.TODO(), namespace); err != nil {
-		return reconcile.Result{}, err
+	if result, err := r.deleteArgocdNamespace(workshop); util.IsRequeued(result, err) {
+		return result, err
 	}
-	log.Infof("Deleting %s  Project", namespace.Name)
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// deleteArgocdNamespace deletes the namespace of the shared Argo CD, with the ArgoCD and its AppProjects,
+// Applications and credentials
+func (r *WorkshopReconciler) deleteArgocdNamespace(workshop *workshopv1.Workshop) (reconcile.Result, error) {
+
 	namespaceFound := kubernetes.NewNamespace(workshop, r.Scheme, ARGOCD_NAMESPACE_NAME)
-	if err := r.Get(context.TODO(), types.NamespacedName{Name: ARGOCD_NAMESPACE_NAME}, namespaceFound); err != nil {
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: ARGOCD_NAMESPACE_NAME}, namespaceFound); err != nil && errors.IsNotFound(err) {
+		return reconcile.Result{}, nil
+	} else if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	if namespaceFound.Spec.Finalizers[0] == "kubernetes" {
-		argoCD := &argocdoperatorv1.ArgoCD{}
-		if err := r.Get(context.TODO(), types.NamespacedName{Name: ARGOCD_CUSTOMRESOURCE_NAME, Namespace: ARGOCD_NAMESPACE_NAME}, argoCD); err != nil {
+	// Delete a Project
+	if namespaceFound.DeletionTimestamp == nil {
+		if err := r.Delete(context.TODO(), namespaceFound); err != nil && !errors.IsNotFound(err) {
 			return reconcile.Result{}, err
 		}
-
-		patch := client.MergeFrom(argoCD.DeepCopy())
-		argoCD.Finalizers = nil
-		if err := r.Patch(context.TODO(), argoCD, patch); err != nil {
+		log.Infof("Deleting %s  Project", namespaceFound.Name)
+	}
+	if len(namespaceFound.Spec.Finalizers) > 0 && namespaceFound.Spec.Finalizers[0] == "kubernetes" {
+		argoCD := &argocdoperatorv1.ArgoCD{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: ARGOCD_CUSTOMRESOURCE_NAME, Namespace: ARGOCD_NAMESPACE_NAME}, argoCD); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
 			return reconcile.Result{}, err
+		} else if err == nil {
+			patch := client.MergeFrom(argoCD.DeepCopy())
+			argoCD.Finalizers = nil
+			if err := r.Patch(context.TODO(), argoCD, patch); err != nil {
+				return reconcile.Result{}, err
+			}
 		}
 	}
-	log.Infof("Deleted %s  Project", namespace.Name)
+	log.Infof("Deleted %s  Project", namespaceFound.Name)
 
 	//Success
 	return reconcile.Result{}, nil
@@ -462,7 +965,7 @@ func (r *WorkshopReconciler) deleteArgocdDefaultClusterConfigSecret(workshop *wo
 
 	clusterConfigSecret := kubernetes.NewStringDataSecret(workshop, r.Scheme, ARGOCD_CONFIG_SECRET_NAME, namespaceName, labels, clusterConfigSecretData)
 	// delete cluster Config Secret
-	if err := r.Delete(context.TODO(), clusterConfigSecret); err != nil {
+	if err := r.Delete(context.TODO(), clusterConfigSecret); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s  Secret", clusterConfigSecret.Name)
@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/common/log"
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/git"
+	"github.com/stakater/workshop-operator/common/kubernetes"
+	"github.com/stakater/workshop-operator/common/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	ARGOCD_SECRET_TYPE_LABEL          = "argocd.argoproj.io/secret-type"
+	ARGOCD_SECRET_TYPE_REPO_CREDS     = "repo-creds"
+	ARGOCD_GITHUB_REPO_CREDS_NAME     = "github-repo-creds"
+	ARGOCD_GITHUB_REPO_CREDS_USERNAME = "workshop-operator"
+)
+
+// gitOpsRepoCredsName returns the name of the credential template of the repositories of a user
+func gitOpsRepoCredsName(username string) string {
+	return username + "-repo-creds"
+}
+
+// gitRepositoriesPrefix returns the URL prefix of the repositories of the owner as seen from the cluster
+func gitRepositoriesPrefix(workshop *workshopv1.Workshop, owner string) string {
+	return fmt.Sprintf("%s/%s/", strings.TrimSuffix(gitInternalURL(workshop), "/"), owner)
+}
+
+// Add the Argo CD credential templates of the Git repositories of the users, matching them by URL prefix. On
+// Gitea and GitLab every user gets a template with its own account. On GitHub the repositories of all the users
+// share the organization, which gets one template with the admin token, not created with perUser Argo CD
+// instances as the users could read it.
+func (r *WorkshopReconciler) addGitOpsRepositoryCredentials(workshop *workshopv1.Workshop, users int) (reconcile.Result, error) {
+
+	labels := map[string]string{
+		"app.kubernetes.io/part-of": "argocd",
+		"app.kubernetes.io/name":    "repo-creds",
+		ARGOCD_SECRET_TYPE_LABEL:    ARGOCD_SECRET_TYPE_REPO_CREDS,
+	}
+
+	secretNames := map[types.NamespacedName]bool{}
+	namespaces := []string{ARGOCD_NAMESPACE_NAME}
+
+	if git.ProviderName(workshop) == git.ProviderGitHub {
+		if isGitOpsPerUser(workshop) {
+			log.Warnf("GitHub repositories are not registered in the Argo CD of every user, their token being shared")
+		} else {
+			token, err := r.getGitAdminToken(workshop)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			repoCredsData := map[string]string{
+				"type":     "git",
+				"url":      gitRepositoriesPrefix(workshop, workshop.Spec.Infrastructure.Git.Organization),
+				"username": ARGOCD_GITHUB_REPO_CREDS_USERNAME,
+				"password": token,
+			}
+			repoCreds := kubernetes.NewStringDataSecret(workshop, r.Scheme, ARGOCD_GITHUB_REPO_CREDS_NAME, ARGOCD_NAMESPACE_NAME, labels, repoCredsData)
+			if result, err := r.addSecret(repoCreds, stringDataToData(repoCredsData)); util.IsRequeued(result, err) {
+				return result, err
+			}
+			secretNames[types.NamespacedName{Name: repoCreds.Name, Namespace: repoCreds.Namespace}] = true
+		}
+	} else {
+		for id := 1; id <= users; id++ {
+			username := fmt.Sprintf("user%d", id)
+			projectName := fmt.Sprintf("%s%d", workshop.Spec.Infrastructure.Project.StagingName, id)
+			namespace := gitOpsNamespace(workshop, projectName)
+			if !util.StringInSlice(namespace, namespaces) {
+				namespaces = append(namespaces, namespace)
+			}
+
+			repoCredsData := map[string]string{
+				"type":     "git",
+				"url":      gitRepositoriesPrefix(workshop, git.RepositoryOwner(workshop, username)),
+				"username": username,
+				"password": workshop.Spec.User.Password,
+			}
+			repoCreds := kubernetes.NewStringDataSecret(workshop, r.Scheme, gitOpsRepoCredsName(username), namespace, labels, repoCredsData)
+			if result, err := r.addSecret(repoCreds, stringDataToData(repoCredsData)); util.IsRequeued(result, err) {
+				return result, err
+			}
+			secretNames[types.NamespacedName{Name: repoCreds.Name, Namespace: repoCreds.Namespace}] = true
+		}
+	}
+
+	// Delete the credential templates of the users gone
+	for _, namespace := range namespaces {
+		secretList := &corev1.SecretList{}
+		if err := r.List(context.TODO(), secretList, client.InNamespace(namespace), client.MatchingLabels(labels)); err != nil {
+			return reconcile.Result{}, err
+		}
+		for i := range secretList.Items {
+			secret := &secretList.Items[i]
+			if secretNames[types.NamespacedName{Name: secret.Name, Namespace: namespace}] {
+				continue
+			}
+			if err := r.Delete(context.TODO(), secret); err != nil && !errors.IsNotFound(err) {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Deleted %s Secret in %s", secret.Name, namespace)
+		}
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
@@ -0,0 +1,214 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/prometheus/common/log"
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/codeready"
+	"github.com/stakater/workshop-operator/common/imagepuller"
+	"github.com/stakater/workshop-operator/common/kubernetes"
+	"github.com/stakater/workshop-operator/common/util"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	IMAGE_PULLER_NAMESPACE_NAME = "workshop-image-puller"
+	IMAGE_PULLER_PULLING        = "Pulling"
+	IMAGE_PULLER_COMPLETED      = "Completed"
+)
+
+// Reconciling ImagePuller
+func (r *WorkshopReconciler) reconcileImagePuller(workshop *workshopv1.Workshop, appsHostnameSuffix string) (reconcile.Result, error) {
+	enabled := workshop.Spec.Infrastructure.ImagePuller.Enabled
+
+	if enabled {
+		if result, err := r.addImagePuller(workshop, appsHostnameSuffix); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// Add ImagePuller, its DaemonSet being watched to record the progress of the pulls without blocking the other
+// components
+func (r *WorkshopReconciler) addImagePuller(workshop *workshopv1.Workshop, appsHostnameSuffix string) (reconcile.Result, error) {
+
+	images, err := r.getWorkshopImages(workshop, appsHostnameSuffix)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Create Project
+	namespace := kubernetes.NewNamespace(workshop, r.Scheme, IMAGE_PULLER_NAMESPACE_NAME)
+	if err := r.Create(context.TODO(), namespace); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Project", namespace.Name)
+	}
+
+	// Copy Pull Secrets
+	for _, secretName := range workshop.Spec.Infrastructure.ImagePuller.ImagePullSecrets {
+		secretFound := &corev1.Secret{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: workshop.Namespace}, secretFound); err != nil {
+			return reconcile.Result{}, err
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName,
+				Namespace: IMAGE_PULLER_NAMESPACE_NAME,
+				Labels:    map[string]string{"app.kubernetes.io/part-of": "image-puller"},
+			},
+			Type: secretFound.Type,
+			Data: secretFound.Data,
+		}
+		if result, err := r.addSecret(secret, secretFound.Data); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
+
+	if len(images) == 0 {
+		return reconcile.Result{}, r.updateImagePullerStatus(workshop, workshopv1.ImagePullerStatus{Phase: IMAGE_PULLER_COMPLETED})
+	}
+
+	checksum, err := imagepuller.ImagesChecksum(workshop, images)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Create/Update DaemonSet
+	labels := map[string]string{
+		"app":                       imagePullerName(workshop),
+		"app.kubernetes.io/part-of": "image-puller",
+	}
+	pullerImage := imageOrDefault(workshop.Spec.Infrastructure.ImagePuller.Image, imagepuller.DefaultImageName, imagepuller.DefaultImageTag)
+	daemonSet := imagepuller.NewDaemonSet(workshop, r.Scheme, imagePullerName(workshop), IMAGE_PULLER_NAMESPACE_NAME, labels,
+		pullerImage, images, checksum)
+	setWorkshopReference(workshop, daemonSet)
+	daemonSetFound := &appsv1.DaemonSet{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: daemonSet.Name, Namespace: IMAGE_PULLER_NAMESPACE_NAME}, daemonSetFound); err != nil && errors.IsNotFound(err) {
+		if err := r.Create(context.TODO(), daemonSet); err != nil {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Created %s DaemonSet pulling %d images", daemonSet.Name, len(images))
+		daemonSetFound = daemonSet
+	} else if err != nil {
+		return reconcile.Result{}, err
+	} else if daemonSetFound.Annotations[imagepuller.ImagesChecksumAnnotation] != checksum ||
+		daemonSetFound.Labels[WORKSHOP_MANAGED_BY_LABEL] != WORKSHOP_MANAGED_BY || !metav1.IsControlledBy(daemonSetFound, workshop) {
+		daemonSetFound.Labels = daemonSet.Labels
+		daemonSetFound.Annotations = daemonSet.Annotations
+		daemonSetFound.OwnerReferences = daemonSet.OwnerReferences
+		daemonSetFound.Spec.Template = daemonSet.Spec.Template
+		if err := r.Update(context.TODO(), daemonSetFound); err != nil {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Updated %s DaemonSet pulling %d images", daemonSetFound.Name, len(images))
+	}
+
+	// Record the progress, the pods being ready once their images are pulled
+	status := daemonSetFound.Status
+	phase := IMAGE_PULLER_PULLING
+	if status.ObservedGeneration >= daemonSetFound.Generation && status.DesiredNumberScheduled > 0 &&
+		status.UpdatedNumberScheduled == status.DesiredNumberScheduled && status.NumberReady == status.DesiredNumberScheduled {
+		phase = IMAGE_PULLER_COMPLETED
+	}
+	if err := r.updateImagePullerStatus(workshop, workshopv1.ImagePullerStatus{
+		Phase:        phase,
+		Images:       images,
+		NodesReady:   status.NumberReady,
+		NodesDesired: status.DesiredNumberScheduled,
+	}); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// getWorkshopImages returns the images of the devfile, the Bookbag, the plugin registry and of the spec
+func (r *WorkshopReconciler) getWorkshopImages(workshop *workshopv1.Workshop, appsHostnameSuffix string) ([]string, error) {
+
+	images := append([]string{}, workshop.Spec.Infrastructure.ImagePuller.Images...)
+
+	bookbag := workshop.Spec.Infrastructure.Guide.Bookbag
+	if bookbag.Enabled && bookbag.Image.Tag != "" {
+		images = append(images, bookbag.Image.Name+":"+bookbag.Image.Tag)
+	} else if bookbag.Enabled {
+		images = append(images, bookbag.Image.Name)
+	}
+
+	if workshop.Spec.Infrastructure.CodeReadyWorkspace.Enabled {
+		images = append(images, codeready.PluginRegistryImage(workshop))
+
+		devfile, _, err := r.getDevFile(workshop, appsHostnameSuffix)
+		if err != nil {
+			return nil, err
+		}
+		devfileImages, err := imagepuller.DevfileImages([]byte(devfile))
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, devfileImages...)
+	}
+
+	if workshop.Spec.Infrastructure.DevSpaces.Enabled {
+		devfile, err := r.getDevSpacesDevfile(workshop, appsHostnameSuffix)
+		if err != nil {
+			return nil, err
+		}
+		devfileImages, err := imagepuller.DevfileImages(devfile)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, devfileImages...)
+	}
+
+	return imagepuller.SortedImages(images), nil
+}
+
+// updateImagePullerStatus records the progress of the pulls when it changed
+func (r *WorkshopReconciler) updateImagePullerStatus(workshop *workshopv1.Workshop, imagePullerStatus workshopv1.ImagePullerStatus) error {
+	if reflect.DeepEqual(workshop.Status.ImagePuller, imagePullerStatus) {
+		return nil
+	}
+	if err := r.updateStatus(workshop, func(status *workshopv1.WorkshopStatus) {
+		status.ImagePuller = imagePullerStatus
+	}); err != nil {
+		return err
+	}
+	log.Infof("Images pulled on %d/%d nodes", imagePullerStatus.NodesReady, imagePullerStatus.NodesDesired)
+	return nil
+}
+
+// imagePullerName returns the name of the DaemonSet of the workshop, the namespace holding the ones of all the
+// workshops
+func imagePullerName(workshop *workshopv1.Workshop) string {
+	return workshop.Name + "-image-puller"
+}
+
+// Delete ImagePuller
+func (r *WorkshopReconciler) deleteImagePuller(workshop *workshopv1.Workshop) (reconcile.Result, error) {
+
+	daemonSetFound := &appsv1.DaemonSet{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: imagePullerName(workshop), Namespace: IMAGE_PULLER_NAMESPACE_NAME}, daemonSetFound); err == nil {
+		// Delete DaemonSet
+		if err := r.Delete(context.TODO(), daemonSetFound); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s DaemonSet", daemonSetFound.Name)
+	} else if !errors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
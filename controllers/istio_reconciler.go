@@ -0,0 +1,637 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/prometheus/common/log"
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/istio"
+	"github.com/stakater/workshop-operator/common/kubernetes"
+	"github.com/stakater/workshop-operator/common/maistra"
+	"github.com/stakater/workshop-operator/common/util"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	SERVICE_MESH_PROVIDER_ISTIO            = "istio"
+	UPSTREAM_ISTIO_INSTALLER_NAME          = "istio-installer"
+	UPSTREAM_ISTIO_UNINSTALLER_NAME        = "istio-uninstaller"
+	UPSTREAM_ISTIO_CONFIG_NAME             = "istio-controlplane"
+	UPSTREAM_ISTIOD_DEPLOYMENT_NAME        = "istiod"
+	UPSTREAM_ISTIO_INJECTION_LABEL         = "istio-injection"
+	UPSTREAM_ISTIO_PEER_AUTHENTICATION     = "default"
+	UPSTREAM_KIALI_CLUSTER_ROLE_NAME       = "istio-kiali"
+	UPSTREAM_KIALI_SIGNING_KEY_SECRET_NAME = "kiali-signing-key"
+	UPSTREAM_JAEGER_PROXY_NAME             = "jaeger"
+	UPSTREAM_PROMETHEUS_CLUSTER_ROLE_NAME  = "istio-prometheus"
+	UPSTREAM_ISTIO_INSTALLER_RETRY_SECONDS = 30
+	UPSTREAM_ISTIO_JAEGER_SAR              = `{"namespace":"%s","resource":"pods","verb":"get"}`
+)
+
+var upstreamIstioInstallerLabels = map[string]string{
+	"app.kubernetes.io/part-of": "istio",
+	"app.kubernetes.io/name":    UPSTREAM_ISTIO_INSTALLER_NAME,
+}
+
+// upstreamIstioAddonLabels returns the labels of an addon of upstream Istio
+func upstreamIstioAddonLabels(name string) map[string]string {
+	return map[string]string{
+		"app":                       name,
+		"app.kubernetes.io/name":    name,
+		"app.kubernetes.io/part-of": "istio",
+	}
+}
+
+// isServiceMeshIstio returns true if the mesh is upstream Istio instead of OpenShift Service Mesh
+func isServiceMeshIstio(workshop *workshopv1.Workshop) bool {
+	return workshop.Spec.Infrastructure.ServiceMesh.Provider == SERVICE_MESH_PROVIDER_ISTIO
+}
+
+// Add upstream Istio, installed by istioctl in a Job run by the Workshop Operator, with Kiali and Jaeger
+func (r *WorkshopReconciler) addIstio(workshop *workshopv1.Workshop, users int, appsHostnameSuffix string) (reconcile.Result, error) {
+
+	if isServiceMeshPerUser(workshop) {
+		log.Warnf("The perUser tenancy is only supported by the maistra provider, Istio being shared")
+	}
+
+	// Create Namespace
+	namespace := kubernetes.NewNamespace(workshop, r.Scheme, ISTIO_NAMESPACE_NAME)
+	if err := r.Create(context.TODO(), namespace); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Namespace", namespace.Name)
+	}
+
+	// Create the Service Account of the installer
+	if result, err := r.addIstioInstallerServiceAccount(workshop); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	// Create/Update ConfigMap
+	istioOperatorConfig := istio.NewIstioOperatorConfig(workshop, UPSTREAM_ISTIO_CONFIG_NAME, ISTIO_NAMESPACE_NAME)
+	checksum, err := kubernetes.Checksum(istioOperatorConfig.Object)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	config, err := yaml.Marshal(istioOperatorConfig.Object)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	configMap := kubernetes.NewConfigMap(workshop, r.Scheme, UPSTREAM_ISTIO_CONFIG_NAME, ISTIO_NAMESPACE_NAME, istioLabels,
+		map[string]string{istio.ConfigKey: string(config)})
+	if err := r.Create(context.TODO(), configMap); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s ConfigMap", configMap.Name)
+	} else if errors.IsAlreadyExists(err) {
+		configMapFound := &corev1.ConfigMap{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: configMap.Name, Namespace: ISTIO_NAMESPACE_NAME}, configMapFound); err != nil {
+			return reconcile.Result{}, err
+		} else if !reflect.DeepEqual(configMap.Data, configMapFound.Data) {
+			configMapFound.Data = configMap.Data
+			if err := r.Update(context.TODO(), configMapFound); err != nil {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Updated %s ConfigMap", configMapFound.Name)
+		}
+	}
+
+	// Install the control plane, again when its configuration changes
+	if result, err := r.runIstioctl(workshop, UPSTREAM_ISTIO_INSTALLER_NAME, checksum, istio.InstallArgs()); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	// Wait for Istio to be running
+	if !kubernetes.GetK8Client().GetDeploymentStatus(UPSTREAM_ISTIOD_DEPLOYMENT_NAME, ISTIO_NAMESPACE_NAME) {
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	// Create/Delete the mesh-wide mTLS policy
+	peerAuthentication := istio.NewPeerAuthenticationCR(workshop, r.Scheme, UPSTREAM_ISTIO_PEER_AUTHENTICATION, ISTIO_NAMESPACE_NAME, istioLabels)
+	if workshop.Spec.Infrastructure.ServiceMesh.ControlPlane.MTLSMode == maistra.MTLSModeStrict {
+		if err := r.Create(context.TODO(), peerAuthentication); err != nil && !errors.IsAlreadyExists(err) {
+			return reconcile.Result{}, err
+		} else if err == nil {
+			log.Infof("Created %s Peer Authentication Custom Resource", peerAuthentication.GetName())
+		}
+	} else if err := r.Delete(context.TODO(), peerAuthentication); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Deleted %s Peer Authentication Custom Resource", peerAuthentication.GetName())
+	}
+
+	// Deploy the addons
+	if result, err := r.addIstioPrometheus(workshop); util.IsRequeued(result, err) {
+		return result, err
+	}
+	if maistra.JaegerEnabled(workshop) {
+		if result, err := r.addIstioJaeger(workshop, appsHostnameSuffix); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
+	if maistra.KialiEnabled(workshop) {
+		if result, err := r.addIstioKiali(workshop, appsHostnameSuffix); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
+
+	istioMembers := []string{}
+	istioUsers := []rbac.Subject{}
+	for id := 1; id <= users; id++ {
+		istioMembers = append(istioMembers, fmt.Sprintf("%s%d", workshop.Spec.Infrastructure.Project.StagingName, id))
+		istioUsers = append(istioUsers, rbac.Subject{
+			Kind:     rbac.UserKind,
+			Name:     fmt.Sprintf("user%d", id),
+			APIGroup: "rbac.authorization.k8s.io",
+		})
+	}
+	for _, member := range workshop.Spec.Infrastructure.ServiceMesh.ControlPlane.Members {
+		if !util.StringInSlice(member, istioMembers) {
+			istioMembers = append(istioMembers, member)
+		}
+	}
+
+	// Enable the sidecar injection in the members, the ones not created yet being labeled later on
+	for _, member := range istioMembers {
+		namespaceFound := &corev1.Namespace{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: member}, namespaceFound); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		} else if errors.IsNotFound(err) {
+			continue
+		}
+
+		// The injected pods run the Istio CNI plugin through Multus
+		networkAttachmentDefinition := istio.NewNetworkAttachmentDefinitionCR(workshop, r.Scheme, member, istioLabels)
+		if err := r.Create(context.TODO(), networkAttachmentDefinition); err != nil && !errors.IsAlreadyExists(err) {
+			return reconcile.Result{}, err
+		} else if err == nil {
+			log.Infof("Created %s Network Attachment Definition in %s Namespace", networkAttachmentDefinition.GetName(), member)
+		}
+
+		if namespaceFound.Labels[UPSTREAM_ISTIO_INJECTION_LABEL] != "enabled" {
+			patch := client.MergeFrom(namespaceFound.DeepCopy())
+			if namespaceFound.Labels == nil {
+				namespaceFound.Labels = map[string]string{}
+			}
+			namespaceFound.Labels[UPSTREAM_ISTIO_INJECTION_LABEL] = "enabled"
+			if err := r.Patch(context.TODO(), namespaceFound, patch); err != nil {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Enabled the sidecar injection in %s Namespace", namespaceFound.Name)
+		}
+	}
+
+	// Create the roles of the users, as with OpenShift Service Mesh
+	jaegerRole := kubernetes.NewRole(workshop, r.Scheme,
+		JAEGER_ROLE_NAME, ISTIO_NAMESPACE_NAME, istioLabels, kubernetes.JaegerUserRules())
+	if err := r.Create(context.TODO(), jaegerRole); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Role", jaegerRole.Name)
+	}
+
+	roleBindings := []*rbac.RoleBinding{
+		kubernetes.NewRoleBindingUsers(workshop, r.Scheme,
+			JAEGER_ROLE_BINDING_NAME, ISTIO_NAMESPACE_NAME, istioLabels, istioUsers, jaegerRole.Name, JAEGER_ROLE_KIND_NAME),
+		kubernetes.NewRoleBindingUsers(workshop, r.Scheme,
+			SERVICE_MESH_VIEW_ROLE_BINDING_NAME, ISTIO_NAMESPACE_NAME, istioLabels, istioUsers, SERVICE_MESH_VIEW_CLUSTER_ROLE_NAME, SERVICE_MESH_VIEW_CLUSTER_ROLE_KIND),
+	}
+	for _, roleBinding := range roleBindings {
+		if err := r.Create(context.TODO(), roleBinding); err != nil && !errors.IsAlreadyExists(err) {
+			return reconcile.Result{}, err
+		} else if err == nil {
+			log.Infof("Created %s Role Binding", roleBinding.Name)
+		} else if errors.IsAlreadyExists(err) {
+			found := &rbac.RoleBinding{}
+			if err := r.Get(context.TODO(), types.NamespacedName{Name: roleBinding.Name, Namespace: ISTIO_NAMESPACE_NAME}, found); err != nil {
+				return reconcile.Result{}, err
+			} else if !reflect.DeepEqual(roleBinding.Subjects, found.Subjects) {
+				found.Subjects = roleBinding.Subjects
+				if err := r.Update(context.TODO(), found); err != nil {
+					return reconcile.Result{}, err
+				}
+				log.Infof("Updated %s Role Binding", found.Name)
+			}
+		}
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// Add the Service Account of istioctl, allowed to install and uninstall the components of the control plane
+func (r *WorkshopReconciler) addIstioInstallerServiceAccount(workshop *workshopv1.Workshop) (reconcile.Result, error) {
+
+	// Create Service Account
+	serviceAccount := kubernetes.NewServiceAccount(workshop, r.Scheme, UPSTREAM_ISTIO_INSTALLER_NAME, ISTIO_NAMESPACE_NAME, upstreamIstioInstallerLabels)
+	if err := r.Create(context.TODO(), serviceAccount); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Service Account", serviceAccount.Name)
+	}
+
+	// Create Cluster Role
+	clusterRole := kubernetes.NewClusterRole(workshop, r.Scheme, UPSTREAM_ISTIO_INSTALLER_NAME, ISTIO_NAMESPACE_NAME, upstreamIstioInstallerLabels, istio.NewRules())
+	if err := r.Create(context.TODO(), clusterRole); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Cluster Role", clusterRole.Name)
+	}
+
+	// Create Cluster Role Binding
+	clusterRoleBinding := kubernetes.NewClusterRoleBindingSA(workshop, r.Scheme, UPSTREAM_ISTIO_INSTALLER_NAME, ISTIO_NAMESPACE_NAME,
+		upstreamIstioInstallerLabels, serviceAccount.Name, clusterRole.Name, "ClusterRole")
+	if err := r.Create(context.TODO(), clusterRoleBinding); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Cluster Role Binding", clusterRoleBinding.Name)
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// runIstioctl runs istioctl with args in a Job until it completes, the Job being replaced when the checksum of the
+// configuration changes and retried when it fails
+func (r *WorkshopReconciler) runIstioctl(workshop *workshopv1.Workshop, name string, checksum string, args []string) (reconcile.Result, error) {
+
+	job := istio.NewInstallerJob(workshop, r.Scheme, name, ISTIO_NAMESPACE_NAME, upstreamIstioInstallerLabels,
+		UPSTREAM_ISTIO_INSTALLER_NAME, UPSTREAM_ISTIO_CONFIG_NAME, checksum, args)
+	setWorkshopReference(workshop, job)
+
+	jobFound := &batchv1.Job{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: job.Name, Namespace: ISTIO_NAMESPACE_NAME}, jobFound); err != nil && errors.IsNotFound(err) {
+		if err := r.Create(context.TODO(), job); err != nil {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Created %s Job", job.Name)
+		return reconcile.Result{Requeue: true}, nil
+	} else if err != nil {
+		return reconcile.Result{}, err
+	} else if jobFound.Annotations[kubernetes.ChecksumAnnotation] != checksum {
+		if err := r.Delete(context.TODO(), jobFound, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s Job of a previous configuration", jobFound.Name)
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	for _, condition := range jobFound.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		if condition.Type == batchv1.JobComplete {
+			//Success
+			return reconcile.Result{}, nil
+		} else if condition.Type == batchv1.JobFailed {
+			// Retry after a while, istioctl failing when the cluster is not ready yet
+			if err := r.Delete(context.TODO(), jobFound, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+				return reconcile.Result{}, err
+			}
+			log.Warnf("Deleted the failed %s Job, retrying in %d seconds", jobFound.Name, UPSTREAM_ISTIO_INSTALLER_RETRY_SECONDS)
+			return reconcile.Result{RequeueAfter: time.Second * UPSTREAM_ISTIO_INSTALLER_RETRY_SECONDS}, nil
+		}
+	}
+
+	// Wait for the Job, which is watched
+	return reconcile.Result{Requeue: true}, nil
+}
+
+// addIstioAddonDeployment creates a Deployment of an addon, updated when its image or its configuration changes
+func (r *WorkshopReconciler) addIstioAddonDeployment(deployment *appsv1.Deployment) (reconcile.Result, error) {
+
+	if err := r.Create(context.TODO(), deployment); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Deployment", deployment.Name)
+	} else if errors.IsAlreadyExists(err) {
+		deploymentFound := &appsv1.Deployment{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, deploymentFound); err != nil {
+			return reconcile.Result{}, err
+		} else if deployment.Spec.Template.Spec.Containers[0].Image != deploymentFound.Spec.Template.Spec.Containers[0].Image ||
+			deployment.Spec.Template.Annotations[kubernetes.ChecksumAnnotation] != deploymentFound.Spec.Template.Annotations[kubernetes.ChecksumAnnotation] {
+			deploymentFound.Spec = deployment.Spec
+			if err := r.Update(context.TODO(), deploymentFound); err != nil {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Updated %s Deployment", deploymentFound.Name)
+		}
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// addIstioAddonService creates a Service of an addon and the NetworkPolicy only letting the from pods reach its
+// port, its openPorts staying reachable from the mesh
+func (r *WorkshopReconciler) addIstioAddonService(workshop *workshopv1.Workshop, name string, labels map[string]string,
+	portName string, port int32, from []map[string]string, openPorts []int32) (reconcile.Result, error) {
+
+	// Create Service
+	service := kubernetes.NewService(workshop, r.Scheme, name, ISTIO_NAMESPACE_NAME, labels, []string{portName}, []int32{port})
+	if err := r.Create(context.TODO(), service); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Service", service.Name)
+	}
+
+	// Create Network Policy
+	networkPolicy := kubernetes.NewNetworkPolicy(workshop, r.Scheme, name, ISTIO_NAMESPACE_NAME, labels, labels, []int32{port}, from, nil, nil, openPorts)
+	if err := r.Create(context.TODO(), networkPolicy); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Network Policy", networkPolicy.Name)
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// addIstioAddonRoute deploys the SSO proxy in front of an addon and its Route
+func (r *WorkshopReconciler) addIstioAddonRoute(workshop *workshopv1.Workshop, name string, upstream string,
+	appsHostnameSuffix string, extraArgs []string) (reconcile.Result, error) {
+
+	host := fmt.Sprintf("%s-%s.%s", name, ISTIO_NAMESPACE_NAME, appsHostnameSuffix)
+	if result, err := r.addSSOProxy(workshop, name, ISTIO_NAMESPACE_NAME, upstream, host, nil, extraArgs); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	// Create Route
+	route := kubernetes.NewSecuredRoute(workshop, r.Scheme, name, ISTIO_NAMESPACE_NAME, istioLabels, name, kubernetes.OAuthProxyPort)
+	if err := r.Create(context.TODO(), route); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Route", route.Name)
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// Add the Prometheus of the mesh, only reachable by Kiali
+func (r *WorkshopReconciler) addIstioPrometheus(workshop *workshopv1.Workshop) (reconcile.Result, error) {
+
+	labels := upstreamIstioAddonLabels(istio.PrometheusName)
+
+	// Create Service Account
+	serviceAccount := kubernetes.NewServiceAccount(workshop, r.Scheme, istio.PrometheusName, ISTIO_NAMESPACE_NAME, labels)
+	if err := r.Create(context.TODO(), serviceAccount); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Service Account", serviceAccount.Name)
+	}
+
+	// Create Cluster Role
+	clusterRole := kubernetes.NewClusterRole(workshop, r.Scheme, UPSTREAM_PROMETHEUS_CLUSTER_ROLE_NAME, ISTIO_NAMESPACE_NAME, labels, istio.PrometheusRules())
+	if err := r.Create(context.TODO(), clusterRole); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Cluster Role", clusterRole.Name)
+	}
+
+	// Create Cluster Role Binding
+	clusterRoleBinding := kubernetes.NewClusterRoleBindingSA(workshop, r.Scheme, UPSTREAM_PROMETHEUS_CLUSTER_ROLE_NAME, ISTIO_NAMESPACE_NAME,
+		labels, serviceAccount.Name, clusterRole.Name, "ClusterRole")
+	if err := r.Create(context.TODO(), clusterRoleBinding); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Cluster Role Binding", clusterRoleBinding.Name)
+	}
+
+	// Create ConfigMap
+	configMap := kubernetes.NewConfigMap(workshop, r.Scheme, istio.PrometheusName, ISTIO_NAMESPACE_NAME, labels,
+		map[string]string{istio.PrometheusConfigKey: istio.PrometheusConfig})
+	if err := r.Create(context.TODO(), configMap); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s ConfigMap", configMap.Name)
+	}
+
+	// Deploy/Update Prometheus
+	checksum, err := kubernetes.Checksum(configMap.Data)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	deployment := istio.NewPrometheusDeployment(workshop, r.Scheme, istio.PrometheusName, ISTIO_NAMESPACE_NAME, labels,
+		serviceAccount.Name, configMap.Name, checksum)
+	if result, err := r.addIstioAddonDeployment(deployment); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	if result, err := r.addIstioAddonService(workshop, istio.PrometheusName, labels, "http", istio.PrometheusPort,
+		[]map[string]string{upstreamIstioAddonLabels(istio.KialiName)}, nil); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// Add Jaeger, its UI being behind the SSO proxy and only allowed to the users reading the pods of the namespace
+func (r *WorkshopReconciler) addIstioJaeger(workshop *workshopv1.Workshop, appsHostnameSuffix string) (reconcile.Result, error) {
+
+	labels := upstreamIstioAddonLabels(istio.JaegerName)
+
+	// Deploy/Update Jaeger
+	deployment := istio.NewJaegerDeployment(workshop, r.Scheme, istio.JaegerName, ISTIO_NAMESPACE_NAME, labels)
+	if result, err := r.addIstioAddonDeployment(deployment); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	// Create the collector Service, reached by the sidecars
+	collector := kubernetes.NewService(workshop, r.Scheme, istio.JaegerCollectorName, ISTIO_NAMESPACE_NAME, labels,
+		[]string{"grpc-otlp"}, []int32{istio.JaegerCollectorPort})
+	if err := r.Create(context.TODO(), collector); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Service", collector.Name)
+	}
+
+	// Create the query Service, only reached by Kiali and the SSO proxy
+	if result, err := r.addIstioAddonService(workshop, istio.JaegerQueryName, labels, "http-query", istio.JaegerQueryPort,
+		[]map[string]string{upstreamIstioAddonLabels(istio.KialiName), ssoProxyLabels(UPSTREAM_JAEGER_PROXY_NAME)},
+		[]int32{istio.JaegerCollectorPort}); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	upstream := fmt.Sprintf("http://%s.%s.svc:%d", istio.JaegerQueryName, ISTIO_NAMESPACE_NAME, istio.JaegerQueryPort)
+	if result, err := r.addIstioAddonRoute(workshop, UPSTREAM_JAEGER_PROXY_NAME, upstream, appsHostnameSuffix,
+		[]string{"--openshift-sar=" + fmt.Sprintf(UPSTREAM_ISTIO_JAEGER_SAR, ISTIO_NAMESPACE_NAME)}); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// Add Kiali, behind the SSO proxy passing the OpenShift token of the users, who only see the namespaces they can
+// access
+func (r *WorkshopReconciler) addIstioKiali(workshop *workshopv1.Workshop, appsHostnameSuffix string) (reconcile.Result, error) {
+
+	labels := upstreamIstioAddonLabels(istio.KialiName)
+
+	// Create Service Account
+	serviceAccount := kubernetes.NewServiceAccount(workshop, r.Scheme, istio.KialiName, ISTIO_NAMESPACE_NAME, labels)
+	if err := r.Create(context.TODO(), serviceAccount); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Service Account", serviceAccount.Name)
+	}
+
+	// Create Cluster Role
+	clusterRole := kubernetes.NewClusterRole(workshop, r.Scheme, UPSTREAM_KIALI_CLUSTER_ROLE_NAME, ISTIO_NAMESPACE_NAME, labels, istio.KialiRules())
+	if err := r.Create(context.TODO(), clusterRole); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Cluster Role", clusterRole.Name)
+	}
+
+	// Create Cluster Role Binding
+	clusterRoleBinding := kubernetes.NewClusterRoleBindingSA(workshop, r.Scheme, UPSTREAM_KIALI_CLUSTER_ROLE_NAME, ISTIO_NAMESPACE_NAME,
+		labels, serviceAccount.Name, clusterRole.Name, "ClusterRole")
+	if err := r.Create(context.TODO(), clusterRoleBinding); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Cluster Role Binding", clusterRoleBinding.Name)
+	}
+
+	// Create Secret, generated once
+	secretFound := &corev1.Secret{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: UPSTREAM_KIALI_SIGNING_KEY_SECRET_NAME, Namespace: ISTIO_NAMESPACE_NAME}, secretFound); err != nil && errors.IsNotFound(err) {
+		signingKey, err := util.GeneratePassword(32)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		secret := kubernetes.NewStringDataSecret(workshop, r.Scheme, UPSTREAM_KIALI_SIGNING_KEY_SECRET_NAME, ISTIO_NAMESPACE_NAME, labels,
+			map[string]string{istio.KialiSigningKey: signingKey})
+		if err := r.Create(context.TODO(), secret); err != nil {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Created %s Secret", secret.Name)
+	} else if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Create/Update ConfigMap
+	jaegerURL := fmt.Sprintf("https://%s-%s.%s", UPSTREAM_JAEGER_PROXY_NAME, ISTIO_NAMESPACE_NAME, appsHostnameSuffix)
+	config, err := istio.NewKialiConfig(workshop, ISTIO_NAMESPACE_NAME, jaegerURL)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	configMap := kubernetes.NewConfigMap(workshop, r.Scheme, istio.KialiName, ISTIO_NAMESPACE_NAME, labels,
+		map[string]string{istio.KialiConfigKey: config})
+	if err := r.Create(context.TODO(), configMap); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s ConfigMap", configMap.Name)
+	} else if errors.IsAlreadyExists(err) {
+		configMapFound := &corev1.ConfigMap{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: configMap.Name, Namespace: ISTIO_NAMESPACE_NAME}, configMapFound); err != nil {
+			return reconcile.Result{}, err
+		} else if !reflect.DeepEqual(configMap.Data, configMapFound.Data) {
+			configMapFound.Data = configMap.Data
+			if err := r.Update(context.TODO(), configMapFound); err != nil {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Updated %s ConfigMap", configMapFound.Name)
+		}
+	}
+
+	// Deploy/Update Kiali, restarted when its configuration changes
+	checksum, err := kubernetes.Checksum(configMap.Data)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	deployment := istio.NewKialiDeployment(workshop, r.Scheme, istio.KialiName, ISTIO_NAMESPACE_NAME, labels,
+		serviceAccount.Name, configMap.Name, UPSTREAM_KIALI_SIGNING_KEY_SECRET_NAME, checksum)
+	if result, err := r.addIstioAddonDeployment(deployment); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	if result, err := r.addIstioAddonService(workshop, istio.KialiName, labels, "http", istio.KialiPort,
+		[]map[string]string{ssoProxyLabels(KIALI_NAME)}, nil); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	// The proxy passes the OpenShift token of the users, with the scope of their own permissions
+	upstream := fmt.Sprintf("http://%s.%s.svc:%d", istio.KialiName, ISTIO_NAMESPACE_NAME, istio.KialiPort)
+	if result, err := r.addIstioAddonRoute(workshop, KIALI_NAME, upstream, appsHostnameSuffix,
+		[]string{"--pass-user-bearer-token=true", "--scope=user:full"}); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// Delete upstream Istio
+func (r *WorkshopReconciler) deleteIstio(workshop *workshopv1.Workshop) (reconcile.Result, error) {
+
+	// Delete OAuth Clients
+	for _, name := range []string{KIALI_NAME, UPSTREAM_JAEGER_PROXY_NAME} {
+		if result, err := r.deleteSSOProxy(workshop, name, ISTIO_NAMESPACE_NAME); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
+
+	// Uninstall the control plane, unless its namespace is already gone
+	namespaceFound := &corev1.Namespace{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: ISTIO_NAMESPACE_NAME}, namespaceFound); err != nil && !errors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	} else if err == nil && namespaceFound.DeletionTimestamp == nil {
+		installer := &batchv1.Job{}
+		installer.SetName(UPSTREAM_ISTIO_INSTALLER_NAME)
+		installer.SetNamespace(ISTIO_NAMESPACE_NAME)
+		if err := r.Delete(context.TODO(), installer, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+
+		if result, err := r.addIstioInstallerServiceAccount(workshop); util.IsRequeued(result, err) {
+			return result, err
+		}
+		if result, err := r.runIstioctl(workshop, UPSTREAM_ISTIO_UNINSTALLER_NAME, "", istio.UninstallArgs()); util.IsRequeued(result, err) {
+			return result, err
+		}
+		log.Infof("Uninstalled Istio")
+	}
+
+	// Delete Cluster Role Bindings and Cluster Roles
+	for _, name := range []string{UPSTREAM_ISTIO_INSTALLER_NAME, UPSTREAM_KIALI_CLUSTER_ROLE_NAME, UPSTREAM_PROMETHEUS_CLUSTER_ROLE_NAME} {
+		clusterRoleBinding := &rbac.ClusterRoleBinding{}
+		clusterRoleBinding.SetName(name)
+		if err := r.Delete(context.TODO(), clusterRoleBinding); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s Cluster Role Binding", clusterRoleBinding.Name)
+
+		clusterRole := &rbac.ClusterRole{}
+		clusterRole.SetName(name)
+		if err := r.Delete(context.TODO(), clusterRole); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s Cluster Role", clusterRole.Name)
+	}
+
+	// Delete Namespace, with the addons and the roles of the users
+	namespace := kubernetes.NewNamespace(workshop, r.Scheme, ISTIO_NAMESPACE_NAME)
+	if err := r.Delete(context.TODO(), namespace); err != nil && !errors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+	log.Infof("Deleted %s Namespace", namespace.Name)
+
+	//Success
+	return reconcile.Result{}, nil
+}
@@ -0,0 +1,404 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/prometheus/common/log"
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/kafka"
+	"github.com/stakater/workshop-operator/common/kubernetes"
+	"github.com/stakater/workshop-operator/common/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	KAFKA_OPERATOR_STRIMZI                      = "strimzi"
+	KAFKA_MODE_PER_USER                         = "perUser"
+	KAFKA_SUBSCRIPTION_NAMESPACE_NAME           = "openshift-operators"
+	KAFKA_AMQ_STREAMS_SUBSCRIPTION_NAME         = "amq-streams"
+	KAFKA_AMQ_STREAMS_SUBSCRIPTION_PACKAGE_NAME = "amq-streams"
+	KAFKA_STRIMZI_SUBSCRIPTION_NAME             = "strimzi-kafka-operator"
+	KAFKA_STRIMZI_SUBSCRIPTION_PACKAGE_NAME     = "strimzi-kafka-operator"
+	KAFKA_NAMESPACE_NAME                        = "kafka"
+	KAFKA_CLUSTER_NAME                          = "workshop"
+	KAFKA_CREDENTIALS_SECRET_NAME               = "kafka-credentials"
+	KAFKA_SECURITY_PROTOCOL                     = "SASL_PLAINTEXT"
+	KAFKA_SASL_MECHANISM                        = "SCRAM-SHA-512"
+)
+
+var kafkaLabels = map[string]string{
+	"app.kubernetes.io/part-of": "kafka",
+}
+
+var kafkaTopicLabels = map[string]string{
+	"app.kubernetes.io/part-of": "kafka",
+	"app.kubernetes.io/name":    "kafka-topic",
+}
+
+var kafkaUserLabels = map[string]string{
+	"app.kubernetes.io/part-of": "kafka",
+	"app.kubernetes.io/name":    "kafka-user",
+}
+
+// isKafkaPerUser returns true when every user has its own Kafka cluster in its staging project
+func isKafkaPerUser(workshop *workshopv1.Workshop) bool {
+	return workshop.Spec.Infrastructure.Kafka.Mode == KAFKA_MODE_PER_USER
+}
+
+// kafkaClusterNamespace returns the namespace of the Kafka cluster used by the staging project of a user
+func kafkaClusterNamespace(workshop *workshopv1.Workshop, projectName string) string {
+	if isKafkaPerUser(workshop) {
+		return projectName
+	}
+	return KAFKA_NAMESPACE_NAME
+}
+
+// newKafkaSubscription returns the Subscription of the AMQ Streams operator, or of the Strimzi one
+func (r *WorkshopReconciler) newKafkaSubscription(workshop *workshopv1.Workshop) *olmv1alpha1.Subscription {
+
+	channel := workshop.Spec.Infrastructure.Kafka.OperatorHub.Channel
+	clusterServiceVersion := workshop.Spec.Infrastructure.Kafka.OperatorHub.ClusterServiceVersion
+
+	if workshop.Spec.Infrastructure.Kafka.Operator == KAFKA_OPERATOR_STRIMZI {
+		return kubernetes.NewCommunitySubscription(workshop, r.Scheme, KAFKA_STRIMZI_SUBSCRIPTION_NAME, KAFKA_SUBSCRIPTION_NAMESPACE_NAME,
+			KAFKA_STRIMZI_SUBSCRIPTION_PACKAGE_NAME, channel, clusterServiceVersion)
+	}
+	return kubernetes.NewRedHatSubscription(workshop, r.Scheme, KAFKA_AMQ_STREAMS_SUBSCRIPTION_NAME, KAFKA_SUBSCRIPTION_NAMESPACE_NAME,
+		KAFKA_AMQ_STREAMS_SUBSCRIPTION_PACKAGE_NAME, channel, clusterServiceVersion)
+}
+
+// Reconciling Kafka
+func (r *WorkshopReconciler) reconcileKafka(workshop *workshopv1.Workshop, users int) (reconcile.Result, error) {
+	enabledKafka := workshop.Spec.Infrastructure.Kafka.Enabled
+
+	if enabledKafka {
+
+		if result, err := r.addKafka(workshop, users); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// Add Kafka
+func (r *WorkshopReconciler) addKafka(workshop *workshopv1.Workshop, users int) (reconcile.Result, error) {
+
+	clusterServiceVersion := workshop.Spec.Infrastructure.Kafka.OperatorHub.ClusterServiceVersion
+
+	subscription := r.newKafkaSubscription(workshop)
+	if err := r.Create(context.TODO(), subscription); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Subscription", subscription.Name)
+	}
+
+	if err := r.ApproveInstallPlan(clusterServiceVersion, subscription.Name, KAFKA_SUBSCRIPTION_NAMESPACE_NAME); err != nil {
+		log.Infof("Waiting for Subscription to create InstallPlan for %s", subscription.Name)
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	clusterNamespaces := []string{KAFKA_NAMESPACE_NAME}
+	if isKafkaPerUser(workshop) {
+		clusterNamespaces = []string{}
+		for id := 1; id <= users; id++ {
+			clusterNamespaces = append(clusterNamespaces, fmt.Sprintf("%s%d", workshop.Spec.Infrastructure.Project.StagingName, id))
+		}
+	} else {
+		namespace := kubernetes.NewNamespace(workshop, r.Scheme, KAFKA_NAMESPACE_NAME)
+		if err := r.Create(context.TODO(), namespace); err != nil && !errors.IsAlreadyExists(err) {
+			return reconcile.Result{}, err
+		} else if err == nil {
+			log.Infof("Created %s Namespace", namespace.Name)
+		}
+	}
+
+	// Create every cluster before waiting for them, so that the clusters of the users start together
+	clustersReady := true
+	for _, clusterNamespace := range clusterNamespaces {
+		kafkaFound, err := r.addKafkaCluster(workshop, clusterNamespace)
+		if err != nil && meta.IsNoMatchError(err) {
+			log.Infof("Waiting for the operator to install the %s Custom Resource Definition", kafka.KafkaGroupVersionKind.Kind)
+			return reconcile.Result{RequeueAfter: time.Second * 10}, nil
+		} else if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		if ready, message := kubernetes.ReadyCondition(kafkaFound); !ready {
+			log.Infof("Waiting for %s Kafka in %s namespace to be ready: %s", kafkaFound.GetName(), clusterNamespace, message)
+			clustersReady = false
+		}
+	}
+
+	// Wait for the clusters to be ready, their conditions not being watched
+	if !clustersReady {
+		return reconcile.Result{RequeueAfter: time.Second * 10}, nil
+	}
+
+	for id := 1; id <= users; id++ {
+		if result, err := r.addKafkaUser(workshop, id); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
+
+	// Delete the users and the topics removed from the shared cluster
+	if !isKafkaPerUser(workshop) {
+		usernames := []string{}
+		topicNames := []string{}
+		for id := 1; id <= users; id++ {
+			username := fmt.Sprintf("user%d", id)
+			usernames = append(usernames, username)
+			for _, topic := range workshop.Spec.Infrastructure.Kafka.Topics {
+				topicNames = append(topicNames, fmt.Sprintf("%s-%s", username, topic.Name))
+			}
+		}
+		if err := r.deleteKafkaCustomResources(kafka.KafkaUserGroupVersionKind, KAFKA_NAMESPACE_NAME, kafkaUserLabels, usernames); err != nil {
+			return reconcile.Result{}, err
+		}
+		if err := r.deleteKafkaCustomResources(kafka.KafkaTopicGroupVersionKind, KAFKA_NAMESPACE_NAME, kafkaTopicLabels, topicNames); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// Add the KafkaNodePool and the Kafka of a namespace, and returns the Kafka with its status
+func (r *WorkshopReconciler) addKafkaCluster(workshop *workshopv1.Workshop, namespace string) (*unstructured.Unstructured, error) {
+
+	kafkaNodePoolCR, err := kafka.NewKafkaNodePoolCR(workshop, r.Scheme, kafka.NodePoolName, namespace, kafkaLabels, KAFKA_CLUSTER_NAME)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.addKafkaCustomResource(kafkaNodePoolCR); err != nil {
+		return nil, err
+	}
+
+	kafkaCR, err := kafka.NewKafkaCR(workshop, r.Scheme, KAFKA_CLUSTER_NAME, namespace, kafkaLabels)
+	if err != nil {
+		return nil, err
+	}
+	return r.addKafkaCustomResource(kafkaCR)
+}
+
+// addKafkaCustomResource creates a Kafka or a KafkaNodePool, or replaces its spec when its configuration changed,
+// and returns it
+func (r *WorkshopReconciler) addKafkaCustomResource(customResource *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+
+	customResourceFound := &unstructured.Unstructured{}
+	customResourceFound.SetGroupVersionKind(customResource.GroupVersionKind())
+	if err := r.Create(context.TODO(), customResource); err != nil && !errors.IsAlreadyExists(err) {
+		return nil, err
+	} else if err == nil {
+		log.Infof("Created %s %s in %s namespace", customResource.GetName(), customResource.GetKind(), customResource.GetNamespace())
+		return customResource, nil
+	}
+
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: customResource.GetName(), Namespace: customResource.GetNamespace()}, customResourceFound); err != nil {
+		return nil, err
+	} else if customResourceFound.GetAnnotations()[kubernetes.ChecksumAnnotation] != customResource.GetAnnotations()[kubernetes.ChecksumAnnotation] {
+		customResourceFound.Object["spec"] = customResource.Object["spec"]
+		annotations := customResourceFound.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		for key, value := range customResource.GetAnnotations() {
+			annotations[key] = value
+		}
+		customResourceFound.SetAnnotations(annotations)
+		if err := r.Update(context.TODO(), customResourceFound); err != nil {
+			return nil, err
+		}
+		log.Infof("Updated %s %s in %s namespace", customResourceFound.GetName(), customResourceFound.GetKind(), customResourceFound.GetNamespace())
+	}
+
+	return customResourceFound, nil
+}
+
+// Add the KafkaUser and the KafkaTopics of a user, and copy its credentials to its staging project
+func (r *WorkshopReconciler) addKafkaUser(workshop *workshopv1.Workshop, id int) (reconcile.Result, error) {
+
+	username := fmt.Sprintf("user%d", id)
+	projectName := fmt.Sprintf("%s%d", workshop.Spec.Infrastructure.Project.StagingName, id)
+	clusterNamespace := kafkaClusterNamespace(workshop, projectName)
+
+	// The users of the shared cluster only have access to the topics and the consumer groups prefixed by their name
+	topicPrefix := ""
+	if !isKafkaPerUser(workshop) {
+		topicPrefix = username + "-"
+	}
+
+	kafkaUser := kafka.NewKafkaUserCR(workshop, r.Scheme, username, clusterNamespace, kafkaUserLabels, KAFKA_CLUSTER_NAME, topicPrefix)
+	if err := r.Create(context.TODO(), kafkaUser); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s KafkaUser in %s namespace", kafkaUser.GetName(), clusterNamespace)
+	}
+
+	topicNames := []string{}
+	for _, topic := range workshop.Spec.Infrastructure.Kafka.Topics {
+		kafkaTopic := kafka.NewKafkaTopicCR(workshop, r.Scheme, topicPrefix+topic.Name, clusterNamespace, kafkaTopicLabels, KAFKA_CLUSTER_NAME, topic)
+		if err := r.Create(context.TODO(), kafkaTopic); err != nil && !errors.IsAlreadyExists(err) {
+			return reconcile.Result{}, err
+		} else if err == nil {
+			log.Infof("Created %s KafkaTopic in %s namespace", kafkaTopic.GetName(), clusterNamespace)
+		}
+		topicNames = append(topicNames, kafkaTopic.GetName())
+	}
+
+	if isKafkaPerUser(workshop) {
+		if err := r.deleteKafkaCustomResources(kafka.KafkaTopicGroupVersionKind, clusterNamespace, kafkaTopicLabels, topicNames); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Wait for the User Operator to generate the password of the user
+	userSecret := &corev1.Secret{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: username, Namespace: clusterNamespace}, userSecret); err != nil && errors.IsNotFound(err) {
+		log.Infof("Waiting for the %s KafkaUser Secret in %s namespace", username, clusterNamespace)
+		return reconcile.Result{RequeueAfter: time.Second * 10}, nil
+	} else if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	credentials := map[string]string{
+		"bootstrap.servers": kafka.BootstrapServers(KAFKA_CLUSTER_NAME, clusterNamespace),
+		"security.protocol": KAFKA_SECURITY_PROTOCOL,
+		"sasl.mechanism":    KAFKA_SASL_MECHANISM,
+		"username":          username,
+		"password":          string(userSecret.Data["password"]),
+		"sasl.jaas.config":  string(userSecret.Data["sasl.jaas.config"]),
+	}
+	secret := kubernetes.NewStringDataSecret(workshop, r.Scheme, KAFKA_CREDENTIALS_SECRET_NAME, projectName, kafkaLabels, credentials)
+	if result, err := r.addSecret(secret, stringDataToData(credentials)); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// deleteKafkaCustomResources deletes the labeled KafkaUsers or KafkaTopics of a namespace which are not kept
+func (r *WorkshopReconciler) deleteKafkaCustomResources(gvk schema.GroupVersionKind, namespace string, labels map[string]string, keep []string) error {
+
+	customResourceList := &unstructured.UnstructuredList{}
+	customResourceList.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := r.List(context.TODO(), customResourceList, client.InNamespace(namespace), client.MatchingLabels(labels)); err != nil {
+		return err
+	}
+	for i := range customResourceList.Items {
+		customResource := &customResourceList.Items[i]
+		if util.StringInSlice(customResource.GetName(), keep) {
+			continue
+		}
+		if err := r.Delete(context.TODO(), customResource); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		log.Infof("Deleted %s %s in %s namespace", customResource.GetName(), gvk.Kind, namespace)
+	}
+	return nil
+}
+
+// delete Kafka
+func (r *WorkshopReconciler) deleteKafka(workshop *workshopv1.Workshop, users int) (reconcile.Result, error) {
+
+	clusterNamespaces := []string{KAFKA_NAMESPACE_NAME}
+	if isKafkaPerUser(workshop) {
+		clusterNamespaces = []string{}
+	}
+
+	for id := 1; id <= users; id++ {
+		projectName := fmt.Sprintf("%s%d", workshop.Spec.Infrastructure.Project.StagingName, id)
+		if isKafkaPerUser(workshop) {
+			clusterNamespaces = append(clusterNamespaces, projectName)
+		}
+
+		secret := kubernetes.NewStringDataSecret(workshop, r.Scheme, KAFKA_CREDENTIALS_SECRET_NAME, projectName, kafkaLabels, nil)
+		// Delete Secret
+		if err := r.Delete(context.TODO(), secret); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Delete the users and the topics while the Entity Operator can clean them up from the clusters
+	for _, clusterNamespace := range clusterNamespaces {
+		if err := r.deleteKafkaCustomResources(kafka.KafkaUserGroupVersionKind, clusterNamespace, kafkaUserLabels, nil); err != nil && !meta.IsNoMatchError(err) {
+			return reconcile.Result{}, err
+		}
+		if err := r.deleteKafkaCustomResources(kafka.KafkaTopicGroupVersionKind, clusterNamespace, kafkaTopicLabels, nil); err != nil && !meta.IsNoMatchError(err) {
+			return reconcile.Result{}, err
+		}
+
+		// Wait for the Topic Operator to remove its finalizers
+		topicList := &unstructured.UnstructuredList{}
+		topicList.SetGroupVersionKind(kafka.KafkaTopicGroupVersionKind.GroupVersion().WithKind("KafkaTopicList"))
+		if err := r.List(context.TODO(), topicList, client.InNamespace(clusterNamespace), client.MatchingLabels(kafkaTopicLabels)); err == nil && len(topicList.Items) > 0 {
+			return reconcile.Result{Requeue: true}, nil
+		}
+
+		kafkaCR := &unstructured.Unstructured{}
+		kafkaCR.SetGroupVersionKind(kafka.KafkaGroupVersionKind)
+		kafkaCR.SetName(KAFKA_CLUSTER_NAME)
+		kafkaCR.SetNamespace(clusterNamespace)
+		// Delete Kafka
+		if err := r.Delete(context.TODO(), kafkaCR); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return reconcile.Result{}, err
+		} else if err == nil {
+			log.Infof("Deleted %s Kafka in %s namespace", kafkaCR.GetName(), clusterNamespace)
+		}
+
+		kafkaNodePoolCR := &unstructured.Unstructured{}
+		kafkaNodePoolCR.SetGroupVersionKind(kafka.KafkaNodePoolGroupVersionKind)
+		kafkaNodePoolCR.SetName(kafka.NodePoolName)
+		kafkaNodePoolCR.SetNamespace(clusterNamespace)
+		// Delete KafkaNodePool
+		if err := r.Delete(context.TODO(), kafkaNodePoolCR); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return reconcile.Result{}, err
+		} else if err == nil {
+			log.Infof("Deleted %s KafkaNodePool in %s namespace", kafkaNodePoolCR.GetName(), clusterNamespace)
+		}
+	}
+
+	subscription := r.newKafkaSubscription(workshop)
+	subscriptionFound := &olmv1alpha1.Subscription{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: subscription.Name, Namespace: KAFKA_SUBSCRIPTION_NAMESPACE_NAME}, subscriptionFound); err == nil {
+		//Delete subscription
+		if err := r.Delete(context.TODO(), subscription); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s Subscription", subscription.Name)
+
+		if subscriptionFound.Status.InstalledCSV != "" {
+			operatorCSV := kubernetes.NewRedHatClusterServiceVersion(workshop, r.Scheme, subscriptionFound.Status.InstalledCSV, KAFKA_SUBSCRIPTION_NAMESPACE_NAME)
+			// Delete ClusterServiceVersion
+			if err := r.Delete(context.TODO(), operatorCSV); err != nil && !errors.IsNotFound(err) {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Deleted %s ClusterServiceVersion", operatorCSV.Name)
+		}
+	}
+
+	if !isKafkaPerUser(workshop) {
+		namespace := kubernetes.NewNamespace(workshop, r.Scheme, KAFKA_NAMESPACE_NAME)
+		// Delete Namespace
+		if err := r.Delete(context.TODO(), namespace); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s Namespace", namespace.Name)
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
@@ -2,15 +2,24 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"strings"
 	"time"
 
+	routev1 "github.com/openshift/api/route/v1"
 	"github.com/prometheus/common/log"
 	workshopv1 "github.com/stakater/workshop-operator/api/v1"
 	"github.com/stakater/workshop-operator/common/kubernetes"
 	nexus "github.com/stakater/workshop-operator/common/nexus"
 
 	"github.com/stakater/workshop-operator/common/util"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -34,16 +43,43 @@ const (
 	NEXUSCLUSTERROLEKINDNAME   = "ClusterRole"
 	NEXUSANSIBLEDEPLOYMENTNAME = "nexus-operator"
 	NEXUSDEPLOYMENTNAME        = "nexus"
+	NEXUSPORT                  = 8081
+	NEXUSADMINUSERNAME         = "admin"
+	NEXUSADMINDEFAULTPASSWORD  = "admin123"
+	NEXUSADMINPASSWORDFILE     = "/nexus-data/admin.password"
+	NEXUSADMINSECRETNAME       = "nexus-admin"
+	NEXUSADMINPASSWORDLENGTH   = 32
+	NEXUSSSOPROXYNAME          = "nexus-sso"
+	NEXUSREGISTRYNAME          = "nexus-registry"
+	NEXUSMAVENSETTINGSNAME     = "nexus-maven-settings"
+	NEXUSCREDENTIALSNAME       = "nexus-credentials"
+	NEXUSDOCKERSECRETNAME      = "nexus-docker"
+	NEXUSPIPELINESANAME        = "pipeline"
 )
 
 // Reconciling Nexus
-func (r *WorkshopReconciler) reconcileNexus(workshop *workshopv1.Workshop) (reconcile.Result, error) {
+func (r *WorkshopReconciler) reconcileNexus(workshop *workshopv1.Workshop, users int, appsHostnameSuffix string) (reconcile.Result, error) {
 	enabledNexus := workshop.Spec.Infrastructure.Nexus.Enabled
 
 	if enabledNexus {
 		if result, err := r.addNexus(workshop); util.IsRequeued(result, err) {
 			return result, err
 		}
+		if result, err := r.addNexusUsers(workshop, users, appsHostnameSuffix); util.IsRequeued(result, err) {
+			return result, err
+		}
+		if workshop.Spec.Infrastructure.Nexus.WarmUp.Enabled {
+			if result, err := r.addNexusWarmUp(workshop); util.IsRequeued(result, err) {
+				return result, err
+			}
+		}
+		if workshop.Spec.Infrastructure.SSO.Enabled {
+			if result, err := r.addNexusSSO(workshop, appsHostnameSuffix); util.IsRequeued(result, err) {
+				return result, err
+			}
+		} else if result, err := r.deleteSSOBackendNetworkPolicy(NEXUSSSOPROXYNAME, NEXUSNAMESPACENAME); util.IsRequeued(result, err) {
+			return result, err
+		}
 	}
 
 	return reconcile.Result{}, nil
@@ -103,12 +139,30 @@ func (r *WorkshopReconciler) addNexus(workshop *workshopv1.Workshop) (reconcile.
 		log.Infof("Created %s nexus Operator", nexusOperator.Name)
 	}
 
-	// Create Custom Resource
+	// Create Admin Secret with a generated password
+	adminPassword, err := r.addNexusAdminSecret(workshop)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Create/Update Custom Resource
 	nexusCustomResource := nexus.NewCustomResource(workshop, r.Scheme, NEXUSCRNAME, NEXUSNAMESPACENAME, nexuslabels)
+	nexusCustomResource.Spec.NexusAdminPassword = adminPassword
 	if err := r.Create(context.TODO(), nexusCustomResource); err != nil && !errors.IsAlreadyExists(err) {
 		return reconcile.Result{}, err
 	} else if err == nil {
 		log.Infof("Created %s nexus Custom Resource", nexusCustomResource.Name)
+	} else if errors.IsAlreadyExists(err) {
+		customResourceFound := &nexus.Nexus{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: nexusCustomResource.Name, Namespace: NEXUSNAMESPACENAME}, customResourceFound); err != nil {
+			return reconcile.Result{}, err
+		} else if !reflect.DeepEqual(nexusCustomResource.Spec, customResourceFound.Spec) {
+			customResourceFound.Spec = nexusCustomResource.Spec
+			if err := r.Update(context.TODO(), customResourceFound); err != nil {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Updated %s nexus Custom Resource", customResourceFound.Name)
+		}
 	}
 
 	// Wait for server to be running
@@ -116,10 +170,468 @@ func (r *WorkshopReconciler) addNexus(workshop *workshopv1.Workshop) (reconcile.
 		return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 1}, nil
 	}
 
+	// Replace the initial admin password
+	nexusClient := nexus.NewClient(nexusURL(), NEXUSADMINUSERNAME, adminPassword)
+	if err := nexusClient.CheckCredentials(); nexus.IsUnauthorized(err) {
+		initialPassword, err := r.getNexusInitialAdminPassword()
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		initialClient := nexus.NewClient(nexusURL(), NEXUSADMINUSERNAME, initialPassword)
+		if err := initialClient.ChangePassword(NEXUSADMINUSERNAME, adminPassword); err != nil {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Changed the initial password of the %s nexus user", NEXUSADMINUSERNAME)
+	} else if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if result, err := r.addNexusRepositories(workshop); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// getNexusInitialAdminPassword returns the random admin password written by Nexus 3.17 and later on its volume until
+// it is changed, or the admin123 password of the older versions
+func (r *WorkshopReconciler) getNexusInitialAdminPassword() (string, error) {
+	deploymentFound := &appsv1.Deployment{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: NEXUSDEPLOYMENTNAME, Namespace: NEXUSNAMESPACENAME}, deploymentFound); err != nil {
+		return "", err
+	}
+	podList := &corev1.PodList{}
+	if err := r.List(context.TODO(), podList, client.InNamespace(NEXUSNAMESPACENAME),
+		client.MatchingLabels(deploymentFound.Spec.Selector.MatchLabels)); err != nil {
+		return "", err
+	}
+	for _, pod := range podList.Items {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		stdout, _, err := kubernetes.GetK8Client().ExecInPod(pod.Name, NEXUSNAMESPACENAME, []string{"cat", NEXUSADMINPASSWORDFILE})
+		if err == nil && strings.TrimSpace(stdout) != "" {
+			return strings.TrimSpace(stdout), nil
+		}
+		break
+	}
+	return NEXUSADMINDEFAULTPASSWORD, nil
+}
+
+// Add the Nexus repositories the operator does not support through the REST API, and delete the repositories
+// removed from the Workshop since the last reconciliation
+func (r *WorkshopReconciler) addNexusRepositories(workshop *workshopv1.Workshop) (reconcile.Result, error) {
+
+	nexusClient, err := r.newNexusAdminClient()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Create/Update Repositories
+	repositoryNames := []string{}
+	for _, repository := range nexus.Repositories(workshop) {
+		repositoryNames = append(repositoryNames, repository.Name)
+		if nexus.IsCustomResourceRepository(repository) {
+			continue
+		}
+		if changed, err := nexusClient.EnsureRepository(repository); err != nil {
+			return reconcile.Result{}, err
+		} else if changed {
+			log.Infof("Created or updated %s nexus repository", repository.Name)
+		}
+	}
+
+	// Delete Repositories, the first reconciliation only recording them
+	previousNames, err := r.getNexusAnnotation(nexus.RepositoriesAnnotation)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if previousNames != "" {
+		for _, name := range strings.Split(previousNames, ",") {
+			if util.StringInSlice(name, repositoryNames) {
+				continue
+			}
+			if deleted, err := nexusClient.EnsureRepositoryDeleted(name); err != nil {
+				return reconcile.Result{}, err
+			} else if deleted {
+				log.Infof("Deleted %s nexus repository", name)
+			}
+		}
+	}
+
+	if err := r.setNexusAnnotation(nexus.RepositoriesAnnotation, strings.Join(repositoryNames, ",")); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	//Success
 	return reconcile.Result{}, nil
 }
 
+// Add Nexus Users, with their deploy rights and their client configuration in their project
+func (r *WorkshopReconciler) addNexusUsers(workshop *workshopv1.Workshop, users int, appsHostnameSuffix string) (reconcile.Result, error) {
+
+	usernames := []string{}
+	for id := 1; id <= users; id++ {
+		usernames = append(usernames, fmt.Sprintf("user%d", id))
+	}
+
+	// Change the passwords of the existing users only when the workshop password changes
+	passwordChecksum, changePassword, err := r.nexusPasswordChanged(nexus.UsersPasswordAnnotation, workshop.Spec.User.Password)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Create/Update Users
+	nexusClient, err := r.newNexusAdminClient()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	hosted := nexus.HostedRepositoryNames(workshop)
+	for _, username := range usernames {
+		if changed, err := nexusClient.EnsureWorkshopUser(username, workshop.Spec.User.Password, changePassword, hosted); err != nil {
+			return reconcile.Result{}, err
+		} else if changed {
+			log.Infof("Created or updated %s nexus user", username)
+		}
+	}
+
+	// Delete Users above the count, the first reconciliation only recording them
+	previousUsernames, err := r.getNexusAnnotation(nexus.UsersAnnotation)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if previousUsernames != "" {
+		for _, username := range strings.Split(previousUsernames, ",") {
+			if util.StringInSlice(username, usernames) {
+				continue
+			}
+			if deleted, err := nexusClient.EnsureWorkshopUserDeleted(username); err != nil {
+				return reconcile.Result{}, err
+			} else if deleted {
+				log.Infof("Deleted %s nexus user", username)
+			}
+		}
+	}
+
+	if err := r.setNexusAnnotation(nexus.UsersAnnotation, strings.Join(usernames, ",")); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := r.setNexusAnnotation(nexus.UsersPasswordAnnotation, passwordChecksum); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Expose the Docker hosted repository, pulled from by the nodes
+	registryHost := ""
+	if repository, ok := nexus.DockerRepository(workshop); ok && repository.HTTPPort > 0 {
+		deploymentFound := &appsv1.Deployment{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: NEXUSDEPLOYMENTNAME, Namespace: NEXUSNAMESPACENAME}, deploymentFound); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		// Create Service
+		service := kubernetes.NewServiceWithTarget(workshop, r.Scheme, NEXUSREGISTRYNAME, NEXUSNAMESPACENAME,
+			deploymentFound.Spec.Template.Labels, []string{"docker"}, []int32{int32(repository.HTTPPort)}, []int32{int32(repository.HTTPPort)})
+		if err := r.Create(context.TODO(), service); err != nil && !errors.IsAlreadyExists(err) {
+			return reconcile.Result{}, err
+		} else if err == nil {
+			log.Infof("Created %s Service", service.Name)
+		}
+
+		// Create/Update Route, through the SSO proxy when Nexus is only reachable from it
+		routeServiceName, routePort := NEXUSREGISTRYNAME, int32(repository.HTTPPort)
+		if workshop.Spec.Infrastructure.SSO.Enabled {
+			routeServiceName, routePort = NEXUSSSOPROXYNAME, kubernetes.OAuthProxyPort
+		}
+		route := kubernetes.NewSecuredRoute(workshop, r.Scheme, NEXUSREGISTRYNAME, NEXUSNAMESPACENAME, nexuslabels,
+			routeServiceName, routePort)
+		if err := r.Create(context.TODO(), route); err != nil && !errors.IsAlreadyExists(err) {
+			return reconcile.Result{}, err
+		} else if err == nil {
+			log.Infof("Created %s Route", route.Name)
+		} else if errors.IsAlreadyExists(err) {
+			routeFound := &routev1.Route{}
+			if err := r.Get(context.TODO(), types.NamespacedName{Name: route.Name, Namespace: NEXUSNAMESPACENAME}, routeFound); err != nil {
+				return reconcile.Result{}, err
+			} else if routeFound.Spec.To.Name != routeServiceName {
+				routeFound.Spec.To = route.Spec.To
+				routeFound.Spec.Port = route.Spec.Port
+				if err := r.Update(context.TODO(), routeFound); err != nil {
+					return reconcile.Result{}, err
+				}
+				log.Infof("Updated %s Route", routeFound.Name)
+			}
+		}
+		registryHost = fmt.Sprintf("%s-%s.%s", NEXUSREGISTRYNAME, NEXUSNAMESPACENAME, appsHostnameSuffix)
+	}
+
+	project := workshop.Spec.Infrastructure.Project
+	if !project.Enabled || project.StagingName == "" {
+		return reconcile.Result{}, nil
+	}
+	for id, username := range usernames {
+		projectName := fmt.Sprintf("%s%d", project.StagingName, id+1)
+		if result, err := r.addNexusUserConfig(workshop, projectName, username, registryHost); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// Add the Maven settings, the npm configuration and the Docker pull secret of the user in its project
+func (r *WorkshopReconciler) addNexusUserConfig(workshop *workshopv1.Workshop, projectName string, username string,
+	registryHost string) (reconcile.Result, error) {
+
+	password := workshop.Spec.User.Password
+
+	// Delete the ConfigMap of the Maven settings without credentials, written by the previous versions
+	configMap := kubernetes.NewConfigMap(workshop, r.Scheme, NEXUSMAVENSETTINGSNAME, projectName, nexuslabels, nil)
+	if err := r.Delete(context.TODO(), configMap); err != nil && !errors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Deleted %s ConfigMap in %s", configMap.Name, projectName)
+	}
+
+	// Create/Update Credentials Secret
+	credentials := map[string]string{
+		nexus.UsernameKey:      username,
+		nexus.PasswordKey:      password,
+		nexus.NpmrcKey:         nexus.NewNpmrc(workshop, nexusClientURL(workshop), username, password),
+		nexus.MavenSettingsKey: nexus.NewMavenSettings(workshop, nexusClientURL(workshop), username, password),
+	}
+	if result, err := r.addSecret(kubernetes.NewStringDataSecret(workshop, r.Scheme, NEXUSCREDENTIALSNAME, projectName,
+		nexuslabels, credentials), stringDataToData(credentials)); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	if registryHost == "" {
+		return reconcile.Result{}, nil
+	}
+
+	// Create/Update Docker Secret
+	dockerConfigJSON, err := nexus.NewDockerConfigJSON(registryHost, username, password)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	dockerSecret := kubernetes.NewDockerConfigSecret(workshop, r.Scheme, NEXUSDOCKERSECRETNAME, projectName, nexuslabels, dockerConfigJSON)
+	if result, err := r.addSecret(dockerSecret, dockerSecret.Data); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	// Link Docker Secret, the pipeline Service Account existing once OpenShift Pipelines is installed
+	for _, serviceAccountName := range []string{PROJECT_SERVICEACCOUNT_NAME, NEXUSPIPELINESANAME} {
+		serviceAccountFound := &corev1.ServiceAccount{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: serviceAccountName, Namespace: projectName}, serviceAccountFound); err != nil && errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return reconcile.Result{}, err
+		}
+		if linkServiceAccountSecret(serviceAccountFound, NEXUSDOCKERSECRETNAME) {
+			if err := r.Update(context.TODO(), serviceAccountFound); err != nil {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Linked %s Secret to %s Service Account in %s", NEXUSDOCKERSECRETNAME, serviceAccountName, projectName)
+		}
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// addSecret creates the secret, or updates it when its data is not the expected one
+func (r *WorkshopReconciler) addSecret(secret *corev1.Secret, data map[string][]byte) (reconcile.Result, error) {
+	if err := r.Create(context.TODO(), secret); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Secret in %s", secret.Name, secret.Namespace)
+	} else if errors.IsAlreadyExists(err) {
+		secretFound := &corev1.Secret{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, secretFound); err != nil {
+			return reconcile.Result{}, err
+		} else if !reflect.DeepEqual(data, secretFound.Data) {
+			secretFound.Data = data
+			if err := r.Update(context.TODO(), secretFound); err != nil {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Updated %s Secret in %s", secret.Name, secret.Namespace)
+		}
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// linkServiceAccountSecret adds the secret to the image pull and mountable secrets of the service account,
+// and returns true if it was missing
+func linkServiceAccountSecret(serviceAccount *corev1.ServiceAccount, secretName string) bool {
+	linked := false
+	found := false
+	for _, secret := range serviceAccount.ImagePullSecrets {
+		found = found || secret.Name == secretName
+	}
+	if !found {
+		serviceAccount.ImagePullSecrets = append(serviceAccount.ImagePullSecrets, corev1.LocalObjectReference{Name: secretName})
+		linked = true
+	}
+	found = false
+	for _, secret := range serviceAccount.Secrets {
+		found = found || secret.Name == secretName
+	}
+	if !found {
+		serviceAccount.Secrets = append(serviceAccount.Secrets, corev1.ObjectReference{Name: secretName})
+		linked = true
+	}
+	return linked
+}
+
+// addNexusAdminSecret returns the password of the admin, generated once and stored in its Secret
+func (r *WorkshopReconciler) addNexusAdminSecret(workshop *workshopv1.Workshop) (string, error) {
+	secretFound := &corev1.Secret{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: NEXUSADMINSECRETNAME, Namespace: NEXUSNAMESPACENAME}, secretFound); err != nil && errors.IsNotFound(err) {
+		password, err := util.GeneratePassword(NEXUSADMINPASSWORDLENGTH)
+		if err != nil {
+			return "", err
+		}
+		secret := kubernetes.NewStringDataSecret(workshop, r.Scheme, NEXUSADMINSECRETNAME, NEXUSNAMESPACENAME, nexuslabels,
+			map[string]string{nexus.UsernameKey: NEXUSADMINUSERNAME, nexus.PasswordKey: password})
+		if err := r.Create(context.TODO(), secret); err != nil {
+			return "", err
+		}
+		log.Infof("Created %s Secret", secret.Name)
+		return password, nil
+	} else if err != nil {
+		return "", err
+	}
+	return string(secretFound.Data[nexus.PasswordKey]), nil
+}
+
+// newNexusAdminClient returns a client of Nexus authenticated with the generated password of the admin
+func (r *WorkshopReconciler) newNexusAdminClient() (*nexus.Client, error) {
+	secretFound := &corev1.Secret{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: NEXUSADMINSECRETNAME, Namespace: NEXUSNAMESPACENAME}, secretFound); err != nil {
+		return nil, err
+	}
+	return nexus.NewClient(nexusURL(), NEXUSADMINUSERNAME, string(secretFound.Data[nexus.PasswordKey])), nil
+}
+
+// stringDataToData returns the data of a secret created from stringData
+func stringDataToData(stringData map[string]string) map[string][]byte {
+	data := map[string][]byte{}
+	for key, value := range stringData {
+		data[key] = []byte(value)
+	}
+	return data
+}
+
+// Add Nexus SSO, the OAuth proxy signing the users in to their Nexus account with the workshop password, so that
+// Nexus needs no header authentication
+func (r *WorkshopReconciler) addNexusSSO(workshop *workshopv1.Workshop, appsHostnameSuffix string) (reconcile.Result, error) {
+
+	// Deploy SSO Proxy, repository clients keep authenticating with their own passwords. The Docker registry is
+	// also served by the proxy.
+	host := fmt.Sprintf("%s-%s.%s", NEXUSSSOPROXYNAME, NEXUSNAMESPACENAME, appsHostnameSuffix)
+	ports := []int32{NEXUSPORT}
+	extraArgs := []string{
+		"--pass-basic-auth=true",
+		"--basic-auth-password=" + workshop.Spec.User.Password,
+	}
+	if repository, ok := nexus.DockerRepository(workshop); ok && repository.HTTPPort > 0 {
+		ports = append(ports, int32(repository.HTTPPort))
+		extraArgs = append(extraArgs, fmt.Sprintf("--upstream=http://%s.%s.svc:%d/v2/", NEXUSREGISTRYNAME, NEXUSNAMESPACENAME, repository.HTTPPort))
+	}
+	if result, err := r.addSSOProxy(workshop, NEXUSSSOPROXYNAME, NEXUSNAMESPACENAME, nexusURL(), host,
+		[]string{`^/repository/`, `^/v2/`, `^/service/rest/`}, extraArgs); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	// Create/Update Network Policy, only the proxy, the Nexus operator, the warm-up and the Workshop operator
+	// reaching Nexus
+	deploymentFound := &appsv1.Deployment{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: NEXUSDEPLOYMENTNAME, Namespace: NEXUSNAMESPACENAME}, deploymentFound); err != nil {
+		return reconcile.Result{}, err
+	}
+	if result, err := r.addSSOBackendNetworkPolicy(workshop, NEXUSSSOPROXYNAME, NEXUSNAMESPACENAME,
+		deploymentFound.Spec.Template.Labels, ports, []map[string]string{nexuslabels}, nil); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	// Delete the Route of the Nexus operator, Nexus being only exposed through the proxy
+	route := &routev1.Route{}
+	route.Name = NEXUSDEPLOYMENTNAME
+	route.Namespace = NEXUSNAMESPACENAME
+	if err := r.Delete(context.TODO(), route); err != nil && !errors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Deleted %s Route", route.Name)
+	}
+
+	// Create Route
+	route = kubernetes.NewSecuredRoute(workshop, r.Scheme, NEXUSSSOPROXYNAME, NEXUSNAMESPACENAME, nexuslabels,
+		NEXUSSSOPROXYNAME, kubernetes.OAuthProxyPort)
+	if err := r.Create(context.TODO(), route); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Route", route.Name)
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// getNexusAnnotation returns an annotation of the Nexus Custom Resource
+func (r *WorkshopReconciler) getNexusAnnotation(key string) (string, error) {
+	customResourceFound := &nexus.Nexus{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: NEXUSCRNAME, Namespace: NEXUSNAMESPACENAME}, customResourceFound); err != nil {
+		return "", err
+	}
+	return customResourceFound.Annotations[key], nil
+}
+
+// setNexusAnnotation sets an annotation of the Nexus Custom Resource, recording what was applied to Nexus
+func (r *WorkshopReconciler) setNexusAnnotation(key string, value string) error {
+	customResourceFound := &nexus.Nexus{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: NEXUSCRNAME, Namespace: NEXUSNAMESPACENAME}, customResourceFound); err != nil {
+		return err
+	} else if customResourceFound.Annotations[key] == value {
+		return nil
+	}
+	if customResourceFound.Annotations == nil {
+		customResourceFound.Annotations = map[string]string{}
+	}
+	customResourceFound.Annotations[key] = value
+	return r.Update(context.TODO(), customResourceFound)
+}
+
+// nexusPasswordChanged returns the checksum of the password, and true if it is not the one recorded in the
+// annotation of the Nexus Custom Resource
+func (r *WorkshopReconciler) nexusPasswordChanged(key string, password string) (string, bool, error) {
+	checksum, err := kubernetes.Checksum(password)
+	if err != nil {
+		return "", false, err
+	}
+	previousChecksum, err := r.getNexusAnnotation(key)
+	if err != nil {
+		return "", false, err
+	}
+	return checksum, checksum != previousChecksum, nil
+}
+
+// nexusURL returns the URL of the Nexus service
+func nexusURL() string {
+	return fmt.Sprintf("http://%s.%s.svc:%d", NEXUSDEPLOYMENTNAME, NEXUSNAMESPACENAME, NEXUSPORT)
+}
+
+// nexusClientURL returns the URL of Nexus for the Maven and npm clients of the users, the SSO proxy being the only
+// way to reach Nexus with SSO
+func nexusClientURL(workshop *workshopv1.Workshop) string {
+	if workshop.Spec.Infrastructure.SSO.Enabled {
+		return fmt.Sprintf("http://%s.%s.svc:%d", NEXUSSSOPROXYNAME, NEXUSNAMESPACENAME, kubernetes.OAuthProxyPort)
+	}
+	return nexusURL()
+}
+
 // Delete Nexus
 func (r *WorkshopReconciler) deleteNexus(workshop *workshopv1.Workshop) (reconcile.Result, error) {
 
@@ -128,51 +640,56 @@ func (r *WorkshopReconciler) deleteNexus(workshop *workshopv1.Workshop) (reconci
 	imageName := workshop.Spec.Infrastructure.Nexus.Image.Name
 	imageTag := workshop.Spec.Infrastructure.Nexus.Image.Tag
 
+	// Delete OAuth Client
+	if result, err := r.deleteSSOProxy(workshop, NEXUSSSOPROXYNAME, NEXUSNAMESPACENAME); util.IsRequeued(result, err) {
+		return result, err
+	}
+
 	nexusCustomResource := nexus.NewCustomResource(workshop, r.Scheme, NEXUSCRNAME, NEXUSNAMESPACENAME, nexuslabels)
 	// Delete Custom Resource
-	if err := r.Delete(context.TODO(), nexusCustomResource); err != nil {
+	if err := r.Delete(context.TODO(), nexusCustomResource); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s nexus Custom Resource", nexusCustomResource.Name)
 
 	nexusOperator := kubernetes.NewAnsibleOperatorDeployment(workshop, r.Scheme, NEXUSANSIBLEDEPLOYMENTNAME, NEXUSNAMESPACENAME, nexuslabels, imageName+":"+imageTag, NEXUSSERVICEACCOUNTNAME)
 	// Delete Operator
-	if err := r.Delete(context.TODO(), nexusOperator); err != nil {
+	if err := r.Delete(context.TODO(), nexusOperator); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s nexus Operator", nexusOperator.Name)
 
 	nexusClusterRoleBinding := kubernetes.NewClusterRoleBindingSA(workshop, r.Scheme, NEXUSROLEBINDINGSANAME, NEXUSNAMESPACENAME, nexuslabels, NEXUSSERVICEACCOUNTNAME, NEXUSROLEBINDINGSANAME, NEXUSCLUSTERROLEKINDNAME)
 	// Delete Cluster Role Binding
-	if err := r.Delete(context.TODO(), nexusClusterRoleBinding); err != nil {
+	if err := r.Delete(context.TODO(), nexusClusterRoleBinding); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s nexus Cluster Role Binding", nexusClusterRoleBinding.Name)
 
 	nexusClusterRole := kubernetes.NewClusterRole(workshop, r.Scheme, NEXUSCLUSTERROLENAME, NEXUSNAMESPACENAME, nexuslabels, nexus.NewRules())
 	// Delete Cluster Role
-	if err := r.Delete(context.TODO(), nexusClusterRole); err != nil {
+	if err := r.Delete(context.TODO(), nexusClusterRole); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s nexus Cluster Role", nexusClusterRole.Name)
 
 	nexusServiceAccount := kubernetes.NewServiceAccount(workshop, r.Scheme, NEXUSSERVICEACCOUNTNAME, NEXUSNAMESPACENAME, nexuslabels)
 	// Delete Service Account
-	if err := r.Delete(context.TODO(), nexusServiceAccount); err != nil {
+	if err := r.Delete(context.TODO(), nexusServiceAccount); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s nexus Service Account", nexusServiceAccount.Name)
 
 	nexusCustomResourceDefinition := kubernetes.NewCustomResourceDefinition(workshop, r.Scheme, NEXUSCRDNAME, NEXUSCRDGROUPNAME, NEXUSCRDKINDNAME, NEXUSCRDLISTKINDNAME, NEXUSCRDPLURALNAME, NEXUSCRDSINGULARNAME, NEXUSCRDVERSIONAME, nil, nil)
 	// Delete CRD
-	if err := r.Delete(context.TODO(), nexusCustomResourceDefinition); err != nil {
+	if err := r.Delete(context.TODO(), nexusCustomResourceDefinition); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s nexus Custom Resource Definition", nexusCustomResourceDefinition.Name)
 
 	nexusNamespace := kubernetes.NewNamespace(workshop, r.Scheme, NEXUSNAMESPACENAME)
 	// Delete Project
-	if err := r.Delete(context.TODO(), nexusNamespace); err != nil {
+	if err := r.Delete(context.TODO(), nexusNamespace); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s  nexus Project", NEXUSNAMESPACENAME)
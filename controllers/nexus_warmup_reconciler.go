@@ -0,0 +1,267 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/common/log"
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/kubernetes"
+	nexus "github.com/stakater/workshop-operator/common/nexus"
+	"github.com/stakater/workshop-operator/common/util"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	NEXUSWARMUPNAME            = "nexus-warmup"
+	NEXUSCACHERUNNING          = "Running"
+	NEXUSCACHECOMPLETED        = "Completed"
+	NEXUSCACHEFAILED           = "Failed"
+	NEXUSWARMUPSECRETNAME      = "nexus-warmup"
+	NEXUSWARMUPRETRYSECONDS    = 60
+	NEXUSWARMUPMAXRETRYSECONDS = 3600
+	NEXUSWARMUPPASSWORDLENGTH  = 32
+)
+
+var nexusWarmUpLabels = map[string]string{
+	"app":                       NEXUSWARMUPNAME,
+	"app.kubernetes.io/part-of": "nexus",
+}
+
+// Add Nexus Warm-Up, run once per configuration and recorded in the status
+func (r *WorkshopReconciler) addNexusWarmUp(workshop *workshopv1.Workshop) (reconcile.Result, error) {
+
+	spec := workshop.Spec.Infrastructure.Nexus.WarmUp
+
+	// Create Secret
+	if result, err := r.addNexusWarmUpSecret(workshop); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	// Create Persistent Volume Claim, unless it was provisioned with a cache to import
+	if spec.Cache.ClaimName != "" {
+		if result, err := r.addNexusWarmUpCacheClaim(workshop); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
+
+	checksum, err := nexusWarmUpChecksum(workshop)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	cacheStatus := workshop.Status.NexusCache
+	if cacheStatus.Checksum == checksum && cacheStatus.Phase == NEXUSCACHECOMPLETED {
+		return reconcile.Result{}, nil
+	}
+
+	// Create/Update User
+	if result, err := r.addNexusWarmUpUser(workshop); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	// Create/Replace Job
+	image := imageOrDefault(spec.Image, nexus.DefaultWarmUpImageName, nexus.DefaultWarmUpImageTag)
+	job := nexus.NewWarmUpJob(workshop, r.Scheme, NEXUSWARMUPNAME, NEXUSNAMESPACENAME, nexusWarmUpLabels, image, nexusURL(),
+		NEXUSWARMUPSECRETNAME, checksum)
+	setWorkshopReference(workshop, job)
+	jobFound := &batchv1.Job{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: job.Name, Namespace: NEXUSNAMESPACENAME}, jobFound); err != nil && errors.IsNotFound(err) {
+		if err := r.Create(context.TODO(), job); err != nil {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Created %s Job", job.Name)
+		jobFound = job
+	} else if err != nil {
+		return reconcile.Result{}, err
+	} else if jobFound.Annotations[nexus.WarmUpChecksumAnnotation] != checksum {
+		if err := r.Delete(context.TODO(), jobFound, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s Job of a previous configuration", jobFound.Name)
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	// Record the progress, the Job being watched
+	phase := NEXUSCACHERUNNING
+	var completionTime *metav1.Time
+	var failureTime metav1.Time
+	for _, condition := range jobFound.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		if condition.Type == batchv1.JobComplete {
+			phase = NEXUSCACHECOMPLETED
+			completionTime = jobFound.Status.CompletionTime
+		} else if condition.Type == batchv1.JobFailed {
+			phase = NEXUSCACHEFAILED
+			failureTime = condition.LastTransitionTime
+		}
+	}
+	retries := cacheStatus.Retries
+	if checksum != cacheStatus.Checksum || phase == NEXUSCACHECOMPLETED {
+		retries = 0
+	}
+	if phase != cacheStatus.Phase || checksum != cacheStatus.Checksum {
+		if err := r.updateStatus(workshop, func(status *workshopv1.WorkshopStatus) {
+			status.NexusCache = workshopv1.NexusCacheStatus{Phase: phase, Checksum: checksum, CompletionTime: completionTime, Retries: retries}
+		}); err != nil {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Nexus cache warm-up %s", phase)
+	}
+
+	// Retry a failed warm-up, waiting twice as long after every failure
+	if phase == NEXUSCACHEFAILED {
+		delay := time.Second * NEXUSWARMUPRETRYSECONDS
+		for i := 0; i < retries && delay < time.Second*NEXUSWARMUPMAXRETRYSECONDS; i++ {
+			delay *= 2
+		}
+		if delay > time.Second*NEXUSWARMUPMAXRETRYSECONDS {
+			delay = time.Second * NEXUSWARMUPMAXRETRYSECONDS
+		}
+		if wait := time.Until(failureTime.Add(delay)); wait > 0 {
+			return reconcile.Result{RequeueAfter: wait}, nil
+		}
+		if err := r.Delete(context.TODO(), jobFound, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		if err := r.updateStatus(workshop, func(status *workshopv1.WorkshopStatus) {
+			status.NexusCache.Retries = retries + 1
+		}); err != nil {
+			return reconcile.Result{}, err
+		}
+		log.Warnf("Deleted the failed %s Job, retrying the warm-up", jobFound.Name)
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// Add the Secret of the account of the warm-up with a generated password, replacing the admin credentials stored by
+// the previous versions
+func (r *WorkshopReconciler) addNexusWarmUpSecret(workshop *workshopv1.Workshop) (reconcile.Result, error) {
+
+	secretFound := &corev1.Secret{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: NEXUSWARMUPSECRETNAME, Namespace: NEXUSNAMESPACENAME}, secretFound); err != nil && !errors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	} else if errors.IsNotFound(err) || string(secretFound.Data[nexus.UsernameKey]) != nexus.WarmUpUsername {
+		password, err := util.GeneratePassword(NEXUSWARMUPPASSWORDLENGTH)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		credentials := map[string]string{nexus.UsernameKey: nexus.WarmUpUsername, nexus.PasswordKey: password}
+		secret := kubernetes.NewStringDataSecret(workshop, r.Scheme, NEXUSWARMUPSECRETNAME, NEXUSNAMESPACENAME, nexusWarmUpLabels, credentials)
+		if result, err := r.addSecret(secret, stringDataToData(credentials)); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// Add the account of the warm-up, only allowed to read the repositories and to upload the imported cache
+func (r *WorkshopReconciler) addNexusWarmUpUser(workshop *workshopv1.Workshop) (reconcile.Result, error) {
+
+	secretFound := &corev1.Secret{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: NEXUSWARMUPSECRETNAME, Namespace: NEXUSNAMESPACENAME}, secretFound); err != nil {
+		return reconcile.Result{}, err
+	}
+	password := string(secretFound.Data[nexus.PasswordKey])
+
+	passwordChecksum, changePassword, err := r.nexusPasswordChanged(nexus.WarmUpPasswordAnnotation, password)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	nexusClient, err := r.newNexusAdminClient()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if _, err := nexusClient.EnsureRole(nexus.Role{
+		ID:          nexus.WarmUpUsername,
+		Name:        nexus.WarmUpUsername,
+		Description: "Role of the cache warm-up",
+		Privileges:  nexus.WarmUpPrivileges(workshop),
+		Roles:       []string{},
+	}); err != nil {
+		return reconcile.Result{}, err
+	}
+	if _, err := nexusClient.EnsureUser(nexus.User{
+		UserID:       nexus.WarmUpUsername,
+		FirstName:    nexus.WarmUpUsername,
+		LastName:     nexus.WarmUpUsername,
+		EmailAddress: nexus.WarmUpUsername + "@none.com",
+		Password:     password,
+		Status:       "active",
+		Roles:        []string{nexus.WarmUpUsername},
+	}, changePassword); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := r.setNexusAnnotation(nexus.WarmUpPasswordAnnotation, passwordChecksum); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// Add the Persistent Volume Claim of the cache. It is deleted with the nexus namespace, its volume being retained
+// so that the exported cache can be bound again by the claim of another workshop.
+func (r *WorkshopReconciler) addNexusWarmUpCacheClaim(workshop *workshopv1.Workshop) (reconcile.Result, error) {
+
+	cache := workshop.Spec.Infrastructure.Nexus.WarmUp.Cache
+	size := cache.VolumeSize
+	if size == "" {
+		size = nexus.DefaultCacheVolumeSize
+	}
+
+	// Create Persistent Volume Claim
+	pvc := kubernetes.NewPersistentVolumeClaim(workshop, r.Scheme, cache.ClaimName, NEXUSNAMESPACENAME, nexusWarmUpLabels, size)
+	// Keep the exported cache while the nexus namespace exists
+	pvc.OwnerReferences = nil
+	if err := r.Create(context.TODO(), pvc); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Persistent Volume Claim", pvc.Name)
+	}
+
+	// Retain the volume once bound
+	pvcFound := &corev1.PersistentVolumeClaim{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: pvc.Name, Namespace: NEXUSNAMESPACENAME}, pvcFound); err != nil {
+		return reconcile.Result{}, err
+	} else if pvcFound.Spec.VolumeName == "" {
+		return reconcile.Result{}, nil
+	}
+	pvFound := &corev1.PersistentVolume{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: pvcFound.Spec.VolumeName}, pvFound); err != nil {
+		return reconcile.Result{}, err
+	} else if pvFound.Spec.PersistentVolumeReclaimPolicy != corev1.PersistentVolumeReclaimRetain {
+		patch := client.MergeFrom(pvFound.DeepCopy())
+		pvFound.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimRetain
+		if err := r.Patch(context.TODO(), pvFound, patch); err != nil {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Retained %s Persistent Volume of %s", pvFound.Name, pvc.Name)
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// nexusWarmUpChecksum returns the checksum of the configuration the warm-up depends on
+func nexusWarmUpChecksum(workshop *workshopv1.Workshop) (string, error) {
+	return kubernetes.Checksum([]interface{}{
+		workshop.Spec.Infrastructure.Nexus.WarmUp,
+		nexus.Repositories(workshop),
+		workshop.Spec.Source,
+	})
+}
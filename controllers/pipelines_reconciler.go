@@ -69,7 +69,7 @@ func (r *WorkshopReconciler) deletePipelines(workshop *workshopv1.Workshop) (rec
 	pipelineSubscription := kubernetes.NewRedHatSubscription(workshop, r.Scheme, PIPELINES_SUBSCRIPTION_NAME, PIPELINES_SUBSCRIPTION_NAMESPACE_NAME,
 		PIPELINES_SUBSCRIPTION_PACKAGE_NAME, channel, clusterServiceVersion)
 	// Delete Subscription
-	if err := r.Delete(context.TODO(), pipelineSubscription); err != nil {
+	if err := r.Delete(context.TODO(), pipelineSubscription); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s Subscription", pipelineSubscription.Name)
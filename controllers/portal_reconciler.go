@@ -2,49 +2,50 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
 	"github.com/prometheus/common/log"
 	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/git"
+	"github.com/stakater/workshop-operator/common/kafka"
 	"github.com/stakater/workshop-operator/common/kubernetes"
-	"github.com/stakater/workshop-operator/common/redis"
-	"github.com/stakater/workshop-operator/common/usernamedistribution"
+	"github.com/stakater/workshop-operator/common/maistra"
+	"github.com/stakater/workshop-operator/common/portal"
 	"github.com/stakater/workshop-operator/common/util"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
-	"reflect"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const (
-	REDIS_VOLUME_SIZE      = "512Mi"
-	REDIS_DEPLOYMENT_NAME  = "redis"
-	REDIS_SERVICE_NAME     = "redis"
-	REDIS_PVC_NAME         = "redis"
-	REDIS_SECRET_NAME      = "redis"
-	PORTAL_SERVICE_NAME    = "portal"
-	PORTAL_DEPLOYMENT_NAME = "portal"
-	PORTAL_ROUTE_NAME      = "portal"
-	PORTAL_ROUTE_PORT      = 8080
+	PORTAL_SERVICE_NAME          = "portal"
+	PORTAL_DEPLOYMENT_NAME       = "portal"
+	PORTAL_ROUTE_NAME            = "portal"
+	PORTAL_SERVICEACCOUNT_NAME   = "portal"
+	PORTAL_ROLE_NAME             = "portal"
+	PORTAL_ROLE_BINDING_NAME     = "portal"
+	PORTAL_ROLE_KIND_NAME        = "Role"
+	PORTAL_CONFIG_SECRET_NAME    = "portal-config"
+	PORTAL_ADMIN_SECRET_NAME     = "portal-admin"
+	PORTAL_CLAIMS_CONFIGMAP_NAME = "portal-claims"
+	PORTAL_DEFAULT_TITLE         = "OpenShift Workshops"
 )
 
-var RedisLabels = map[string]string{
-	"app":                       REDIS_SERVICE_NAME,
+var PortalLabels = map[string]string{
+	"app":                       "portal",
 	"app.kubernetes.io/part-of": "portal",
 }
 
-var RedisCredentials = map[string]string{
-	"database-password": "redis",
-}
-
 // reconcilePortal reconciles Portal
 func (r *WorkshopReconciler) reconcilePortal(workshop *workshopv1.Workshop, users int,
 	appsHostnameSuffix string, openshiftConsoleURL string) (reconcile.Result, error) {
 
-	if result, err := r.addRedis(workshop); util.IsRequeued(result, err) {
-		return result, err
-	}
-
-	if result, err := r.addUpdateUsernameDistribution(workshop, users, appsHostnameSuffix, openshiftConsoleURL); err != nil {
+	if result, err := r.addUpdatePortal(workshop, users, appsHostnameSuffix, openshiftConsoleURL); util.IsRequeued(result, err) {
 		return result, err
 	}
 
@@ -52,60 +53,108 @@ func (r *WorkshopReconciler) reconcilePortal(workshop *workshopv1.Workshop, user
 	return reconcile.Result{}, nil
 }
 
-func (r *WorkshopReconciler) addRedis(workshop *workshopv1.Workshop) (reconcile.Result, error) {
-	log.Info("Creating Redis")
-	secret := kubernetes.NewStringDataSecret(workshop, r.Scheme, REDIS_SECRET_NAME, workshop.Namespace, RedisLabels, RedisCredentials)
-	if err := r.Create(context.TODO(), secret); err != nil && !errors.IsAlreadyExists(err) {
+func (r *WorkshopReconciler) addUpdatePortal(workshop *workshopv1.Workshop,
+	users int, appsHostnameSuffix string, openshiftConsoleURL string) (reconcile.Result, error) {
+	log.Info("Creating portal")
+
+	// Create Service Account
+	serviceAccount := kubernetes.NewServiceAccount(workshop, r.Scheme, PORTAL_SERVICEACCOUNT_NAME, workshop.Namespace, PortalLabels)
+	if err := r.Create(context.TODO(), serviceAccount); err != nil && !errors.IsAlreadyExists(err) {
 		return reconcile.Result{}, err
 	} else if err == nil {
-		log.Infof("Created %s Secret", secret.Name)
+		log.Infof("Created %s Service Account", serviceAccount.Name)
 	}
 
-	persistentVolumeClaim := kubernetes.NewPersistentVolumeClaim(workshop, r.Scheme, REDIS_PVC_NAME, workshop.Namespace, RedisLabels, REDIS_VOLUME_SIZE)
-	if err := r.Create(context.TODO(), persistentVolumeClaim); err != nil && !errors.IsAlreadyExists(err) {
+	// Create Role
+	role := kubernetes.NewRole(workshop, r.Scheme, PORTAL_ROLE_NAME, workshop.Namespace, PortalLabels, kubernetes.PortalRules(PORTAL_CLAIMS_CONFIGMAP_NAME))
+	if err := r.Create(context.TODO(), role); err != nil && !errors.IsAlreadyExists(err) {
 		return reconcile.Result{}, err
 	} else if err == nil {
-		log.Infof("Created %s Persistent Volume Claim", persistentVolumeClaim.Name)
+		log.Infof("Created %s Role", role.Name)
 	}
 
-	// Deploy/Update UsernameDistribution
-	dep := redis.NewDeployment(workshop, r.Scheme, REDIS_DEPLOYMENT_NAME, workshop.Namespace, RedisLabels)
-	if err := r.Create(context.TODO(), dep); err != nil && !errors.IsAlreadyExists(err) {
+	// Create Role Binding
+	roleBinding := kubernetes.NewRoleBindingSA(workshop, r.Scheme, PORTAL_ROLE_BINDING_NAME, workshop.Namespace, PortalLabels,
+		serviceAccount.Name, role.Name, PORTAL_ROLE_KIND_NAME)
+	if err := r.Create(context.TODO(), roleBinding); err != nil && !errors.IsAlreadyExists(err) {
 		return reconcile.Result{}, err
 	} else if err == nil {
-		log.Infof("Created %s Deployment", dep.Name)
-	} else if errors.IsAlreadyExists(err) {
-		deploymentFound := &appsv1.Deployment{}
-		if err := r.Get(context.TODO(), types.NamespacedName{Name: dep.Name, Namespace: workshop.Namespace}, deploymentFound); err != nil {
+		log.Infof("Created %s Role Binding", roleBinding.Name)
+	}
+
+	// Create Claims ConfigMap, owned by the portal once created
+	claimsConfigMap := kubernetes.NewConfigMap(workshop, r.Scheme, PORTAL_CLAIMS_CONFIGMAP_NAME, workshop.Namespace, PortalLabels, nil)
+	if err := r.Create(context.TODO(), claimsConfigMap); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s ConfigMap", claimsConfigMap.Name)
+	}
+
+	// Create Admin Secret with a generated password and a generated key signing the cookies of the attendees
+	adminSecretFound := &corev1.Secret{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: PORTAL_ADMIN_SECRET_NAME, Namespace: workshop.Namespace}, adminSecretFound); err != nil && errors.IsNotFound(err) {
+		adminPassword, err := util.GeneratePassword(16)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		cookieSecret, err := util.GeneratePassword(32)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		adminSecret := kubernetes.NewStringDataSecret(workshop, r.Scheme, PORTAL_ADMIN_SECRET_NAME, workshop.Namespace, PortalLabels,
+			map[string]string{portal.AdminPasswordKey: adminPassword, portal.CookieSecretKey: cookieSecret})
+		if err := r.Create(context.TODO(), adminSecret); err != nil && !errors.IsAlreadyExists(err) {
 			return reconcile.Result{}, err
 		} else if err == nil {
-			if !reflect.DeepEqual(dep.Spec.Template.Spec.Containers[0].Env, deploymentFound.Spec.Template.Spec.Containers[0].Env) {
-				// Update Guide
-				if err := r.Update(context.TODO(), dep); err != nil {
-					return reconcile.Result{}, err
-				}
-				log.Infof("Updated %s Deployment", dep.Name)
-			}
+			log.Infof("Created %s Secret", adminSecret.Name)
+		}
+	} else if err != nil {
+		return reconcile.Result{}, err
+	} else if len(adminSecretFound.Data[portal.CookieSecretKey]) == 0 {
+		// Secrets created by the previous versions have no cookie key
+		cookieSecret, err := util.GeneratePassword(32)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if adminSecretFound.Data == nil {
+			adminSecretFound.Data = map[string][]byte{}
+		}
+		adminSecretFound.Data[portal.CookieSecretKey] = []byte(cookieSecret)
+		if err := r.Update(context.TODO(), adminSecretFound); err != nil {
+			return reconcile.Result{}, err
 		}
+		log.Infof("Updated %s Secret", adminSecretFound.Name)
 	}
 
-	// Create Service
-	service := kubernetes.NewService(workshop, r.Scheme, REDIS_SERVICE_NAME, workshop.Namespace, RedisLabels, []string{"http"}, []int32{6379})
-	if err := r.Create(context.TODO(), service); err != nil && !errors.IsAlreadyExists(err) {
+	// Create/Update Config Secret
+	config, err := json.Marshal(r.newPortalConfig(workshop, users, appsHostnameSuffix, openshiftConsoleURL))
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	configData := map[string]string{portal.ConfigFileName: string(config)}
+	configSecret := kubernetes.NewStringDataSecret(workshop, r.Scheme, PORTAL_CONFIG_SECRET_NAME, workshop.Namespace, PortalLabels, configData)
+	if err := r.Create(context.TODO(), configSecret); err != nil && !errors.IsAlreadyExists(err) {
 		return reconcile.Result{}, err
 	} else if err == nil {
-		log.Infof("Created %s Service", service.Name)
+		log.Infof("Created %s Secret", configSecret.Name)
+	} else if errors.IsAlreadyExists(err) {
+		configSecretFound := &corev1.Secret{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: configSecret.Name, Namespace: workshop.Namespace}, configSecretFound); err != nil {
+			return reconcile.Result{}, err
+		} else if err == nil {
+			if string(configSecretFound.Data[portal.ConfigFileName]) != configData[portal.ConfigFileName] {
+				configSecretFound.StringData = configData
+				if err := r.Update(context.TODO(), configSecretFound); err != nil {
+					return reconcile.Result{}, err
+				}
+				log.Infof("Updated %s Secret", configSecretFound.Name)
+			}
+		}
 	}
 
-	//Success
-	return reconcile.Result{}, nil
-}
-
-func (r *WorkshopReconciler) addUpdateUsernameDistribution(workshop *workshopv1.Workshop,
-	users int, appsHostnameSuffix string, openshiftConsoleURL string) (reconcile.Result, error) {
-	log.Info("Creating portal")
-	// Deploy/Update UsernameDistribution
-	dep := usernamedistribution.NewDeployment(workshop, r.Scheme, PORTAL_DEPLOYMENT_NAME, RedisLabels, REDIS_SERVICE_NAME, users, appsHostnameSuffix, openshiftConsoleURL)
+	// Deploy/Update Portal
+	dep := portal.NewDeployment(workshop, r.Scheme, PORTAL_DEPLOYMENT_NAME, workshop.Namespace, PortalLabels,
+		serviceAccount.Name, PORTAL_CONFIG_SECRET_NAME, PORTAL_ADMIN_SECRET_NAME, PORTAL_CLAIMS_CONFIGMAP_NAME)
 	if err := r.Create(context.TODO(), dep); err != nil && !errors.IsAlreadyExists(err) {
 		return reconcile.Result{}, err
 	} else if err == nil {
@@ -115,19 +164,21 @@ func (r *WorkshopReconciler) addUpdateUsernameDistribution(workshop *workshopv1.
 		if err := r.Get(context.TODO(), types.NamespacedName{Name: dep.Name, Namespace: workshop.Namespace}, deploymentFound); err != nil {
 			return reconcile.Result{}, err
 		} else if err == nil {
-			if !reflect.DeepEqual(dep.Spec.Template.Spec.Containers[0].Env, deploymentFound.Spec.Template.Spec.Containers[0].Env) ||
-				!reflect.DeepEqual(dep.Spec.Template.Spec.Containers[0].Image, deploymentFound.Spec.Template.Spec.Containers[0].Image) {
-				// Update Guide
-				if err := r.Update(context.TODO(), dep); err != nil {
+			if !equality.Semantic.DeepDerivative(dep.Spec.Template.Spec.Volumes, deploymentFound.Spec.Template.Spec.Volumes) ||
+				!equality.Semantic.DeepDerivative(dep.Spec.Template.Spec.Containers, deploymentFound.Spec.Template.Spec.Containers) {
+				// Update Portal
+				deploymentFound.Spec.Template.Spec.Volumes = dep.Spec.Template.Spec.Volumes
+				deploymentFound.Spec.Template.Spec.Containers = dep.Spec.Template.Spec.Containers
+				if err := r.Update(context.TODO(), deploymentFound); err != nil {
 					return reconcile.Result{}, err
 				}
-				log.Infof("Updated %s Deployment", dep.Name)
+				log.Infof("Updated %s Deployment", deploymentFound.Name)
 			}
 		}
 	}
 
 	// Create Service
-	service := kubernetes.NewService(workshop, r.Scheme, PORTAL_SERVICE_NAME, workshop.Namespace, RedisLabels, []string{"http"}, []int32{8080})
+	service := kubernetes.NewService(workshop, r.Scheme, PORTAL_SERVICE_NAME, workshop.Namespace, PortalLabels, []string{"http"}, []int32{portal.Port})
 	if err := r.Create(context.TODO(), service); err != nil && !errors.IsAlreadyExists(err) {
 		return reconcile.Result{}, err
 	} else if err == nil {
@@ -135,7 +186,7 @@ func (r *WorkshopReconciler) addUpdateUsernameDistribution(workshop *workshopv1.
 	}
 
 	// Create Route
-	route := kubernetes.NewSecuredRoute(workshop, r.Scheme, PORTAL_ROUTE_NAME, workshop.Namespace, RedisLabels, PORTAL_SERVICE_NAME, int32(PORTAL_ROUTE_PORT))
+	route := kubernetes.NewSecuredRoute(workshop, r.Scheme, PORTAL_ROUTE_NAME, workshop.Namespace, PortalLabels, PORTAL_SERVICE_NAME, int32(portal.Port))
 	if err := r.Create(context.TODO(), route); err != nil && !errors.IsAlreadyExists(err) {
 		return reconcile.Result{}, err
 	} else if err == nil {
@@ -146,86 +197,210 @@ func (r *WorkshopReconciler) addUpdateUsernameDistribution(workshop *workshopv1.
 	return reconcile.Result{}, nil
 }
 
-// delete Redis
-func (r *WorkshopReconciler) deletePortal(workshop *workshopv1.Workshop, userID int,
-	appsHostnameSuffix string, openshiftConsoleURL string) (reconcile.Result, error) {
+// newPortalConfig builds the credentials and links shown to attendees from the enabled components
+func (r *WorkshopReconciler) newPortalConfig(workshop *workshopv1.Workshop, users int,
+	appsHostnameSuffix string, openshiftConsoleURL string) *portal.Config {
 
-	if result, err := r.deleteUsernameDistribution(workshop, userID, appsHostnameSuffix, openshiftConsoleURL); util.IsRequeued(result, err) {
-		return result, err
+	infrastructure := workshop.Spec.Infrastructure
+	password := workshop.Spec.User.Password
+
+	title := infrastructure.Portal.Title
+	if title == "" {
+		title = PORTAL_DEFAULT_TITLE
 	}
-	if result, err := r.deleteRedis(workshop); util.IsRequeued(result, err) {
-		return result, err
+
+	config := &portal.Config{
+		Title:       title,
+		UserPrefix:  "user",
+		Users:       users,
+		AccessToken: password,
+		Credentials: []portal.Item{
+			{Name: "Username", Value: portal.UsernamePlaceholder},
+			{Name: "Password", Value: password},
+		},
+		Links: []portal.Item{
+			{Name: "OpenShift Console", Value: openshiftConsoleURL},
+		},
 	}
 
-	return reconcile.Result{}, nil
-}
+	if infrastructure.Guide.Bookbag.Enabled {
+		config.Links = append(config.Links, portal.Item{
+			Name:  "Guide",
+			Value: fmt.Sprintf("http://%s-bookbag-%s.%s", portal.UsernamePlaceholder, BOOKBAG_NAMESPACE_NAME, appsHostnameSuffix),
+		})
+	}
 
-// delete Redis
-func (r *WorkshopReconciler) deleteRedis(workshop *workshopv1.Workshop) (reconcile.Result, error) {
-	log.Info("Deleting Redis")
-	service := kubernetes.NewService(workshop, r.Scheme, REDIS_SERVICE_NAME, workshop.Namespace, RedisLabels, []string{"http"}, []int32{6379})
-	// Delete Service
-	if err := r.Delete(context.TODO(), service); err != nil {
-		return reconcile.Result{}, err
+	if infrastructure.Guide.Scholars.Enabled {
+		guideURLParameters := "APPS_HOSTNAME_SUFFIX=" + appsHostnameSuffix +
+			"&USER_ID=" + portal.UserIDPlaceholder +
+			"&OPENSHIFT_PASSWORD=" + url.QueryEscape(password) +
+			"&WORKSHOP_GIT_REPO=" + url.QueryEscape(workshop.Spec.Source.GitURL) +
+			"&WORKSHOP_GIT_REF=" + workshop.Spec.Source.GitBranch
+		for _, guideName := range util.SortedKeys(infrastructure.Guide.Scholars.GuideURL) {
+			config.Links = append(config.Links, portal.Item{
+				Name:  guideName,
+				Value: fmt.Sprintf("%s?%s", infrastructure.Guide.Scholars.GuideURL[guideName], guideURLParameters),
+			})
+		}
 	}
-	log.Infof("Deleted %s Service", service.Name)
 
-	dep := redis.NewDeployment(workshop, r.Scheme, REDIS_DEPLOYMENT_NAME, workshop.Namespace, RedisLabels)
-	// Delete Deployment
-	if err := r.Delete(context.TODO(), dep); err != nil {
-		return reconcile.Result{}, err
+	if infrastructure.CodeReadyWorkspace.Enabled {
+		config.Links = append(config.Links, portal.Item{
+			Name:  "CodeReady Workspaces",
+			Value: fmt.Sprintf("https://%s-%s.%s", CHE_CODE_FLAVOR_NAME, CODEREADY_NAMESPACE_NAME, appsHostnameSuffix),
+		})
 	}
-	log.Infof("Deleted %s Deployment ", dep.Name)
 
-	persistentVolumeClaim := kubernetes.NewPersistentVolumeClaim(workshop, r.Scheme, REDIS_PVC_NAME, workshop.Namespace, RedisLabels, REDIS_VOLUME_SIZE)
-	// Delete persistentVolume Claim
-	if err := r.Delete(context.TODO(), persistentVolumeClaim); err != nil {
-		return reconcile.Result{}, err
+	if infrastructure.Gitea.Enabled || git.ProviderName(workshop) != git.ProviderGitea {
+		serverURL := gitServerURL(workshop, appsHostnameSuffix)
+		config.Links = append(config.Links, portal.Item{
+			Name:  "Git",
+			Value: serverURL,
+		})
+		for _, repository := range git.Repositories(workshop) {
+			config.Links = append(config.Links, portal.Item{
+				Name:  fmt.Sprintf("Repository %s", repository.Name),
+				Value: gitRepositoryURL(workshop, serverURL, portal.UsernamePlaceholder, repository.Name),
+			})
+		}
 	}
-	log.Infof("Deleted %s Persistent Volume Claim", persistentVolumeClaim.Name)
 
-	secret := kubernetes.NewStringDataSecret(workshop, r.Scheme, REDIS_SECRET_NAME, workshop.Namespace, RedisLabels, RedisCredentials)
-	// Delete secret
-	if err := r.Delete(context.TODO(), secret); err != nil {
-		return reconcile.Result{}, err
+	if infrastructure.GitOps.Enabled && isGitOpsPerUser(workshop) {
+		// Every user has its own Argo CD in its staging project, logging in as themselves
+		config.Links = append(config.Links, portal.Item{
+			Name: "Argo CD",
+			Value: fmt.Sprintf("https://%s-%s%s.%s", ARGOCD_DEPLOYMENT_NAME, infrastructure.Project.StagingName,
+				portal.UserIDPlaceholder, appsHostnameSuffix),
+		})
+	} else if infrastructure.GitOps.Enabled {
+		config.Links = append(config.Links, portal.Item{
+			Name:  "Argo CD",
+			Value: fmt.Sprintf("https://%s-%s.%s", ARGOCD_DEPLOYMENT_NAME, ARGOCD_NAMESPACE_NAME, appsHostnameSuffix),
+		})
 	}
-	log.Infof("Deleted %s Secret", secret.Name)
-	log.Info("Deleted Redis successfully")
 
-	//Success
-	return reconcile.Result{}, nil
+	if infrastructure.Nexus.Enabled {
+		nexusRouteName := NEXUSDEPLOYMENTNAME
+		if infrastructure.SSO.Enabled {
+			nexusRouteName = NEXUSSSOPROXYNAME
+		}
+		config.Links = append(config.Links, portal.Item{
+			Name:  "Nexus",
+			Value: fmt.Sprintf("https://%s-%s.%s", nexusRouteName, NEXUSNAMESPACENAME, appsHostnameSuffix),
+		})
+	}
+
+	// The SASL credentials of every user are in the Kafka credentials Secret of its staging project
+	if infrastructure.Kafka.Enabled {
+		kafkaNamespace := KAFKA_NAMESPACE_NAME
+		if isKafkaPerUser(workshop) {
+			kafkaNamespace = infrastructure.Project.StagingName + portal.UserIDPlaceholder
+		}
+		config.Credentials = append(config.Credentials,
+			portal.Item{Name: "Kafka Bootstrap Servers", Value: kafka.BootstrapServers(KAFKA_CLUSTER_NAME, kafkaNamespace)},
+			portal.Item{Name: "Kafka Credentials Secret", Value: KAFKA_CREDENTIALS_SECRET_NAME},
+		)
+	}
+
+	// With the perUser tenancy, every user has Kiali and Jaeger in the namespace of its control plane, upstream Istio
+	// being always shared
+	istioNamespace := ISTIO_NAMESPACE_NAME
+	if isServiceMeshPerUser(workshop) && !isServiceMeshIstio(workshop) {
+		istioNamespace = serviceMeshUserNamespace(portal.UsernamePlaceholder)
+	}
+
+	if infrastructure.ServiceMesh.Enabled && maistra.KialiEnabled(workshop) {
+		config.Links = append(config.Links, portal.Item{
+			Name:  "Kiali",
+			Value: fmt.Sprintf("https://%s-%s.%s", KIALI_NAME, istioNamespace, appsHostnameSuffix),
+		})
+	}
+
+	if infrastructure.ServiceMesh.Enabled && maistra.JaegerEnabled(workshop) {
+		config.Links = append(config.Links, portal.Item{
+			Name:  "Jaeger",
+			Value: fmt.Sprintf("https://jaeger-%s.%s", istioNamespace, appsHostnameSuffix),
+		})
+	}
+
+	return config
 }
 
-// delete UsernameDistribution
-func (r *WorkshopReconciler) deleteUsernameDistribution(workshop *workshopv1.Workshop,
-	users int, appsHostnameSuffix string, openshiftConsoleURL string) (reconcile.Result, error) {
+// delete Portal
+func (r *WorkshopReconciler) deletePortal(workshop *workshopv1.Workshop, users int,
+	appsHostnameSuffix string, openshiftConsoleURL string) (reconcile.Result, error) {
 
-	log.Info("Deleting  portal ")
-	route := kubernetes.NewSecuredRoute(workshop, r.Scheme, PORTAL_ROUTE_NAME, workshop.Namespace, RedisLabels, PORTAL_SERVICE_NAME, int32(PORTAL_ROUTE_PORT))
+	log.Info("Deleting portal")
+	route := kubernetes.NewSecuredRoute(workshop, r.Scheme, PORTAL_ROUTE_NAME, workshop.Namespace, PortalLabels, PORTAL_SERVICE_NAME, int32(portal.Port))
 	// Delete Route
-	if err := r.Delete(context.TODO(), route); err != nil {
+	if err := r.Delete(context.TODO(), route); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s Route", route.Name)
 
-	service := kubernetes.NewService(workshop, r.Scheme, PORTAL_SERVICE_NAME, workshop.Namespace, RedisLabels, []string{"http"}, []int32{8080})
+	service := kubernetes.NewService(workshop, r.Scheme, PORTAL_SERVICE_NAME, workshop.Namespace, PortalLabels, []string{"http"}, []int32{portal.Port})
 	// Delete Service
-	if err := r.Delete(context.TODO(), service); err != nil {
+	if err := r.Delete(context.TODO(), service); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s Service", service.Name)
 
-	dep := usernamedistribution.NewDeployment(workshop, r.Scheme, PORTAL_DEPLOYMENT_NAME, RedisLabels, REDIS_SERVICE_NAME, users, appsHostnameSuffix, openshiftConsoleURL)
+	dep := portal.NewDeployment(workshop, r.Scheme, PORTAL_DEPLOYMENT_NAME, workshop.Namespace, PortalLabels,
+		PORTAL_SERVICEACCOUNT_NAME, PORTAL_CONFIG_SECRET_NAME, PORTAL_ADMIN_SECRET_NAME, PORTAL_CLAIMS_CONFIGMAP_NAME)
 	deploymentFound := &appsv1.Deployment{}
 	deploymentErr := r.Get(context.TODO(), types.NamespacedName{Name: dep.Name, Namespace: workshop.Namespace}, deploymentFound)
 	if deploymentErr == nil {
 		// Delete Deployment
-		if err := r.Delete(context.TODO(), dep); err != nil {
+		if err := r.Delete(context.TODO(), dep); err != nil && !errors.IsNotFound(err) {
 			return reconcile.Result{}, err
 		}
 		log.Infof("Deleted %s Deployment", dep.Name)
 	}
-	log.Info(" Deleted portal  successfully")
+
+	configSecret := kubernetes.NewStringDataSecret(workshop, r.Scheme, PORTAL_CONFIG_SECRET_NAME, workshop.Namespace, PortalLabels, nil)
+	// Delete Config Secret
+	if err := r.Delete(context.TODO(), configSecret); err != nil && !errors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+	log.Infof("Deleted %s Secret", configSecret.Name)
+
+	adminSecret := kubernetes.NewStringDataSecret(workshop, r.Scheme, PORTAL_ADMIN_SECRET_NAME, workshop.Namespace, PortalLabels, nil)
+	// Delete Admin Secret
+	if err := r.Delete(context.TODO(), adminSecret); err != nil && !errors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+	log.Infof("Deleted %s Secret", adminSecret.Name)
+
+	claimsConfigMap := kubernetes.NewConfigMap(workshop, r.Scheme, PORTAL_CLAIMS_CONFIGMAP_NAME, workshop.Namespace, PortalLabels, nil)
+	// Delete Claims ConfigMap
+	if err := r.Delete(context.TODO(), claimsConfigMap); err != nil && !errors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+	log.Infof("Deleted %s ConfigMap", claimsConfigMap.Name)
+
+	roleBinding := kubernetes.NewRoleBindingSA(workshop, r.Scheme, PORTAL_ROLE_BINDING_NAME, workshop.Namespace, PortalLabels,
+		PORTAL_SERVICEACCOUNT_NAME, PORTAL_ROLE_NAME, PORTAL_ROLE_KIND_NAME)
+	// Delete Role Binding
+	if err := r.Delete(context.TODO(), roleBinding); err != nil && !errors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+	log.Infof("Deleted %s Role Binding", roleBinding.Name)
+
+	role := kubernetes.NewRole(workshop, r.Scheme, PORTAL_ROLE_NAME, workshop.Namespace, PortalLabels, kubernetes.PortalRules(PORTAL_CLAIMS_CONFIGMAP_NAME))
+	// Delete Role
+	if err := r.Delete(context.TODO(), role); err != nil && !errors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+	log.Infof("Deleted %s Role", role.Name)
+
+	serviceAccount := kubernetes.NewServiceAccount(workshop, r.Scheme, PORTAL_SERVICEACCOUNT_NAME, workshop.Namespace, PortalLabels)
+	// Delete Service Account
+	if err := r.Delete(context.TODO(), serviceAccount); err != nil && !errors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+	log.Infof("Deleted %s Service Account", serviceAccount.Name)
+
+	log.Info("Deleted portal successfully")
 	//Success
 	return reconcile.Result{}, nil
 }
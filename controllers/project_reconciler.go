@@ -168,7 +168,7 @@ func (r *WorkshopReconciler) deleteProjectNamespace(workshop *workshopv1.Worksho
 	}
 
 	// Delete a Project
-	if err := r.Delete(context.TODO(), projectNamespace); err != nil {
+	if err := r.Delete(context.TODO(), projectNamespace); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s Namespace ", projectNamespace.Name)
@@ -199,7 +199,7 @@ func (r *WorkshopReconciler) deleteManageRoles(workshop *workshopv1.Workshop, pr
 	argocdEditRoleBinding := kubernetes.NewRoleBindingUsers(workshop, r.Scheme,
 		username+"-argocd", projectName, projectLabels, argocdUsers, ARGOCD_EDIT_ROLE_BINDING_NAME, KIND_CLUSTER_ROLE)
 	// Delete Argo CD Role Binding
-	if err := r.Delete(context.TODO(), argocdEditRoleBinding); err != nil {
+	if err := r.Delete(context.TODO(), argocdEditRoleBinding); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s Role Binding", argocdEditRoleBinding.Name)
@@ -207,7 +207,7 @@ func (r *WorkshopReconciler) deleteManageRoles(workshop *workshopv1.Workshop, pr
 	defaultRoleBinding := kubernetes.NewRoleBindingSA(workshop, r.Scheme, username+"-default", projectName, projectLabels,
 		PROJECT_SERVICEACCOUNT_NAME, DEFAULT_ROLE_BINDING_NAME, KIND_CLUSTER_ROLE)
 	// Delete default Role Binding
-	if err := r.Delete(context.TODO(), defaultRoleBinding); err != nil {
+	if err := r.Delete(context.TODO(), defaultRoleBinding); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s Role Binding", defaultRoleBinding.Name)
@@ -215,7 +215,7 @@ func (r *WorkshopReconciler) deleteManageRoles(workshop *workshopv1.Workshop, pr
 	userRoleBinding := kubernetes.NewRoleBindingUsers(workshop, r.Scheme, username+"-project", projectName, projectLabels,
 		users, USER_ROLE_BINDING_NAME, KIND_CLUSTER_ROLE)
 	// Delete user Role Binding
-	if err := r.Delete(context.TODO(), userRoleBinding); err != nil {
+	if err := r.Delete(context.TODO(), userRoleBinding); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s Role Binding", userRoleBinding.Name)
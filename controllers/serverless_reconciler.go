@@ -2,23 +2,51 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/prometheus/common/log"
 	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/knative"
 	"github.com/stakater/workshop-operator/common/kubernetes"
 	"github.com/stakater/workshop-operator/common/util"
-
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+const (
+	SERVERLESS_NAMESPACE_NAME            = "openshift-serverless"
+	SERVERLESS_OPERATORGROUP_NAME        = "serverless-operators"
+	SERVERLESS_SUBSCRIPTION_NAME         = "serverless-operator"
+	SERVERLESS_SUBSCRIPTION_PACKAGE_NAME = "serverless-operator"
+	SERVERLESS_OPERATOR_DEPLOYMENT_NAME  = "knative-operator"
+	KNATIVE_SERVING_NAMESPACE_NAME       = "knative-serving"
+	KNATIVE_SERVING_CR_NAME              = "knative-serving"
+	KNATIVE_EVENTING_NAMESPACE_NAME      = "knative-eventing"
+	KNATIVE_EVENTING_CR_NAME             = "knative-eventing"
+)
+
+var knativeLabels = map[string]string{
+	"app.kubernetes.io/part-of": "knative",
+}
+
+var knativeBrokerLabels = map[string]string{
+	"app.kubernetes.io/part-of": "knative",
+	"app.kubernetes.io/name":    "broker",
+}
+
 // Reconciling Serverless
-func (r *WorkshopReconciler) reconcileServerless(workshop *workshopv1.Workshop) (reconcile.Result, error) {
+func (r *WorkshopReconciler) reconcileServerless(workshop *workshopv1.Workshop, users int) (reconcile.Result, error) {
 	enabledServerless := workshop.Spec.Infrastructure.Serverless.Enabled
 
 	if enabledServerless {
 
-		if result, err := r.addServerless(workshop); util.IsRequeued(result, err) {
+		if result, err := r.addServerless(workshop, users); util.IsRequeued(result, err) {
 			return result, err
 		}
 	}
@@ -28,19 +56,28 @@ func (r *WorkshopReconciler) reconcileServerless(workshop *workshopv1.Workshop)
 }
 
 // Add Serverless
-func (r *WorkshopReconciler) addServerless(workshop *workshopv1.Workshop) (reconcile.Result, error) {
+func (r *WorkshopReconciler) addServerless(workshop *workshopv1.Workshop, users int) (reconcile.Result, error) {
 
 	channel := workshop.Spec.Infrastructure.Serverless.OperatorHub.Channel
 	clusterServiceVersion := workshop.Spec.Infrastructure.Serverless.OperatorHub.ClusterServiceVersion
 
-	namespace := kubernetes.NewNamespace(workshop, r.Scheme, "openshift-serverless")
+	namespace := kubernetes.NewNamespace(workshop, r.Scheme, SERVERLESS_NAMESPACE_NAME)
 	if err := r.Create(context.TODO(), namespace); err != nil && !errors.IsAlreadyExists(err) {
 		return reconcile.Result{}, err
 	} else if err == nil {
 		log.Infof("Created %s Project", namespace.Name)
 	}
 
-	subscription := kubernetes.NewRedHatSubscription(workshop, r.Scheme, "serverless-operator", namespace.Name, "serverless-operator",
+	// The Serverless Operator only supports the AllNamespaces install mode
+	operatorGroup := kubernetes.NewOperatorGroup(workshop, r.Scheme, SERVERLESS_OPERATORGROUP_NAME, namespace.Name)
+	operatorGroup.Spec.TargetNamespaces = nil
+	if err := r.Create(context.TODO(), operatorGroup); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s OperatorGroup", operatorGroup.Name)
+	}
+
+	subscription := kubernetes.NewRedHatSubscription(workshop, r.Scheme, SERVERLESS_SUBSCRIPTION_NAME, namespace.Name, SERVERLESS_SUBSCRIPTION_PACKAGE_NAME,
 		channel, clusterServiceVersion)
 	if err := r.Create(context.TODO(), subscription); err != nil && !errors.IsAlreadyExists(err) {
 		return reconcile.Result{}, err
@@ -48,14 +85,24 @@ func (r *WorkshopReconciler) addServerless(workshop *workshopv1.Workshop) (recon
 		log.Infof("Created %s Subscription", subscription.Name)
 	}
 
-	knativeServingNamespace := kubernetes.NewNamespace(workshop, r.Scheme, "knative-serving")
+	if err := r.ApproveInstallPlan(clusterServiceVersion, SERVERLESS_SUBSCRIPTION_NAME, namespace.Name); err != nil {
+		log.Infof("Waiting for Subscription to create InstallPlan for %s", subscription.Name)
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	// Wait for Operator to be running
+	if !kubernetes.GetK8Client().GetDeploymentStatus(SERVERLESS_OPERATOR_DEPLOYMENT_NAME, namespace.Name) {
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	knativeServingNamespace := kubernetes.NewNamespace(workshop, r.Scheme, KNATIVE_SERVING_NAMESPACE_NAME)
 	if err := r.Create(context.TODO(), knativeServingNamespace); err != nil && !errors.IsAlreadyExists(err) {
 		return reconcile.Result{}, err
 	} else if err == nil {
 		log.Infof("Created %s Namespace", knativeServingNamespace.Name)
 	}
 
-	knativeEventingNamespace := kubernetes.NewNamespace(workshop, r.Scheme, "knative-eventing")
+	knativeEventingNamespace := kubernetes.NewNamespace(workshop, r.Scheme, KNATIVE_EVENTING_NAMESPACE_NAME)
 	if err := r.Create(context.TODO(), knativeEventingNamespace); err != nil && !errors.IsAlreadyExists(err) {
 		return reconcile.Result{}, err
 	} else if err == nil {
@@ -64,72 +111,206 @@ func (r *WorkshopReconciler) addServerless(workshop *workshopv1.Workshop) (recon
 
 	// TODO
 	// Add  knativeServingNamespace to ServiceMeshMember
-	// Create CR
+
+	knativeServingCR, err := knative.NewKnativeServingCR(workshop, r.Scheme, KNATIVE_SERVING_CR_NAME, KNATIVE_SERVING_NAMESPACE_NAME, knativeLabels)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	knativeServingFound, err := r.addKnativeCustomResource(knativeServingCR)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	knativeEventingCR, err := knative.NewKnativeEventingCR(workshop, r.Scheme, KNATIVE_EVENTING_CR_NAME, KNATIVE_EVENTING_NAMESPACE_NAME, knativeLabels)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	knativeEventingFound, err := r.addKnativeCustomResource(knativeEventingCR)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Record the readiness of Serving and Eventing
+	knativeStatus := workshopv1.KnativeStatus{}
+	knativeStatus.Serving.Ready, knativeStatus.Serving.Message = kubernetes.ReadyCondition(knativeServingFound)
+	knativeStatus.Eventing.Ready, knativeStatus.Eventing.Message = kubernetes.ReadyCondition(knativeEventingFound)
+	if workshop.Status.Knative != knativeStatus {
+		if err := r.updateStatus(workshop, func(status *workshopv1.WorkshopStatus) {
+			status.Knative = knativeStatus
+		}); err != nil {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Knative Serving ready: %t, Knative Eventing ready: %t", knativeStatus.Serving.Ready, knativeStatus.Eventing.Ready)
+	}
+
+	// Wait for Serving and Eventing to be ready, their conditions not being watched
+	if !knativeStatus.Serving.Ready || !knativeStatus.Eventing.Ready {
+		return reconcile.Result{RequeueAfter: time.Second * 10}, nil
+	}
+
+	if result, err := r.addKnativeBrokers(workshop, users); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// addKnativeCustomResource creates a KnativeServing or KnativeEventing, or updates it when its configuration changed,
+// and returns it with its status
+func (r *WorkshopReconciler) addKnativeCustomResource(customResource *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+
+	customResourceFound := &unstructured.Unstructured{}
+	customResourceFound.SetGroupVersionKind(customResource.GroupVersionKind())
+	if err := r.Create(context.TODO(), customResource); err != nil && !errors.IsAlreadyExists(err) {
+		return nil, err
+	} else if err == nil {
+		log.Infof("Created %s Custom Resource", customResource.GetName())
+		return customResource, nil
+	}
+
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: customResource.GetName(), Namespace: customResource.GetNamespace()}, customResourceFound); err != nil {
+		return nil, err
+	} else if customResourceFound.GetAnnotations()[kubernetes.ChecksumAnnotation] != customResource.GetAnnotations()[kubernetes.ChecksumAnnotation] {
+		// Only the fields set by the Workshop are replaced, the other ones being defaulted by the operator
+		spec, _, _ := unstructured.NestedMap(customResourceFound.Object, "spec")
+		if spec == nil {
+			spec = map[string]interface{}{}
+		}
+		for key, value := range customResource.Object["spec"].(map[string]interface{}) {
+			spec[key] = value
+		}
+		customResourceFound.Object["spec"] = spec
+		annotations := customResourceFound.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[kubernetes.ChecksumAnnotation] = customResource.GetAnnotations()[kubernetes.ChecksumAnnotation]
+		customResourceFound.SetAnnotations(annotations)
+		if err := r.Update(context.TODO(), customResourceFound); err != nil {
+			return nil, err
+		}
+		log.Infof("Updated %s Custom Resource", customResourceFound.GetName())
+	}
+
+	return customResourceFound, nil
+}
+
+// Add the Brokers of the staging project of every user, and delete the ones removed from the spec
+func (r *WorkshopReconciler) addKnativeBrokers(workshop *workshopv1.Workshop, users int) (reconcile.Result, error) {
+
+	brokers := workshop.Spec.Infrastructure.Serverless.Eventing.Brokers
+
+	for id := 1; id <= users; id++ {
+		projectName := fmt.Sprintf("%s%d", workshop.Spec.Infrastructure.Project.StagingName, id)
+
+		for _, brokerName := range brokers {
+			broker := knative.NewBrokerCR(workshop, r.Scheme, brokerName, projectName, knativeBrokerLabels)
+			if err := r.Create(context.TODO(), broker); err != nil && !errors.IsAlreadyExists(err) {
+				return reconcile.Result{}, err
+			} else if err == nil {
+				log.Infof("Created %s Broker in %s namespace", broker.GetName(), projectName)
+			}
+		}
+
+		brokerList := &unstructured.UnstructuredList{}
+		brokerList.SetGroupVersionKind(knative.BrokerGroupVersionKind.GroupVersion().WithKind("BrokerList"))
+		if err := r.List(context.TODO(), brokerList, client.InNamespace(projectName), client.MatchingLabels(knativeBrokerLabels)); err != nil {
+			return reconcile.Result{}, err
+		}
+		for i := range brokerList.Items {
+			broker := &brokerList.Items[i]
+			if util.StringInSlice(broker.GetName(), brokers) {
+				continue
+			}
+			if err := r.Delete(context.TODO(), broker); err != nil && !errors.IsNotFound(err) {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Deleted %s Broker in %s namespace", broker.GetName(), projectName)
+		}
+	}
 
 	//Success
 	return reconcile.Result{}, nil
 }
 
-/**
 // delete Serverless
-func (r *WorkshopReconciler) deleteServerless(workshop *workshopv1.Workshop) (reconcile.Result, error) {
+func (r *WorkshopReconciler) deleteServerless(workshop *workshopv1.Workshop, users int) (reconcile.Result, error) {
 
 	channel := workshop.Spec.Infrastructure.Serverless.OperatorHub.Channel
 	clusterServiceVersion := workshop.Spec.Infrastructure.Serverless.OperatorHub.ClusterServiceVersion
 
-	namespace := kubernetes.NewNamespace(workshop, r.Scheme, "openshift-serverless")
-	knativeServingNamespace := kubernetes.NewNamespace(workshop, r.Scheme, "knative-serving")
-
-	knativeEventingNamespace := kubernetes.NewNamespace(workshop, r.Scheme, "knative-eventing")
-	knativeEventingNamespaceFound := &corev1.Namespace{}
-	knativeEventingNamespaceErr := r.Get(context.TODO(), types.NamespacedName{Name: knativeEventingNamespace.Name}, knativeEventingNamespaceFound)
-	if knativeEventingNamespaceErr == nil {
-		//Delete knativeEventing Namespace
-		if err := r.Delete(context.TODO(), knativeEventingNamespace); err != nil {
-			return reconcile.Result{}, err
+	// Delete the Custom Resources while the operator can clean up the Knative components
+	for id := 1; id <= users; id++ {
+		projectName := fmt.Sprintf("%s%d", workshop.Spec.Infrastructure.Project.StagingName, id)
+		for _, brokerName := range workshop.Spec.Infrastructure.Serverless.Eventing.Brokers {
+			broker := knative.NewBrokerCR(workshop, r.Scheme, brokerName, projectName, knativeBrokerLabels)
+			if err := r.Delete(context.TODO(), broker); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+				return reconcile.Result{}, err
+			}
 		}
-		log.Infof("Deleted %s Namespace", knativeEventingNamespace.Name)
 	}
 
-	knativeServingNamespaceFound := &corev1.Namespace{}
-	knativeServingNamespaceErr := r.Get(context.TODO(), types.NamespacedName{Name: knativeServingNamespace.Name}, knativeServingNamespaceFound)
-	if knativeServingNamespaceErr == nil {
-		//Delete knativeServing Namespace
-		if err := r.Delete(context.TODO(), knativeServingNamespace); err != nil {
+	knativeEventing := &unstructured.Unstructured{}
+	knativeEventing.SetGroupVersionKind(knative.KnativeEventingGroupVersionKind)
+	knativeEventing.SetName(KNATIVE_EVENTING_CR_NAME)
+	knativeEventing.SetNamespace(KNATIVE_EVENTING_NAMESPACE_NAME)
+
+	knativeServing := &unstructured.Unstructured{}
+	knativeServing.SetGroupVersionKind(knative.KnativeServingGroupVersionKind)
+	knativeServing.SetName(KNATIVE_SERVING_CR_NAME)
+	knativeServing.SetNamespace(KNATIVE_SERVING_NAMESPACE_NAME)
+
+	for _, customResource := range []*unstructured.Unstructured{knativeEventing, knativeServing} {
+		// Delete Custom Resource
+		if err := r.Delete(context.TODO(), customResource); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
 			return reconcile.Result{}, err
+		} else if err == nil {
+			log.Infof("Deleted %s Custom Resource", customResource.GetName())
+		}
+
+		// Wait for the operator to remove its finalizer
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: customResource.GetName(), Namespace: customResource.GetNamespace()}, customResource); err == nil {
+			return reconcile.Result{Requeue: true}, nil
 		}
-		log.Infof("Deleted %s Namespace", knativeServingNamespace.Name)
 	}
 
-	subscription := kubernetes.NewRedHatSubscription(workshop, r.Scheme, "serverless-operator", namespace.Name, "serverless-operator",
+	subscription := kubernetes.NewRedHatSubscription(workshop, r.Scheme, SERVERLESS_SUBSCRIPTION_NAME, SERVERLESS_NAMESPACE_NAME, SERVERLESS_SUBSCRIPTION_PACKAGE_NAME,
 		channel, clusterServiceVersion)
 	subscriptionFound := &olmv1alpha1.Subscription{}
-	subscriptionErr := r.Get(context.TODO(), types.NamespacedName{Name: subscription.Name, Namespace: namespace.Name}, subscriptionFound)
-	if subscriptionErr == nil {
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: subscription.Name, Namespace: SERVERLESS_NAMESPACE_NAME}, subscriptionFound); err == nil {
 		//Delete subscription
-		if err := r.Delete(context.TODO(), subscription); err != nil {
+		if err := r.Delete(context.TODO(), subscription); err != nil && !errors.IsNotFound(err) {
 			return reconcile.Result{}, err
 		}
 		log.Infof("Deleted %s Subscription", subscription.Name)
-	}
 
-	namespaceFound := &corev1.Namespace{}
-	namespaceErr := r.Get(context.TODO(), types.NamespacedName{Name: namespace.Name}, namespaceFound)
-	if namespaceErr == nil {
-		// Delete namespace
-		if err := r.Delete(context.TODO(), namespace); err != nil {
-			return reconcile.Result{}, err
+		if subscriptionFound.Status.InstalledCSV != "" {
+			operatorCSV := kubernetes.NewRedHatClusterServiceVersion(workshop, r.Scheme, subscriptionFound.Status.InstalledCSV, SERVERLESS_NAMESPACE_NAME)
+			// Delete ClusterServiceVersion
+			if err := r.Delete(context.TODO(), operatorCSV); err != nil && !errors.IsNotFound(err) {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Deleted %s ClusterServiceVersion", operatorCSV.Name)
 		}
-		log.Infof("Deleted %s namespace", namespace.Name)
 	}
 
-	//
+	operatorGroup := kubernetes.NewOperatorGroup(workshop, r.Scheme, SERVERLESS_OPERATORGROUP_NAME, SERVERLESS_NAMESPACE_NAME)
+	// Delete OperatorGroup
+	if err := r.Delete(context.TODO(), operatorGroup); err != nil && !errors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+	log.Infof("Deleted %s OperatorGroup", operatorGroup.Name)
 
-	// TODO
-	// Add  knativeServingNamespace to ServiceMeshMember
-	// Delete CR
+	for _, namespaceName := range []string{KNATIVE_EVENTING_NAMESPACE_NAME, KNATIVE_SERVING_NAMESPACE_NAME, SERVERLESS_NAMESPACE_NAME} {
+		namespace := kubernetes.NewNamespace(workshop, r.Scheme, namespaceName)
+		// Delete Namespace
+		if err := r.Delete(context.TODO(), namespace); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s Namespace", namespace.Name)
+	}
 
 	//Success
 	return reconcile.Result{}, nil
 }
-**/
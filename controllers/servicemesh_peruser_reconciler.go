@@ -0,0 +1,209 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	maistrav1 "github.com/maistra/istio-operator/pkg/apis/maistra/v1"
+	maistrav2 "github.com/maistra/istio-operator/pkg/apis/maistra/v2"
+	"github.com/prometheus/common/log"
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/kubernetes"
+	"github.com/stakater/workshop-operator/common/portal"
+	"github.com/stakater/workshop-operator/common/util"
+	corev1 "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	SERVICE_MESH_TENANCY_PER_USER         = "perUser"
+	SERVICE_MESH_USER_NAMESPACE_SUFFIX    = "-istio"
+	SERVICE_MESH_VIEW_ROLE_BINDING_NAME   = "mesh-viewers"
+	SERVICE_MESH_VIEW_CLUSTER_ROLE_NAME   = "view"
+	SERVICE_MESH_VIEW_CLUSTER_ROLE_KIND   = "ClusterRole"
+	SERVICE_MESH_USER_NAMESPACE_COMPONENT = "istio-user"
+)
+
+// isServiceMeshPerUser returns true if every user gets its own control plane
+func isServiceMeshPerUser(workshop *workshopv1.Workshop) bool {
+	return workshop.Spec.Infrastructure.ServiceMesh.Tenancy == SERVICE_MESH_TENANCY_PER_USER
+}
+
+// serviceMeshUserNamespace returns the namespace of the control plane of a user
+func serviceMeshUserNamespace(username string) string {
+	return username + SERVICE_MESH_USER_NAMESPACE_SUFFIX
+}
+
+// serviceMeshUserMembers returns the members of the mesh of a user: its staging project and the extra members
+func serviceMeshUserMembers(workshop *workshopv1.Workshop, username string, projectName string) []string {
+	members := []string{projectName}
+	for _, member := range workshop.Spec.Infrastructure.ServiceMesh.ControlPlane.Members {
+		member = strings.ReplaceAll(member, portal.UsernamePlaceholder, username)
+		if !util.StringInSlice(member, members) {
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+// Add a control plane in the userN-istio namespace of every user, so that the users do not share their gateways
+// and mesh-wide policies
+func (r *WorkshopReconciler) addServiceMeshPerUser(workshop *workshopv1.Workshop, users int) (reconcile.Result, error) {
+
+	labels := map[string]string{
+		"app.kubernetes.io/part-of": "istio",
+		"app.kubernetes.io/name":    SERVICE_MESH_USER_NAMESPACE_COMPONENT,
+	}
+
+	for id := 1; id <= users; id++ {
+		username := fmt.Sprintf("user%d", id)
+		projectName := fmt.Sprintf("%s%d", workshop.Spec.Infrastructure.Project.StagingName, id)
+
+		if result, err := r.addServiceMeshUser(workshop, username, projectName, labels); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
+
+	// Delete the control planes of the users gone
+	if result, err := r.deleteServiceMeshUsers(workshop, users); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// Add the control plane of a user, with the access of the user to it, Kiali and Jaeger
+func (r *WorkshopReconciler) addServiceMeshUser(workshop *workshopv1.Workshop, username string, projectName string,
+	labels map[string]string) (reconcile.Result, error) {
+
+	namespace := serviceMeshUserNamespace(username)
+
+	istioNamespace := kubernetes.NewNamespace(workshop, r.Scheme, namespace)
+	istioNamespace.Labels = labels
+	if err := r.Create(context.TODO(), istioNamespace); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Namespace", istioNamespace.Name)
+	}
+
+	istioUsers := []rbac.Subject{
+		{
+			Kind:     rbac.UserKind,
+			Name:     username,
+			APIGroup: "rbac.authorization.k8s.io",
+		},
+	}
+	if workshop.Spec.Infrastructure.GitOps.Enabled {
+		istioUsers = append(istioUsers, rbac.Subject{
+			Kind: rbac.UserKind,
+			Name: fmt.Sprintf("system:serviceaccount:%s:%s%s", gitOpsNamespace(workshop, projectName),
+				ARGOCD_CUSTOMRESOURCE_NAME, ARGOCD_CONTROLLER_SA_SUFFIX),
+			APIGroup: "rbac.authorization.k8s.io",
+		})
+	}
+
+	jaegerRole := kubernetes.NewRole(workshop, r.Scheme,
+		JAEGER_ROLE_NAME, namespace, istioLabels, kubernetes.JaegerUserRules())
+	if err := r.Create(context.TODO(), jaegerRole); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Role in %s namespace", jaegerRole.Name, namespace)
+	}
+
+	// The mesh-user Role is created by the Service Mesh Operator, and Kiali and Jaeger let in the users who can
+	// view the namespace of the control plane
+	roleBindings := []*rbac.RoleBinding{
+		kubernetes.NewRoleBindingUsers(workshop, r.Scheme,
+			JAEGER_ROLE_BINDING_NAME, namespace, istioLabels, istioUsers, jaegerRole.Name, JAEGER_ROLE_KIND_NAME),
+		kubernetes.NewRoleBindingUsers(workshop, r.Scheme,
+			SERVICE_MESH_ROLE_BINDING_NAME, namespace, istioLabels, istioUsers, SERVICE_MESH_ROLE_NAME, SERVICE_MESH_ROLE_KIND_NAME),
+		kubernetes.NewRoleBindingUsers(workshop, r.Scheme,
+			SERVICE_MESH_VIEW_ROLE_BINDING_NAME, namespace, istioLabels, istioUsers[:1], SERVICE_MESH_VIEW_CLUSTER_ROLE_NAME, SERVICE_MESH_VIEW_CLUSTER_ROLE_KIND),
+	}
+	for _, roleBinding := range roleBindings {
+		if err := r.Create(context.TODO(), roleBinding); err != nil && !errors.IsAlreadyExists(err) {
+			return reconcile.Result{}, err
+		} else if err == nil {
+			log.Infof("Created %s Role Binding in %s namespace", roleBinding.Name, namespace)
+		} else if errors.IsAlreadyExists(err) {
+			found := &rbac.RoleBinding{}
+			if err := r.Get(context.TODO(), types.NamespacedName{Name: roleBinding.Name, Namespace: namespace}, found); err != nil {
+				return reconcile.Result{}, err
+			} else if !reflect.DeepEqual(roleBinding.Subjects, found.Subjects) {
+				found.Subjects = roleBinding.Subjects
+				if err := r.Update(context.TODO(), found); err != nil {
+					return reconcile.Result{}, err
+				}
+				log.Infof("Updated %s Role Binding in %s namespace", found.Name, namespace)
+			}
+		}
+	}
+
+	if result, err := r.addServiceMeshControlPlane(workshop, namespace); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	if result, err := r.addServiceMeshMemberRoll(workshop, namespace, serviceMeshUserMembers(workshop, username, projectName)); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// Delete the control planes of the users beyond the number of users, and their namespace
+func (r *WorkshopReconciler) deleteServiceMeshUsers(workshop *workshopv1.Workshop, users int) (reconcile.Result, error) {
+
+	namespaces := map[string]bool{}
+	for id := 1; id <= users; id++ {
+		namespaces[serviceMeshUserNamespace(fmt.Sprintf("user%d", id))] = true
+	}
+
+	namespaceList := &corev1.NamespaceList{}
+	if err := r.List(context.TODO(), namespaceList, client.MatchingLabels{
+		"app.kubernetes.io/part-of": "istio",
+		"app.kubernetes.io/name":    SERVICE_MESH_USER_NAMESPACE_COMPONENT,
+	}); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	for i := range namespaceList.Items {
+		namespace := &namespaceList.Items[i]
+		if namespaces[namespace.Name] || namespace.DeletionTimestamp != nil {
+			continue
+		}
+
+		// Delete the members and the control plane first, for the operator to clean up the member namespaces
+		serviceMeshMemberRoll := &maistrav1.ServiceMeshMemberRoll{}
+		serviceMeshMemberRoll.Name = SERVICE_MESH_MEMBER_ROLL_NAME
+		serviceMeshMemberRoll.Namespace = namespace.Name
+		if err := r.Delete(context.TODO(), serviceMeshMemberRoll); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s Service Mesh Member Roll Custom Resource in %s namespace", serviceMeshMemberRoll.Name, namespace.Name)
+
+		serviceMeshControlPlane := &maistrav2.ServiceMeshControlPlane{}
+		serviceMeshControlPlane.Name = SERVICE_MESH_CONTROL_PLANE_NAME
+		serviceMeshControlPlane.Namespace = namespace.Name
+		if err := r.Delete(context.TODO(), serviceMeshControlPlane); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s Service Mesh Control Plane Custom Resource in %s namespace", serviceMeshControlPlane.Name, namespace.Name)
+
+		// Delete Namespace
+		if err := r.Delete(context.TODO(), namespace); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s Namespace", namespace.Name)
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
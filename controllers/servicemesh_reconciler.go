@@ -15,6 +15,7 @@ import (
 	admissionregistration "k8s.io/api/admissionregistration/v1"
 	rbac "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"reflect"
@@ -57,11 +58,16 @@ var istioLabels = map[string]string{
 }
 
 // Reconciling ServiceMesh
-func (r *WorkshopReconciler) reconcileServiceMesh(workshop *workshopv1.Workshop, users int) (reconcile.Result, error) {
+func (r *WorkshopReconciler) reconcileServiceMesh(workshop *workshopv1.Workshop, users int, appsHostnameSuffix string) (reconcile.Result, error) {
 	enabledServiceMesh := workshop.Spec.Infrastructure.ServiceMesh.Enabled
 	enabledServerless := workshop.Spec.Infrastructure.Serverless.Enabled
 
-	if enabledServiceMesh || enabledServerless {
+	if (enabledServiceMesh || enabledServerless) && isServiceMeshIstio(workshop) {
+
+		if result, err := r.addIstio(workshop, users, appsHostnameSuffix); util.IsRequeued(result, err) {
+			return result, err
+		}
+	} else if enabledServiceMesh || enabledServerless {
 
 		if result, err := r.addElasticSearchOperator(workshop); util.IsRequeued(result, err) {
 			return result, err
@@ -108,6 +114,10 @@ func (r *WorkshopReconciler) addServiceMesh(workshop *workshopv1.Workshop, users
 		return reconcile.Result{Requeue: true}, nil
 	}
 
+	if isServiceMeshPerUser(workshop) {
+		return r.addServiceMeshPerUser(workshop, users)
+	}
+
 	istioSystemNamespace := kubernetes.NewNamespace(workshop, r.Scheme, ISTIO_NAMESPACE_NAME)
 	if err := r.Create(context.TODO(), istioSystemNamespace); err != nil && !errors.IsAlreadyExists(err) {
 		return reconcile.Result{}, err
@@ -178,33 +188,88 @@ func (r *WorkshopReconciler) addServiceMesh(workshop *workshopv1.Workshop, users
 		log.Infof("Created %s Role Binding", meshUserRoleBinding.Name)
 	}
 
-	serviceMeshControlPlaneCR := maistra.NewServiceMeshControlPlaneCR(workshop, r.Scheme, SERVICE_MESH_CONTROL_PLANE_NAME, istioSystemNamespace.Name)
+	if result, err := r.addServiceMeshControlPlane(workshop, istioSystemNamespace.Name); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	for _, member := range workshop.Spec.Infrastructure.ServiceMesh.ControlPlane.Members {
+		if !util.StringInSlice(member, istioMembers) {
+			istioMembers = append(istioMembers, member)
+		}
+	}
+
+	if result, err := r.addServiceMeshMemberRoll(workshop, istioSystemNamespace.Name, istioMembers); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// Create/Update the ServiceMeshControlPlane of a namespace
+func (r *WorkshopReconciler) addServiceMeshControlPlane(workshop *workshopv1.Workshop, namespace string) (reconcile.Result, error) {
+
+	serviceMeshControlPlaneCR, err := maistra.NewServiceMeshControlPlaneCR(workshop, r.Scheme, SERVICE_MESH_CONTROL_PLANE_NAME, namespace)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
 	if err := r.Create(context.TODO(), serviceMeshControlPlaneCR); err != nil && !errors.IsAlreadyExists(err) {
 		return reconcile.Result{}, err
 	} else if err == nil {
-		log.Infof("Created %s Service Mesh Control Plane Custom Resource", serviceMeshControlPlaneCR.Name)
+		log.Infof("Created %s Service Mesh Control Plane Custom Resource in %s namespace", serviceMeshControlPlaneCR.Name, namespace)
+	} else if errors.IsAlreadyExists(err) {
+		serviceMeshControlPlaneCRFound := &maistrav2.ServiceMeshControlPlane{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: serviceMeshControlPlaneCR.Name, Namespace: namespace}, serviceMeshControlPlaneCRFound); err != nil {
+			return reconcile.Result{}, err
+		} else if serviceMeshControlPlaneCRFound.Annotations[kubernetes.ChecksumAnnotation] != serviceMeshControlPlaneCR.Annotations[kubernetes.ChecksumAnnotation] {
+			// Only the fields set by the Workshop are replaced, the other ones being defaulted by the operator
+			patch := client.MergeFrom(serviceMeshControlPlaneCRFound.DeepCopy())
+			if serviceMeshControlPlaneCRFound.Annotations == nil {
+				serviceMeshControlPlaneCRFound.Annotations = map[string]string{}
+			}
+			serviceMeshControlPlaneCRFound.Annotations[kubernetes.ChecksumAnnotation] = serviceMeshControlPlaneCR.Annotations[kubernetes.ChecksumAnnotation]
+			serviceMeshControlPlaneCRFound.Spec.Version = serviceMeshControlPlaneCR.Spec.Version
+			serviceMeshControlPlaneCRFound.Spec.Tracing = serviceMeshControlPlaneCR.Spec.Tracing
+			serviceMeshControlPlaneCRFound.Spec.Policy = serviceMeshControlPlaneCR.Spec.Policy
+			serviceMeshControlPlaneCRFound.Spec.Telemetry = serviceMeshControlPlaneCR.Spec.Telemetry
+			serviceMeshControlPlaneCRFound.Spec.Security = serviceMeshControlPlaneCR.Spec.Security
+			serviceMeshControlPlaneCRFound.Spec.Gateways = serviceMeshControlPlaneCR.Spec.Gateways
+			serviceMeshControlPlaneCRFound.Spec.Addons = serviceMeshControlPlaneCR.Spec.Addons
+			if err := r.Patch(context.TODO(), serviceMeshControlPlaneCRFound, patch); err != nil {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Updated %s Service Mesh Control Plane Custom Resource in %s namespace", serviceMeshControlPlaneCRFound.Name, namespace)
+		}
 	}
 
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// Create/Update the ServiceMeshMemberRoll of a namespace
+func (r *WorkshopReconciler) addServiceMeshMemberRoll(workshop *workshopv1.Workshop, namespace string, members []string) (reconcile.Result, error) {
+
 	serviceMeshMemberRollCR := maistra.NewServiceMeshMemberRollCR(workshop, r.Scheme,
-		SERVICE_MESH_MEMBER_ROLL_NAME, istioSystemNamespace.Name, istioMembers)
+		SERVICE_MESH_MEMBER_ROLL_NAME, namespace, members)
 	if err := r.Create(context.TODO(), serviceMeshMemberRollCR); err != nil && !errors.IsAlreadyExists(err) {
 		return reconcile.Result{}, err
 	} else if err == nil {
-		log.Infof("Created %s Custom Resource", serviceMeshMemberRollCR.Name)
+		log.Infof("Created %s Service Mesh Member Roll Custom Resource in %s namespace", serviceMeshMemberRollCR.Name, namespace)
 	} else if errors.IsAlreadyExists(err) {
 		serviceMeshMemberRollCRFound := &maistrav1.ServiceMeshMemberRoll{}
-		if err := r.Get(context.TODO(), types.NamespacedName{Name: serviceMeshMemberRollCR.Name, Namespace: istioSystemNamespace.Name}, serviceMeshMemberRollCRFound); err != nil {
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: serviceMeshMemberRollCR.Name, Namespace: namespace}, serviceMeshMemberRollCRFound); err != nil {
 			return reconcile.Result{}, err
 		} else if err == nil {
-			if !reflect.DeepEqual(istioMembers, serviceMeshMemberRollCRFound.Spec.Members) {
-				serviceMeshMemberRollCRFound.Spec.Members = istioMembers
+			if !reflect.DeepEqual(members, serviceMeshMemberRollCRFound.Spec.Members) {
+				serviceMeshMemberRollCRFound.Spec.Members = members
 				if err := r.Update(context.TODO(), serviceMeshMemberRollCRFound); err != nil {
 					return reconcile.Result{}, err
 				}
-				log.Infof("Updated %s Service Mesh Member Roll Custom Resource", serviceMeshMemberRollCRFound.Name)
+				log.Infof("Updated %s Service Mesh Member Roll Custom Resource in %s namespace", serviceMeshMemberRollCRFound.Name, namespace)
 			}
 		}
 	}
+
 	//Success
 	return reconcile.Result{}, nil
 }
@@ -288,11 +353,22 @@ func (r *WorkshopReconciler) addKialiOperator(workshop *workshopv1.Workshop) (re
 
 func (r *WorkshopReconciler) deleteServiceMeshService(workshop *workshopv1.Workshop, userID int) (reconcile.Result, error) {
 
+	if isServiceMeshIstio(workshop) {
+		return r.deleteIstio(workshop)
+	}
+
 	servicemeshCSV, JaegerCSV, kialiCSV, err := r.getCSV(workshop)
 	if err != nil {
 		log.Error("Failed to get ClusterServiceVersion")
 	}
 
+	// The control planes of the users are deleted while the operator can still clean up their members
+	if isServiceMeshPerUser(workshop) {
+		if result, err := r.deleteServiceMeshUsers(workshop, 0); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
+
 	if result, err := r.deleteServiceMesh(workshop, userID); util.IsRequeued(result, err) {
 		return result, err
 	}
@@ -307,16 +383,20 @@ func (r *WorkshopReconciler) deleteServiceMeshService(workshop *workshopv1.Works
 		return result, err
 	}
 
-	if result, err := r.deleteIstioSystemNamespace(workshop); util.IsRequeued(result, err) {
-		return result, err
+	if !isServiceMeshPerUser(workshop) {
+		if result, err := r.deleteIstioSystemNamespace(workshop); util.IsRequeued(result, err) {
+			return result, err
+		}
 	}
 
 	if result, err := r.deleteElasticSearchOperator(workshop); util.IsRequeued(result, err) {
 		return result, err
 	}
 
-	if result, err := r.PatchIstioProject(workshop); util.IsRequeued(result, err) {
-		return result, err
+	if !isServiceMeshPerUser(workshop) {
+		if result, err := r.PatchIstioProject(workshop); util.IsRequeued(result, err) {
+			return result, err
+		}
 	}
 
 	return reconcile.Result{}, nil
@@ -356,47 +436,55 @@ func (r *WorkshopReconciler) deleteServiceMesh(workshop *workshopv1.Workshop, us
 	jaegerRole := kubernetes.NewRole(workshop, r.Scheme,
 		JAEGER_ROLE_NAME, JAEGER_ROLE_NAMESPACE_NAME, istioLabels, kubernetes.JaegerUserRules())
 
-	serviceMeshMemberRollCR := maistra.NewServiceMeshMemberRollCR(workshop, r.Scheme,
-		SERVICE_MESH_MEMBER_ROLL_NAME, ISTIO_NAMESPACE_NAME, istioMembers)
-	// Delete Service MeshMember Roll Custom Resource
-	if err := r.Delete(context.TODO(), serviceMeshMemberRollCR); err != nil {
-		return reconcile.Result{}, err
-	}
-	log.Infof("Deleted %s Service MeshMember Roll Custom Resource", serviceMeshMemberRollCR.Name)
+	// The shared control plane is only created without the perUser tenancy
+	if !isServiceMeshPerUser(workshop) {
+		serviceMeshMemberRollCR := maistra.NewServiceMeshMemberRollCR(workshop, r.Scheme,
+			SERVICE_MESH_MEMBER_ROLL_NAME, ISTIO_NAMESPACE_NAME, istioMembers)
+		// Delete Service MeshMember Roll Custom Resource
+		if err := r.Delete(context.TODO(), serviceMeshMemberRollCR); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s Service MeshMember Roll Custom Resource", serviceMeshMemberRollCR.Name)
 
-	serviceMeshControlPlaneCR := maistra.NewServiceMeshControlPlaneCR(workshop, r.Scheme, SERVICE_MESH_CONTROL_PLANE_NAME, ISTIO_NAMESPACE_NAME)
-	// Delete Service Mesh Control Plane Custom Resource
-	if err := r.Delete(context.TODO(), serviceMeshControlPlaneCR); err != nil {
-		return reconcile.Result{}, err
-	}
-	log.Infof("Deleted %s Service Mesh Control Plane Custom Resource", serviceMeshControlPlaneCR.Name)
+		serviceMeshControlPlaneCR := &maistrav2.ServiceMeshControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      SERVICE_MESH_CONTROL_PLANE_NAME,
+				Namespace: ISTIO_NAMESPACE_NAME,
+			},
+		}
+		// Delete Service Mesh Control Plane Custom Resource
+		if err := r.Delete(context.TODO(), serviceMeshControlPlaneCR); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s Service Mesh Control Plane Custom Resource", serviceMeshControlPlaneCR.Name)
 
-	meshUserRoleBinding := kubernetes.NewRoleBindingUsers(workshop, r.Scheme,
-		SERVICE_MESH_ROLE_BINDING_NAME, SERVICE_MESH_ROLE_BINDING_NAMESPACE_NAME, istioLabels, istioUsers, SERVICE_MESH_ROLE_NAME, SERVICE_MESH_ROLE_KIND_NAME)
-	// Delete RoleBinding
-	if err := r.Delete(context.TODO(), meshUserRoleBinding); err != nil {
-		return reconcile.Result{}, err
-	}
-	log.Infof("Deleted %s Role Binding", meshUserRoleBinding.Name)
+		meshUserRoleBinding := kubernetes.NewRoleBindingUsers(workshop, r.Scheme,
+			SERVICE_MESH_ROLE_BINDING_NAME, SERVICE_MESH_ROLE_BINDING_NAMESPACE_NAME, istioLabels, istioUsers, SERVICE_MESH_ROLE_NAME, SERVICE_MESH_ROLE_KIND_NAME)
+		// Delete RoleBinding
+		if err := r.Delete(context.TODO(), meshUserRoleBinding); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s Role Binding", meshUserRoleBinding.Name)
 
-	jaegerRoleBinding := kubernetes.NewRoleBindingUsers(workshop, r.Scheme,
-		JAEGER_ROLE_BINDING_NAME, JAEGER_ROLE_BINDING_NAMESPACE_NAME, istioLabels, istioUsers, jaegerRole.Name, JAEGER_ROLE_KIND_NAME)
-	// Delete RoleBinding
-	if err := r.Delete(context.TODO(), jaegerRoleBinding); err != nil {
-		return reconcile.Result{}, err
-	}
-	log.Infof("Deleted %s Role Binding", jaegerRoleBinding.Name)
+		jaegerRoleBinding := kubernetes.NewRoleBindingUsers(workshop, r.Scheme,
+			JAEGER_ROLE_BINDING_NAME, JAEGER_ROLE_BINDING_NAMESPACE_NAME, istioLabels, istioUsers, jaegerRole.Name, JAEGER_ROLE_KIND_NAME)
+		// Delete RoleBinding
+		if err := r.Delete(context.TODO(), jaegerRoleBinding); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s Role Binding", jaegerRoleBinding.Name)
 
-	// Delete Role
-	if err := r.Delete(context.TODO(), jaegerRole); err != nil {
-		return reconcile.Result{}, err
+		// Delete Role
+		if err := r.Delete(context.TODO(), jaegerRole); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s Role", jaegerRole.Name)
 	}
-	log.Infof("Deleted %s Role", jaegerRole.Name)
 
 	subscription := kubernetes.NewRedHatSubscription(workshop, r.Scheme, SERVICE_MESH_SUBSCRIPTION_NAME, SERVICE_MESH_SUBSCRIPTION_NAMESPACE_NAME,
 		SERVICE_MESH_SUBSCRIPTION_PACKAGE_NAME, channel, clusterserviceversion)
 	// Delete Subscription
-	if err := r.Delete(context.TODO(), subscription); err != nil {
+	if err := r.Delete(context.TODO(), subscription); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s Subscription", subscription.Name)
@@ -409,7 +497,7 @@ func (r *WorkshopReconciler) deleteServiceMesh(workshop *workshopv1.Workshop, us
 	}
 
 	// Delete ValidatingWebhookConfiguration
-	if err := r.Delete(context.TODO(), vwc); err != nil {
+	if err := r.Delete(context.TODO(), vwc); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("deleted %s ValidatingWebhookConfiguration", vwc.Name)
@@ -421,7 +509,7 @@ func (r *WorkshopReconciler) deleteServiceMesh(workshop *workshopv1.Workshop, us
 		},
 	}
 	// Delete MutatingWebhookConfiguration
-	if err := r.Delete(context.TODO(), mwc); err != nil {
+	if err := r.Delete(context.TODO(), mwc); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("deleted %s MutatingWebhookConfiguration", mwc.Name)
@@ -439,7 +527,7 @@ func (r *WorkshopReconciler) deleteKialiSubscription(workshop *workshopv1.Worksh
 	subscription := kubernetes.NewRedHatSubscription(workshop, r.Scheme, KIALI_SUBSCRIPTION_NAME, KIALI_SUBSCRIPTION_NAMESPACE_NAME,
 		KIALI_SUBSCRIPTION_PACKAGE_NAME, channel, clusterserviceversion)
 	// Delete Subscription
-	if err := r.Delete(context.TODO(), subscription); err != nil {
+	if err := r.Delete(context.TODO(), subscription); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s Subscription", subscription.Name)
@@ -456,7 +544,7 @@ func (r *WorkshopReconciler) deleteJaegerSubscription(workshop *workshopv1.Works
 	subscription := kubernetes.NewRedHatSubscription(workshop, r.Scheme, JAEGER_SUBSCRIPTION_NAME, JAEGER_SUBSCRIPTION_NAMESPACE_NAME,
 		JAEGER_SUBSCRIPTION_PACKAGE_NAME, channel, clusterserviceversion)
 	// Delete Subscription
-	if err := r.Delete(context.TODO(), subscription); err != nil {
+	if err := r.Delete(context.TODO(), subscription); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s Subscription", subscription.Name)
@@ -475,14 +563,14 @@ func (r *WorkshopReconciler) deleteElasticSearchOperator(workshop *workshopv1.Wo
 	subscription := kubernetes.NewRedHatSubscription(workshop, r.Scheme, subcriptionName, ELASTICSEARCH_SUBSCRIPTION_NAMESPACE_NAME,
 		ELASTICSEARCH_SUBSCRIPTION_PACKAGE_NAME, channel, clusterserviceversion)
 	// Delete Subscription
-	if err := r.Delete(context.TODO(), subscription); err != nil {
+	if err := r.Delete(context.TODO(), subscription); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s Subscription", subscription.Name)
 
 	redhatOperatorsNamespace := kubernetes.NewNamespace(workshop, r.Scheme, OPERATOR_REDHAT_NAMESPACE_NAME)
 	// Delete Namespace
-	if err := r.Delete(context.TODO(), redhatOperatorsNamespace); err != nil {
+	if err := r.Delete(context.TODO(), redhatOperatorsNamespace); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s Namespace", redhatOperatorsNamespace.Name)
@@ -496,7 +584,7 @@ func (r *WorkshopReconciler) deleteIstioSystemNamespace(workshop *workshopv1.Wor
 
 	istioSystemNamespace := kubernetes.NewNamespace(workshop, r.Scheme, ISTIO_NAMESPACE_NAME)
 	// Delete Namespace
-	if err := r.Delete(context.TODO(), istioSystemNamespace); err != nil {
+	if err := r.Delete(context.TODO(), istioSystemNamespace); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s Namespace", istioSystemNamespace.Name)
@@ -532,23 +620,22 @@ func (r *WorkshopReconciler) getCSV(workshop *workshopv1.Workshop) (string, stri
 // delete CSV of servicemesh, jaeger, kiali
 func (r *WorkshopReconciler) deleteCSV(workshop *workshopv1.Workshop, servicemeshCSV string, kialiCSV string, JaegerCSV string) (reconcile.Result, error) {
 
-	servicemeshOperatorCSV := kubernetes.NewRedHatClusterServiceVersion(workshop, r.Scheme, servicemeshCSV, SERVICE_MESH_SUBSCRIPTION_NAMESPACE_NAME)
-	if err := r.Delete(context.TODO(), servicemeshOperatorCSV); err != nil {
-		return reconcile.Result{}, err
+	operatorCSVs := map[string]string{
+		servicemeshCSV: SERVICE_MESH_SUBSCRIPTION_NAMESPACE_NAME,
+		kialiCSV:       KIALI_SUBSCRIPTION_NAMESPACE_NAME,
+		JaegerCSV:      JAEGER_SUBSCRIPTION_NAMESPACE_NAME,
 	}
-	log.Infof("Deleted %s ClusterServiceVersion", servicemeshOperatorCSV.Name)
-
-	kialiOperatorCSV := kubernetes.NewRedHatClusterServiceVersion(workshop, r.Scheme, kialiCSV, KIALI_SUBSCRIPTION_NAMESPACE_NAME)
-	if err := r.Delete(context.TODO(), kialiOperatorCSV); err != nil {
-		return reconcile.Result{}, err
-	}
-	log.Infof("Deleted %s ClusterServiceVersion", kialiOperatorCSV.Name)
-
-	JaegerOperatorCSV := kubernetes.NewRedHatClusterServiceVersion(workshop, r.Scheme, JaegerCSV, JAEGER_SUBSCRIPTION_NAMESPACE_NAME)
-	if err := r.Delete(context.TODO(), JaegerOperatorCSV); err != nil {
-		return reconcile.Result{}, err
+	for csvName, namespace := range operatorCSVs {
+		// The Subscription was not installed
+		if csvName == "" {
+			continue
+		}
+		operatorCSV := kubernetes.NewRedHatClusterServiceVersion(workshop, r.Scheme, csvName, namespace)
+		if err := r.Delete(context.TODO(), operatorCSV); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s ClusterServiceVersion", operatorCSV.Name)
 	}
-	log.Infof("Deleted %s ClusterServiceVersion", JaegerOperatorCSV.Name)
 
 	return reconcile.Result{}, nil
 }
@@ -558,16 +645,21 @@ func (r *WorkshopReconciler) PatchIstioProject(workshop *workshopv1.Workshop) (r
 
 	namespaceFound := kubernetes.NewNamespace(workshop, r.Scheme, ISTIO_NAMESPACE_NAME)
 
-	if err := r.Get(context.TODO(), types.NamespacedName{Name: ISTIO_NAMESPACE_NAME}, namespaceFound); err != nil {
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: ISTIO_NAMESPACE_NAME}, namespaceFound); err != nil && errors.IsNotFound(err) {
+		return reconcile.Result{}, nil
+	} else if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	if namespaceFound.Spec.Finalizers[0] == "kubernetes" {
-		servicemeshcontrolplanes := &maistrav2.ServiceMeshControlPlane{}
-		if err := r.Get(context.TODO(), types.NamespacedName{Name: SERVICE_MESH_CONTROL_PLANE_NAME, Namespace: ISTIO_NAMESPACE_NAME}, servicemeshcontrolplanes); err != nil {
-			return reconcile.Result{}, err
-		}
+	// Only a namespace still finalized by Kubernetes waits for the finalizers of its Custom Resources
+	if len(namespaceFound.Spec.Finalizers) == 0 || namespaceFound.Spec.Finalizers[0] != "kubernetes" {
+		return reconcile.Result{}, nil
+	}
 
+	servicemeshcontrolplanes := &maistrav2.ServiceMeshControlPlane{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: SERVICE_MESH_CONTROL_PLANE_NAME, Namespace: ISTIO_NAMESPACE_NAME}, servicemeshcontrolplanes); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
 		patch := client.MergeFrom(servicemeshcontrolplanes.DeepCopy())
 		servicemeshcontrolplanes.Finalizers = nil
 		if err := r.Patch(context.TODO(), servicemeshcontrolplanes, patch); err != nil {
@@ -576,12 +668,11 @@ func (r *WorkshopReconciler) PatchIstioProject(workshop *workshopv1.Workshop) (r
 		log.Infof("patched %s ServiceMeshControlPlane", servicemeshcontrolplanes.Name)
 	}
 
-	if namespaceFound.Spec.Finalizers[0] == "kubernetes" {
-		kialiFound := &kiali.Kiali{}
-		if err := r.Get(context.TODO(), types.NamespacedName{Name: KIALI_NAME, Namespace: ISTIO_NAMESPACE_NAME}, kialiFound); err != nil {
-			return reconcile.Result{}, err
-		}
-
+	// Kiali is not installed when its addon is disabled
+	kialiFound := &kiali.Kiali{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: KIALI_NAME, Namespace: ISTIO_NAMESPACE_NAME}, kialiFound); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
 		patch := client.MergeFrom(kialiFound.DeepCopy())
 		kialiFound.Finalizers = nil
 		if err := r.Patch(context.TODO(), kialiFound, patch); err != nil {
@@ -590,12 +681,10 @@ func (r *WorkshopReconciler) PatchIstioProject(workshop *workshopv1.Workshop) (r
 		log.Infof("patched %s kiali", kialiFound.Name)
 	}
 
-	if namespaceFound.Spec.Finalizers[0] == "kubernetes" {
-		serviceMeshMemberRoll := &maistrav1.ServiceMeshMemberRoll{}
-		if err := r.Get(context.TODO(), types.NamespacedName{Name: SERVICE_MESH_MEMBER_ROLL_NAME, Namespace: ISTIO_NAMESPACE_NAME}, serviceMeshMemberRoll); err != nil {
-			return reconcile.Result{}, err
-		}
-
+	serviceMeshMemberRoll := &maistrav1.ServiceMeshMemberRoll{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: SERVICE_MESH_MEMBER_ROLL_NAME, Namespace: ISTIO_NAMESPACE_NAME}, serviceMeshMemberRoll); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
 		patch := client.MergeFrom(serviceMeshMemberRoll.DeepCopy())
 		serviceMeshMemberRoll.Finalizers = nil
 		if err := r.Patch(context.TODO(), serviceMeshMemberRoll, patch); err != nil {
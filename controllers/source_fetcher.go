@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"context"
+	"net/url"
+
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/git"
+	"github.com/stakater/workshop-operator/common/source"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// newSourceFetcher returns the fetcher of the files of the workshop source. The repositories of the bundled Gitea
+// are read through its service.
+func (r *WorkshopReconciler) newSourceFetcher(workshop *workshopv1.Workshop, appsHostnameSuffix string) (source.Fetcher, error) {
+
+	spec := workshop.Spec.Source
+	provider := source.ProviderName(spec)
+
+	if provider == source.ProviderConfigMap {
+		configMapFound := &corev1.ConfigMap{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: spec.ConfigMapName, Namespace: workshop.Namespace}, configMapFound); err != nil {
+			return nil, err
+		}
+		return source.NewConfigMapFetcher(configMapFound.Data), nil
+	}
+
+	token := ""
+	if spec.CredentialsSecretName != "" {
+		credentialsSecretFound := &corev1.Secret{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: spec.CredentialsSecretName, Namespace: workshop.Namespace}, credentialsSecretFound); err != nil {
+			return nil, err
+		}
+		token = string(credentialsSecretFound.Data[GITCREDENTIALSTOKENKEY])
+	}
+
+	if git.ProviderName(workshop) == git.ProviderGitea && workshop.Spec.Infrastructure.Gitea.Enabled {
+		giteaURL, err := url.Parse(gitServerURL(workshop, appsHostnameSuffix))
+		if err != nil {
+			return nil, err
+		}
+		sourceURL, err := url.Parse(spec.GitURL)
+		if err == nil && sourceURL.Host == giteaURL.Host {
+			_, repository, err := source.ParseRepository(spec.GitURL)
+			if err != nil {
+				return nil, err
+			}
+			return source.NewGiteaFetcher(gitInternalURL(workshop), repository, spec.GitBranch, token, nil), nil
+		}
+	}
+
+	// The sources served by routes of the cluster are signed by the ingress CA, only the system pool being used
+	// without it
+	rootCAs, err := r.ingressRootCAs()
+	if errors.IsNotFound(err) {
+		rootCAs = nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return source.NewFetcher(spec, provider, token, rootCAs)
+}
@@ -0,0 +1,257 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+
+	oauthv1 "github.com/openshift/api/oauth/v1"
+	"github.com/prometheus/common/log"
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/kubernetes"
+	"github.com/stakater/workshop-operator/common/portal"
+	"github.com/stakater/workshop-operator/common/util"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// operatorPodLabels are the labels of the pods of the operator in its manifests
+var operatorPodLabels = map[string]string{"control-plane": "controller-manager"}
+
+const (
+	SSO_PROXY_DEFAULT_IMAGE_NAME   = "quay.io/openshift/origin-oauth-proxy"
+	SSO_PROXY_DEFAULT_IMAGE_TAG    = "4.8"
+	SSO_PROXY_SECRET_SUFFIX        = "-secrets"
+	SSO_BACKEND_POLICY_SUFFIX      = "-sso-only"
+	SERVICE_ACCOUNT_NAMESPACE_FILE = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+	CLUSTER_NETWORK_NAME           = "cluster"
+	DEFAULT_CLUSTER_NETWORK_CIDR   = "10.128.0.0/14"
+)
+
+// addSSOProxy deploys an OAuth proxy authenticating the users with their OpenShift identity in front of upstream,
+// registered as an OAuthClient redirecting to host. Requests matching skipAuthRegex are passed through
+// unauthenticated, for the clients using their own credentials, and extraArgs are appended to the proxy arguments.
+func (r *WorkshopReconciler) addSSOProxy(workshop *workshopv1.Workshop, name string, namespace string,
+	upstream string, host string, skipAuthRegex []string, extraArgs []string) (reconcile.Result, error) {
+
+	labels := ssoProxyLabels(name)
+	clientID := ssoClientID(name, namespace)
+
+	// Create Secret, generated once
+	secretName := name + SSO_PROXY_SECRET_SUFFIX
+	secretFound := &corev1.Secret{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: namespace}, secretFound); err != nil && errors.IsNotFound(err) {
+		clientSecret, err := util.GeneratePassword(32)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		cookieSecret, err := util.GeneratePassword(32)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		secret := kubernetes.NewStringDataSecret(workshop, r.Scheme, secretName, namespace, labels,
+			map[string]string{"client-secret": clientSecret, "cookie-secret": cookieSecret})
+		if err := r.Create(context.TODO(), secret); err != nil {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Created %s Secret", secret.Name)
+		return reconcile.Result{Requeue: true}, nil
+	} else if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Create/Update OAuthClient
+	oauthClient := kubernetes.NewOAuthClient(workshop, r.Scheme, clientID, labels,
+		string(secretFound.Data["client-secret"]), []string{"https://" + host})
+	if err := r.Create(context.TODO(), oauthClient); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s OAuth Client", oauthClient.Name)
+	} else if errors.IsAlreadyExists(err) {
+		oauthClientFound := &oauthv1.OAuthClient{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: oauthClient.Name}, oauthClientFound); err != nil {
+			return reconcile.Result{}, err
+		} else if oauthClientFound.Secret != oauthClient.Secret || !reflect.DeepEqual(oauthClientFound.RedirectURIs, oauthClient.RedirectURIs) {
+			oauthClientFound.Secret = oauthClient.Secret
+			oauthClientFound.RedirectURIs = oauthClient.RedirectURIs
+			if err := r.Update(context.TODO(), oauthClientFound); err != nil {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Updated %s OAuth Client", oauthClientFound.Name)
+		}
+	}
+
+	// Deploy/Update OAuth Proxy
+	image := imageOrDefault(workshop.Spec.Infrastructure.SSO.ProxyImage, SSO_PROXY_DEFAULT_IMAGE_NAME, SSO_PROXY_DEFAULT_IMAGE_TAG)
+	dep := kubernetes.NewOAuthProxyDeployment(workshop, r.Scheme, name, namespace, labels, image, portal.Image(workshop),
+		clientID, secretName, upstream, skipAuthRegex, extraArgs)
+	if err := r.Create(context.TODO(), dep); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Deployment", dep.Name)
+	} else if errors.IsAlreadyExists(err) {
+		deploymentFound := &appsv1.Deployment{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: dep.Name, Namespace: namespace}, deploymentFound); err != nil {
+			return reconcile.Result{}, err
+		}
+		if ssoProxyContainersChanged(dep.Spec.Template.Spec.Containers, deploymentFound.Spec.Template.Spec.Containers) ||
+			!metav1.IsControlledBy(deploymentFound, workshop) {
+			deploymentFound.Spec = dep.Spec
+			deploymentFound.OwnerReferences = dep.OwnerReferences
+			if err := r.Update(context.TODO(), deploymentFound); err != nil {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Updated %s Deployment", dep.Name)
+		}
+	}
+
+	// Create Service
+	service := kubernetes.NewService(workshop, r.Scheme, name, namespace, labels, []string{"http"}, []int32{kubernetes.OAuthProxyPort})
+	if err := r.Create(context.TODO(), service); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Service", service.Name)
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// deleteSSOProxy deletes the OAuthClient of the proxy, the other resources being deleted with its namespace
+func (r *WorkshopReconciler) deleteSSOProxy(workshop *workshopv1.Workshop, name string, namespace string) (reconcile.Result, error) {
+
+	oauthClient := &oauthv1.OAuthClient{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: ssoClientID(name, namespace)}, oauthClient); err == nil {
+		if err := r.Delete(context.TODO(), oauthClient); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		log.Infof("Deleted %s OAuth Client", oauthClient.Name)
+	} else if !errors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// addSSOBackendNetworkPolicy only lets the proxy, the from pods and the operator reach the ports of the backend
+// behind the proxy, its openPorts staying reachable from everywhere. For a backend trusting the user header of the
+// proxy from any pod of the cluster, this policy is the trust boundary of the header.
+func (r *WorkshopReconciler) addSSOBackendNetworkPolicy(workshop *workshopv1.Workshop, proxyName string, namespace string,
+	podLabels map[string]string, ports []int32, from []map[string]string, openPorts []int32) (reconcile.Result, error) {
+
+	from = append([]map[string]string{ssoProxyLabels(proxyName)}, from...)
+	networkPolicy := kubernetes.NewNetworkPolicy(workshop, r.Scheme, proxyName+SSO_BACKEND_POLICY_SUFFIX, namespace,
+		ssoProxyLabels(proxyName), podLabels, ports, from, operatorNamespaceLabels(), operatorPodLabels, openPorts)
+	if err := r.Create(context.TODO(), networkPolicy); err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Created %s Network Policy", networkPolicy.Name)
+	} else if errors.IsAlreadyExists(err) {
+		networkPolicyFound := &networkingv1.NetworkPolicy{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: networkPolicy.Name, Namespace: namespace}, networkPolicyFound); err != nil {
+			return reconcile.Result{}, err
+		} else if !reflect.DeepEqual(networkPolicy.Spec, networkPolicyFound.Spec) {
+			networkPolicyFound.Spec = networkPolicy.Spec
+			if err := r.Update(context.TODO(), networkPolicyFound); err != nil {
+				return reconcile.Result{}, err
+			}
+			log.Infof("Updated %s Network Policy", networkPolicyFound.Name)
+		}
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// deleteSSOBackendNetworkPolicy opens the backend again once SSO is disabled
+func (r *WorkshopReconciler) deleteSSOBackendNetworkPolicy(proxyName string, namespace string) (reconcile.Result, error) {
+
+	networkPolicy := &networkingv1.NetworkPolicy{}
+	networkPolicy.Name = proxyName + SSO_BACKEND_POLICY_SUFFIX
+	networkPolicy.Namespace = namespace
+	if err := r.Delete(context.TODO(), networkPolicy); err != nil && !errors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	} else if err == nil {
+		log.Infof("Deleted %s Network Policy", networkPolicy.Name)
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+// operatorNamespaceLabels returns the labels of the namespace of the operator, which calls the APIs of the backends
+// directly, or none when the operator runs outside of the cluster. Only the operator pods of the namespace are let
+// through, as it may be shared with other operators.
+func operatorNamespaceLabels() []map[string]string {
+	namespace, err := ioutil.ReadFile(SERVICE_ACCOUNT_NAMESPACE_FILE)
+	if err != nil {
+		return nil
+	}
+	return []map[string]string{{"kubernetes.io/metadata.name": strings.TrimSpace(string(namespace))}}
+}
+
+// clusterNetworkCIDRs returns the CIDRs of the pods of the cluster, the proxies being the only pods allowed to send
+// a user header to the backends by their NetworkPolicy
+func (r *WorkshopReconciler) clusterNetworkCIDRs() ([]string, error) {
+	network := &unstructured.Unstructured{}
+	network.SetGroupVersionKind(schema.GroupVersionKind{Group: "config.openshift.io", Version: "v1", Kind: "Network"})
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: CLUSTER_NETWORK_NAME}, network); err != nil {
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return []string{DEFAULT_CLUSTER_NETWORK_CIDR}, nil
+		}
+		return nil, err
+	}
+
+	cidrs := []string{}
+	clusterNetworks, _, _ := unstructured.NestedSlice(network.Object, "status", "clusterNetwork")
+	for _, clusterNetwork := range clusterNetworks {
+		if entry, ok := clusterNetwork.(map[string]interface{}); ok {
+			if cidr, ok := entry["cidr"].(string); ok && cidr != "" {
+				cidrs = append(cidrs, cidr)
+			}
+		}
+	}
+	if len(cidrs) == 0 {
+		return []string{DEFAULT_CLUSTER_NETWORK_CIDR}, nil
+	}
+	return cidrs, nil
+}
+
+// ssoProxyContainersChanged returns true if the images or the arguments of the proxy or of its header filter changed,
+// the previous versions having no filter
+func ssoProxyContainersChanged(containers []corev1.Container, containersFound []corev1.Container) bool {
+	if len(containers) != len(containersFound) {
+		return true
+	}
+	for i, container := range containers {
+		if container.Image != containersFound[i].Image || !reflect.DeepEqual(container.Args, containersFound[i].Args) {
+			return true
+		}
+	}
+	return false
+}
+
+// ssoProxyLabels returns the labels of the pods of a proxy
+func ssoProxyLabels(name string) map[string]string {
+	return map[string]string{
+		"app":                       name,
+		"app.kubernetes.io/name":    name,
+		"app.kubernetes.io/part-of": "sso",
+	}
+}
+
+// ssoClientID returns the name of the OAuthClient of a proxy, OAuthClients being cluster scoped
+func ssoClientID(name string, namespace string) string {
+	return fmt.Sprintf("%s-%s", namespace, name)
+}
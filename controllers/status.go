@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"context"
+
+	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+// updateStatus applies mutate to the latest status of the workshop and persists it
+func (r *WorkshopReconciler) updateStatus(workshop *workshopv1.Workshop, mutate func(status *workshopv1.WorkshopStatus)) error {
+	mutate(&workshop.Status)
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		found := &workshopv1.Workshop{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: workshop.Name, Namespace: workshop.Namespace}, found); err != nil {
+			return err
+		}
+		mutate(&found.Status)
+		if err := r.Status().Update(context.TODO(), found); err != nil {
+			return err
+		}
+		workshop.ResourceVersion = found.ResourceVersion
+		return nil
+	})
+}
+
+// COMPONENT_INSTALLED is the status of a component once enabled
+const COMPONENT_INSTALLED = "Installed"
+
+// markInstalledComponents sets the status of the enabled components to Installed, and returns true if it changed
+func markInstalledComponents(status *workshopv1.WorkshopStatus, infrastructure workshopv1.InfrastructureSpec) bool {
+	changed := false
+	mark := func(component *string, enabled bool) {
+		if enabled && *component != COMPONENT_INSTALLED {
+			*component = COMPONENT_INSTALLED
+			changed = true
+		}
+	}
+	mark(&status.Bookbag, infrastructure.Guide.Bookbag.Enabled)
+	mark(&status.CertManager, infrastructure.CertManager.Enabled)
+	mark(&status.CodeReadyWorkspace, infrastructure.CodeReadyWorkspace.Enabled)
+	mark(&status.DevSpaces, infrastructure.DevSpaces.Enabled)
+	mark(&status.Gitea, infrastructure.Gitea.Enabled)
+	mark(&status.GitOps, infrastructure.GitOps.Enabled)
+	mark(&status.Kafka, infrastructure.Kafka.Enabled)
+	mark(&status.Nexus, infrastructure.Nexus.Enabled)
+	mark(&status.Pipeline, infrastructure.Pipeline.Enabled)
+	mark(&status.Project, infrastructure.Project.Enabled)
+	mark(&status.ServiceMesh, infrastructure.ServiceMesh.Enabled)
+	mark(&status.Serverless, infrastructure.Serverless.Enabled)
+	mark(&status.Vault, infrastructure.Vault.Enabled)
+	return changed
+}
+
+// updateInstalledComponents records the enabled components before installing them, to delete them with the
+// workshop even if they are disabled later
+func (r *WorkshopReconciler) updateInstalledComponents(workshop *workshopv1.Workshop) error {
+	infrastructure := workshop.Spec.Infrastructure
+	if !markInstalledComponents(workshop.Status.DeepCopy(), infrastructure) {
+		return nil
+	}
+	return r.updateStatus(workshop, func(status *workshopv1.WorkshopStatus) {
+		markInstalledComponents(status, infrastructure)
+	})
+}
+
+// isInstalled returns true if the component was installed, or is enabled and may have been partly installed
+func isInstalled(status string, enabled bool) bool {
+	return status == COMPONENT_INSTALLED || enabled
+}
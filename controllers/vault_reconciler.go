@@ -302,21 +302,21 @@ func (r *WorkshopReconciler) deleteVaultServer(workshop *workshopv1.Workshop) (r
 
 	stateful := vault.NewStatefulSet(workshop, r.Scheme, VAULT_STATEFULSET_NAME, VAULT_NAMESPACE_NAME, VaultServerLabels)
 	// Delete stateful
-	if err := r.Delete(context.TODO(), stateful); err != nil {
+	if err := r.Delete(context.TODO(), stateful); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s VaultServer stateful", stateful.Name)
 
 	service := kubernetes.NewService(workshop, r.Scheme, VAULT_SERVICE_NAME, VAULT_NAMESPACE_NAME, VaultServerLabels, []string{"http", "internal"}, []int32{8200, 8201})
 	// Delete Service
-	if err := r.Delete(context.TODO(), service); err != nil {
+	if err := r.Delete(context.TODO(), service); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s VaultServer Service", service.Name)
 
 	internalService := kubernetes.NewService(workshop, r.Scheme, VAULT_INTERNAL_SERVICE_NAME, VAULT_NAMESPACE_NAME, VaultServerLabels, []string{"http", "internal"}, []int32{8200, 8201})
 	// Delete internal Service
-	if err := r.Delete(context.TODO(), internalService); err != nil {
+	if err := r.Delete(context.TODO(), internalService); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s VaultServer internal Service", internalService.Name)
@@ -324,28 +324,28 @@ func (r *WorkshopReconciler) deleteVaultServer(workshop *workshopv1.Workshop) (r
 	clusterRoleBinding := kubernetes.NewClusterRoleBindingSA(workshop, r.Scheme, VAULT_ROLEBINDING_NAME, VAULT_NAMESPACE_NAME,
 		VaultServerLabels, serviceAccount.Name, VAULT_ROLEBINDING_ROLE_NAME, KIND_CLUSTER_ROLE)
 	// Delete ClusterRole Binding
-	if err := r.Delete(context.TODO(), clusterRoleBinding); err != nil {
+	if err := r.Delete(context.TODO(), clusterRoleBinding); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s  VaultServer ClusterRole Binding", clusterRoleBinding.Name)
 
 	vaultSCC := &securityv1.SecurityContextConstraints{}
 	if err := r.Get(context.TODO(), types.NamespacedName{Name: "vault"}, vaultSCC); err == nil {
-		if err := r.Delete(context.TODO(), vaultSCC); err != nil {
+		if err := r.Delete(context.TODO(), vaultSCC); err != nil && !errors.IsNotFound(err) {
 			return reconcile.Result{}, err
 		}
 		log.Infof("Deleted %s vaultSCC ", vaultSCC.Name)
 	}
 
 	// Delete Service Account
-	if err := r.Delete(context.TODO(), serviceAccount); err != nil {
+	if err := r.Delete(context.TODO(), serviceAccount); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s VaultServer Service Account", serviceAccount.Name)
 
 	configMap := kubernetes.NewConfigMap(workshop, r.Scheme, VAULT_CONFIGMAP_NAME, VAULT_NAMESPACE_NAME, VaultServerLabels, ExtraConfigFromValues)
 	// Delete configMap
-	if err := r.Delete(context.TODO(), configMap); err != nil {
+	if err := r.Delete(context.TODO(), configMap); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s VaultServer configMap", configMap.Name)
@@ -367,14 +367,14 @@ func (r *WorkshopReconciler) deleteVaultAgentInjector(workshop *workshopv1.Works
 	mutatingWebhookConfiguration := kubernetes.NewMutatingWebhookConfiguration(workshop, r.Scheme,
 		VAULTAGENT_WEBHOOK_NAME, VaultAgentLabels, webhooks)
 	// Delete AgentInjectorWebHook
-	if err := r.Delete(context.TODO(), mutatingWebhookConfiguration); err != nil {
+	if err := r.Delete(context.TODO(), mutatingWebhookConfiguration); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s VaultAgent Mutating Webhook Configuration ", mutatingWebhookConfiguration.Name)
 
 	ocpDeployment := vault.NewAgentInjectorDeployment(workshop, r.Scheme, VAULTAGENT_DEPLOYMENT_NAME, VAULT_NAMESPACE_NAME, VaultAgentLabels)
 	// Delete Deployment
-	if err := r.Delete(context.TODO(), ocpDeployment); err != nil {
+	if err := r.Delete(context.TODO(), ocpDeployment); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s VaultAgent Deployment ", ocpDeployment.Name)
@@ -382,7 +382,7 @@ func (r *WorkshopReconciler) deleteVaultAgentInjector(workshop *workshopv1.Works
 	service := kubernetes.NewServiceWithTarget(workshop, r.Scheme, VAULTAGENT_SERVICE_NAME, VAULT_NAMESPACE_NAME, VaultAgentLabels,
 		[]string{"http"}, []int32{443}, []int32{8080})
 	// Delete Service
-	if err := r.Delete(context.TODO(), service); err != nil {
+	if err := r.Delete(context.TODO(), service); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s VaultAgent Service ", service.Name)
@@ -390,20 +390,20 @@ func (r *WorkshopReconciler) deleteVaultAgentInjector(workshop *workshopv1.Works
 	clusterRoleBinding := kubernetes.NewClusterRoleBindingSA(workshop, r.Scheme, VAULTAGENT_ROLEBINDING_NAME, VAULT_NAMESPACE_NAME,
 		VaultAgentLabels, VAULTAGENT_SERVICEACCOUNT_NAME, clusterRole.Name, KIND_CLUSTER_ROLE)
 	// Delete Cluster Role Binding
-	if err := r.Delete(context.TODO(), clusterRoleBinding); err != nil {
+	if err := r.Delete(context.TODO(), clusterRoleBinding); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s VaultAgent Cluster Role Binding", clusterRoleBinding.Name)
 
 	// Delete Cluster Role
-	if err := r.Delete(context.TODO(), clusterRole); err != nil {
+	if err := r.Delete(context.TODO(), clusterRole); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s VaultAgent Cluster Role", clusterRole.Name)
 
 	vaultAgentSCC := &securityv1.SecurityContextConstraints{}
 	if err := r.Get(context.TODO(), types.NamespacedName{Name: "vault"}, vaultAgentSCC); err == nil {
-		if err := r.Delete(context.TODO(), vaultAgentSCC); err != nil {
+		if err := r.Delete(context.TODO(), vaultAgentSCC); err != nil && !errors.IsNotFound(err) {
 			return reconcile.Result{}, err
 		}
 		log.Infof("Deleted %s vaultAgentSCC ", vaultAgentSCC.Name)
@@ -411,7 +411,7 @@ func (r *WorkshopReconciler) deleteVaultAgentInjector(workshop *workshopv1.Works
 
 	serviceAccount := kubernetes.NewServiceAccount(workshop, r.Scheme, VAULTAGENT_SERVICEACCOUNT_NAME, VAULT_NAMESPACE_NAME, VaultAgentLabels)
 	// Delete  Service Account
-	if err := r.Delete(context.TODO(), serviceAccount); err != nil {
+	if err := r.Delete(context.TODO(), serviceAccount); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s  VaultAgent Service Account", serviceAccount.Name)
@@ -426,7 +426,7 @@ func (r *WorkshopReconciler) deleteVaultNamespace(workshop *workshopv1.Workshop)
 	log.Infoln("Deleting Namespace")
 	vaultNamespace := kubernetes.NewNamespace(workshop, r.Scheme, VAULT_NAMESPACE_NAME)
 	// Delete Namespace
-	if err := r.Delete(context.TODO(), vaultNamespace); err != nil {
+	if err := r.Delete(context.TODO(), vaultNamespace); err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
 	log.Infof("Deleted %s Namespace", vaultNamespace.Name)
@@ -20,13 +20,20 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 	"github.com/prometheus/common/log"
 
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	workshopv1 "github.com/stakater/workshop-operator/api/v1"
 	"github.com/stakater/workshop-operator/common/util"
@@ -42,6 +49,18 @@ type WorkshopReconciler struct {
 // Finalizer
 const workshopFinalizer = "finalizer.workshop.stakater.com"
 
+// Annotations of the watched objects of other namespaces, locating their workshop
+const (
+	WORKSHOP_NAME_ANNOTATION      = "workshop.stakater.com/workshop-name"
+	WORKSHOP_NAMESPACE_ANNOTATION = "workshop.stakater.com/workshop-namespace"
+)
+
+// Label of the watched objects of other namespaces, filtering the events of the watches
+const (
+	WORKSHOP_MANAGED_BY_LABEL = "app.kubernetes.io/managed-by"
+	WORKSHOP_MANAGED_BY       = "workshop-operator"
+)
+
 // +kubebuilder:rbac:groups=workshop.stakater.com,resources=workshops;workshops/finalizers,verbs=*
 // +kubebuilder:rbac:groups=workshop.stakater.com,resources=workshops/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=workshop.stakater.com,resources=workshops,verbs=get;list;watch;create;update;patch;delete
@@ -49,19 +68,32 @@ const workshopFinalizer = "finalizer.workshop.stakater.com"
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=create;delete
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=deployments/finalizers,verbs=update
+// +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=pods;services;endpoints;persistentvolumeclaims;events;configmaps;secrets;namespaces;serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=pods/exec,verbs=create
+// +kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=oauth.openshift.io,resources=oauthclients,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,verbs=create;list;watch;update;patch;get;delete
 // +kubebuilder:rbac:groups=project.openshift.io,resources=projectrequests,verbs=create
 
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings;clusterroles;clusterrolebindings,verbs=*
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=org.eclipse.che,resources=checlusters,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=workspace.devfile.io,resources=devworkspaces,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=maistra.io,resources=servicemeshcontrolplanes;servicemeshmemberrolls,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=operator.knative.dev,resources=knativeservings;knativeeventings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=eventing.knative.dev,resources=brokers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=kafka.strimzi.io,resources=kafkas;kafkanodepools;kafkatopics;kafkausers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=k8s.cni.cncf.io,resources=network-attachment-definitions,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=config.openshift.io,resources=networks,verbs=get;list;watch
+// +kubebuilder:rbac:groups=security.istio.io,resources=peerauthentications,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations;validatingwebhookconfigurations,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=gpte.opentlc.com,resources=nexus;giteas,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=operators.coreos.com,resources=operatorgroups;subscriptions;clusterserviceversions;installplans,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=argoproj.io,resources=argocds;appprojects,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=argoproj.io,resources=argocds;appprojects;applications;applicationsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=kiali.io,resources=kialis,verbs=get;list;watch;patch
 
 func (r *WorkshopReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
@@ -122,7 +154,10 @@ func (r *WorkshopReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 			if err := r.finalizeWorkshop(reqLogger, workshop); err != nil {
 				return ctrl.Result{}, err
 			}
-			_, _ = r.handleDelete(ctx, req, workshop, users, appsHostnameSuffix, openshiftConsoleURL)
+			// Keep the finalizer until every component is deleted, the deletion resuming on the next reconciliation
+			if result, err := r.handleDelete(ctx, req, workshop, users, appsHostnameSuffix, openshiftConsoleURL); util.IsRequeued(result, err) {
+				return result, err
+			}
 			// Remove workshopFinalizer. Once all finalizers have been
 			// removed, the object will be deleted.
 			controllerutil.RemoveFinalizer(workshop, workshopFinalizer)
@@ -142,6 +177,11 @@ func (r *WorkshopReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 			return ctrl.Result{}, err
 		}
 	}
+	// Record the enabled components, deleted with the workshop even if disabled later
+	if err := r.updateInstalledComponents(workshop); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	//////////////////////////
 	// Portal
 	//////////////////////////
@@ -166,14 +206,28 @@ func (r *WorkshopReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	//////////////////////////
 	// Nexus
 	//////////////////////////
-	if result, err := r.reconcileNexus(workshop); util.IsRequeued(result, err) {
+	if result, err := r.reconcileNexus(workshop, users, appsHostnameSuffix); util.IsRequeued(result, err) {
 		return result, err
 	}
 
 	//////////////////////////
 	// Gitea
 	//////////////////////////
-	if result, err := r.reconcileGitea(workshop, users); util.IsRequeued(result, err) {
+	if result, err := r.reconcileGitea(workshop, users, appsHostnameSuffix); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	//////////////////////////
+	// Git
+	//////////////////////////
+	if result, err := r.reconcileGit(workshop, users); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	//////////////////////////
+	// Image Puller
+	//////////////////////////
+	if result, err := r.reconcileImagePuller(workshop, appsHostnameSuffix); util.IsRequeued(result, err) {
 		return result, err
 	}
 
@@ -201,14 +255,21 @@ func (r *WorkshopReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	//////////////////////////
 	// Service Mesh
 	//////////////////////////
-	if result, err := r.reconcileServiceMesh(workshop, users); util.IsRequeued(result, err) {
+	if result, err := r.reconcileServiceMesh(workshop, users, appsHostnameSuffix); util.IsRequeued(result, err) {
 		return result, err
 	}
 
 	//////////////////////////
 	// Serverless
 	//////////////////////////
-	if result, err := r.reconcileServerless(workshop); util.IsRequeued(result, err) {
+	if result, err := r.reconcileServerless(workshop, users); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	//////////////////////////
+	// Kafka
+	//////////////////////////
+	if result, err := r.reconcileKafka(workshop, users); util.IsRequeued(result, err) {
 		return result, err
 	}
 
@@ -226,59 +287,157 @@ func (r *WorkshopReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return result, err
 	}
 
+	//////////////////////////
+	// Dev Spaces
+	//////////////////////////
+	if result, err := r.reconcileDevSpaces(workshop, users, appsHostnameSuffix); util.IsRequeued(result, err) {
+		return result, err
+	}
+
+	//////////////////////////
+	// Ready
+	//////////////////////////
+	ready := (!workshop.Spec.Infrastructure.ImagePuller.Enabled || workshop.Status.ImagePuller.Phase == IMAGE_PULLER_COMPLETED) &&
+		(!workshop.Spec.Infrastructure.Serverless.Enabled || workshop.Status.Knative.Serving.Ready && workshop.Status.Knative.Eventing.Ready)
+	if ready != workshop.Status.Ready {
+		if err := r.updateStatus(workshop, func(status *workshopv1.WorkshopStatus) {
+			status.Ready = ready
+		}); err != nil {
+			return ctrl.Result{}, err
+		}
+		log.Infof("Workshop %s ready: %t", workshop.Name, ready)
+	}
+
 	return ctrl.Result{}, nil
 }
 
 func (r *WorkshopReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&workshopv1.Workshop{}).
+		Watches(&source.Kind{Type: &batchv1.Job{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(workshopRequests),
+		}, builder.WithPredicates(managedByWorkshop)).
+		Watches(&source.Kind{Type: &appsv1.DaemonSet{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(workshopRequests),
+		}, builder.WithPredicates(managedByWorkshop)).
 		Complete(r)
 }
 
+// managedByWorkshop filters the events of the objects labelled by setWorkshopReference
+var managedByWorkshop = predicate.NewPredicateFuncs(func(meta metav1.Object, object runtime.Object) bool {
+	return meta.GetLabels()[WORKSHOP_MANAGED_BY_LABEL] == WORKSHOP_MANAGED_BY
+})
+
+// setWorkshopReference labels an object of another namespace and annotates it with its workshop, to map its watched
+// events to the reconciliation of the workshop
+func setWorkshopReference(workshop *workshopv1.Workshop, object metav1.Object) {
+	labels := map[string]string{}
+	for key, value := range object.GetLabels() {
+		labels[key] = value
+	}
+	labels[WORKSHOP_MANAGED_BY_LABEL] = WORKSHOP_MANAGED_BY
+	object.SetLabels(labels)
+
+	annotations := map[string]string{}
+	for key, value := range object.GetAnnotations() {
+		annotations[key] = value
+	}
+	annotations[WORKSHOP_NAME_ANNOTATION] = workshop.Name
+	annotations[WORKSHOP_NAMESPACE_ANNOTATION] = workshop.Namespace
+	object.SetAnnotations(annotations)
+}
+
+// workshopRequests maps an annotated object to the reconciliation of its workshop
+func workshopRequests(object handler.MapObject) []reconcile.Request {
+	annotations := object.Meta.GetAnnotations()
+	if annotations[WORKSHOP_NAME_ANNOTATION] == "" {
+		return nil
+	}
+	return []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: annotations[WORKSHOP_NAME_ANNOTATION], Namespace: annotations[WORKSHOP_NAMESPACE_ANNOTATION]}},
+	}
+}
+
 func (r *WorkshopReconciler) handleDelete(ctx context.Context, req ctrl.Request, workshop *workshopv1.Workshop, userID int, appsHostnameSuffix string, openshiftConsoleURL string) (ctrl.Result, error) {
 	log := r.Log.WithValues("workshop", req.NamespacedName)
 	log.Info("Deleting workshop   " + workshop.ObjectMeta.Name)
 
-	if result, err := r.deleteServiceMeshService(workshop, userID); util.IsRequeued(result, err) {
-		return result, err
+	if isInstalled(workshop.Status.Serverless, workshop.Spec.Infrastructure.Serverless.Enabled) {
+		if result, err := r.deleteServerless(workshop, userID); util.IsRequeued(result, err) {
+			return result, err
+		}
 	}
 
-	if result, err := r.deleteBookbag(workshop, userID, appsHostnameSuffix, openshiftConsoleURL); util.IsRequeued(result, err) {
+	if isInstalled(workshop.Status.Kafka, workshop.Spec.Infrastructure.Kafka.Enabled) {
+		if result, err := r.deleteKafka(workshop, userID); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
 
-		return result, err
+	if isInstalled(workshop.Status.ServiceMesh, workshop.Spec.Infrastructure.ServiceMesh.Enabled) {
+		if result, err := r.deleteServiceMeshService(workshop, userID); util.IsRequeued(result, err) {
+			return result, err
+		}
 	}
 
-	if result, err := r.deletePipelines(workshop); util.IsRequeued(result, err) {
+	if isInstalled(workshop.Status.Bookbag, workshop.Spec.Infrastructure.Guide.Bookbag.Enabled) {
+		if result, err := r.deleteBookbag(workshop, userID, appsHostnameSuffix, openshiftConsoleURL); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
 
-		return result, err
+	if isInstalled(workshop.Status.Pipeline, workshop.Spec.Infrastructure.Pipeline.Enabled) {
+		if result, err := r.deletePipelines(workshop); util.IsRequeued(result, err) {
+			return result, err
+		}
 	}
 
-	if result, err := r.deleteGitOps(workshop, userID, appsHostnameSuffix, openshiftConsoleURL); util.IsRequeued(result, err) {
-		return result, err
+	if isInstalled(workshop.Status.GitOps, workshop.Spec.Infrastructure.GitOps.Enabled) {
+		if result, err := r.deleteGitOps(workshop, userID, appsHostnameSuffix, openshiftConsoleURL); util.IsRequeued(result, err) {
+			return result, err
+		}
 	}
 
 	if result, err := r.deleteProject(workshop, userID); util.IsRequeued(result, err) {
 		return result, err
 	}
 
-	if result, err := r.deleteCodeReadyWorkspace(workshop, userID, appsHostnameSuffix); util.IsRequeued(result, err) {
-		return result, err
+	if isInstalled(workshop.Status.CodeReadyWorkspace, workshop.Spec.Infrastructure.CodeReadyWorkspace.Enabled) {
+		if result, err := r.deleteCodeReadyWorkspace(workshop, userID, appsHostnameSuffix); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
+
+	if isInstalled(workshop.Status.DevSpaces, workshop.Spec.Infrastructure.DevSpaces.Enabled) {
+		if result, err := r.deleteDevSpaces(workshop, userID); util.IsRequeued(result, err) {
+			return result, err
+		}
 	}
 
 	if result, err := r.deletePortal(workshop, userID, appsHostnameSuffix, openshiftConsoleURL); util.IsRequeued(result, err) {
 		return result, err
 	}
 
-	if result, err := r.deleteVault(workshop); util.IsRequeued(result, err) {
-		return result, err
+	if isInstalled(workshop.Status.Vault, workshop.Spec.Infrastructure.Vault.Enabled) {
+		if result, err := r.deleteVault(workshop); util.IsRequeued(result, err) {
+			return result, err
+		}
 	}
 
-	if result, err := r.deleteGitea(workshop); util.IsRequeued(result, err) {
+	if result, err := r.deleteImagePuller(workshop); util.IsRequeued(result, err) {
 		return result, err
 	}
 
-	if result, err := r.deleteNexus(workshop); util.IsRequeued(result, err) {
-		return result, err
+	if isInstalled(workshop.Status.Gitea, workshop.Spec.Infrastructure.Gitea.Enabled) {
+		if result, err := r.deleteGitea(workshop); util.IsRequeued(result, err) {
+			return result, err
+		}
+	}
+
+	if isInstalled(workshop.Status.Nexus, workshop.Spec.Infrastructure.Nexus.Enabled) {
+		if result, err := r.deleteNexus(workshop); util.IsRequeued(result, err) {
+			return result, err
+		}
 	}
 
 	return ctrl.Result{}, nil
@@ -26,11 +26,14 @@ import (
 	maistrav1 "github.com/maistra/istio-operator/pkg/apis/maistra/v1"
 	maistrav2 "github.com/maistra/istio-operator/pkg/apis/maistra/v2"
 	workshopv1 "github.com/stakater/workshop-operator/api/v1"
+	"github.com/stakater/workshop-operator/common/argocd"
+	"github.com/stakater/workshop-operator/common/devspaces"
 	"github.com/stakater/workshop-operator/common/gitea"
 	"github.com/stakater/workshop-operator/common/nexus"
 	"github.com/stakater/workshop-operator/controllers"
 
 	kiali "github.com/maistra/istio-operator/pkg/apis/external/kiali/v1alpha1"
+	oauthv1 "github.com/openshift/api/oauth/v1"
 	routev1 "github.com/openshift/api/route/v1"
 	securityv1 "github.com/openshift/api/security/v1"
 	olmv1 "github.com/operator-framework/api/pkg/operators/v1"
@@ -56,10 +59,13 @@ func init() {
 	utilruntime.Must(workshopv1.AddToScheme(scheme))
 
 	utilruntime.Must(routev1.AddToScheme(scheme))
+	utilruntime.Must(oauthv1.AddToScheme(scheme))
 	utilruntime.Must(apiextensionsv1beta1.AddToScheme(scheme))
 	utilruntime.Must(olmv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(olmv1.AddToScheme(scheme))
 
+	utilruntime.Must(argocd.AddToScheme(scheme))
+	utilruntime.Must(devspaces.AddToScheme(scheme))
 	utilruntime.Must(gitea.AddToScheme(scheme))
 	utilruntime.Must(nexus.AddToScheme(scheme))
 	utilruntime.Must(maistrav1.SchemeBuilder.AddToScheme(scheme))